@@ -6,9 +6,13 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/doji-co/agent-builder/internal/adk"
 	"github.com/doji-co/agent-builder/internal/generator"
 	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/naming"
 	"github.com/doji-co/agent-builder/internal/prompt"
+	"github.com/doji-co/agent-builder/internal/spec"
+	"github.com/doji-co/agent-builder/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -19,333 +23,309 @@ var createCmd = &cobra.Command{
 	RunE:  runCreate,
 }
 
+var (
+	adkVersionFlag string
+	specFlag       string
+	saveSpecFlag   string
+	outputDirFlag  string
+
+	answersFlag       string
+	recordAnswersFlag string
+)
+
 func init() {
 	rootCmd.AddCommand(createCmd)
+	createCmd.Flags().StringVar(&adkVersionFlag, "adk-version", adk.Default,
+		fmt.Sprintf("ADK version the generated code targets (%s)", strings.Join(adk.Names(), ", ")))
+	createCmd.Flags().StringVar(&specFlag, "spec", "", "generate from a saved spec or manifest instead of asking questions")
+	createCmd.Flags().StringVar(&saveSpecFlag, "save-spec", "", "save the wizard's answers as a spec file")
+	createCmd.Flags().StringVar(&outputDirFlag, "output-dir", "", "directory to generate into when using --spec (default ./<project name>)")
+	createCmd.Flags().StringVar(&answersFlag, "answers", "", "answer the wizard's questions from an answers file instead of the terminal")
+	createCmd.Flags().StringVar(&recordAnswersFlag, "record-answers", "", "save the wizard's questions and answers to replay with --answers")
 }
 
 func runCreate(cmd *cobra.Command, args []string) error {
-	interactive := prompt.NewInteractive()
-
-	fmt.Println("🤖 Welcome to Agent Builder!")
-
-	projectType, err := interactive.PromptProjectType()
+	adkVersion, err := adk.Lookup(adkVersionFlag)
 	if err != nil {
-		return fmt.Errorf("failed to get project type: %w", err)
+		return err
 	}
 
-	if projectType == "full" {
-		return runCreateFullProject(interactive)
+	if specFlag != "" {
+		return runCreateFromSpec(cmd, adkVersion)
 	}
-	return runCreateSingleAgent(interactive)
-}
-
-func runCreateFullProject(interactive *prompt.Interactive) error {
-	fmt.Println("Let's create your multi-agent system.")
 
-	projectName, err := interactive.PromptProjectName()
-	if err != nil {
-		return fmt.Errorf("failed to get project name: %w", err)
+	var prompter prompt.Prompter = prompt.Survey{}
+	var scripted *prompt.Scripted
+	if answersFlag != "" {
+		answers, err := prompt.LoadAnswers(answersFlag)
+		if err != nil {
+			return err
+		}
+		scripted = prompt.NewScripted(answers)
+		prompter = scripted
+	} else if !ui.IsTerminal(os.Stdin) || !ui.IsTerminal(os.Stdout) {
+		// The wizard reads keys from stdin and redraws its prompts on stdout;
+		// without a terminal survey fails with errors that do not say why.
+		return fmt.Errorf("no terminal: create asks its questions interactively; use --spec <file> to generate from a spec or --answers <file> to replay a session")
 	}
 
-	pattern, err := interactive.PromptOrchestrationPattern()
-	if err != nil {
-		return fmt.Errorf("failed to get orchestration pattern: %w", err)
+	var recorder *prompt.Recorder
+	if recordAnswersFlag != "" {
+		recorder = &prompt.Recorder{Prompter: prompter}
+		prompter = recorder
 	}
 
-	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println("📋 ORCHESTRATOR CONFIGURATION")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	interactive := prompt.NewInteractive(prompter)
 
-	orchName, err := interactive.PromptOrchestratorName()
-	if err != nil {
-		return fmt.Errorf("failed to get orchestrator name: %w", err)
-	}
+	ui.Println("🤖 Welcome to Agent Builder!")
 
-	orchDescription, err := interactive.PromptOrchestratorDescription()
+	projectType, err := interactive.PromptProjectType()
 	if err != nil {
-		return fmt.Errorf("failed to get orchestrator description: %w", err)
+		return fmt.Errorf("failed to get project type: %w", err)
 	}
 
-	orchModel, err := interactive.PromptModel(prompt.DefaultModel)
+	if projectType == "full" {
+		err = runCreateFullProject(interactive, adkVersion)
+	} else {
+		err = runCreateSingleAgent(interactive)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to get orchestrator model: %w", err)
+		return err
 	}
 
-	orchestrator := model.NewOrchestrator(orchName, pattern, orchDescription, orchModel)
-
-	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println("🤖 SUB-AGENTS CONFIGURATION")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-
-	agentNumber := 1
-	for {
-		agentName, err := interactive.PromptAgentName(agentNumber)
-		if err != nil {
-			return fmt.Errorf("failed to get agent name: %w", err)
+	if scripted != nil && scripted.Remaining() > 0 {
+		fmt.Fprintf(os.Stderr, "notice: the last %d answers in %s were not asked for\n", scripted.Remaining(), answersFlag)
+	}
+	if recorder != nil {
+		if err := prompt.SaveAnswers(recordAnswersFlag, recorder.Answers); err != nil {
+			return err
 		}
+		ui.Printf("\n💾 Saved answers to %s\n", recordAnswersFlag)
+		ui.Printf("   Replay with: agent-builder create --answers %s\n", recordAnswersFlag)
+	}
+	return nil
+}
 
-		agentType, err := interactive.PromptAgentType()
-		if err != nil {
-			return fmt.Errorf("failed to get agent type: %w", err)
-		}
+func runCreateFullProject(interactive *prompt.Interactive, adkVersion adk.Version) error {
+	project, err := interactive.Project(adkVersion)
+	if err != nil {
+		return err
+	}
 
-		var instruction string
-		if agentType == model.AgentTypeLLM {
-			instruction, err = interactive.PromptAgentInstruction(agentName)
-			if err != nil {
-				return fmt.Errorf("failed to get agent instruction: %w", err)
-			}
-		}
+	if err := validateProject(project); err != nil {
+		return err
+	}
 
-		outputKey, err := interactive.PromptOutputKey()
+	specPath := saveSpecFlag
+	if specPath == "" {
+		specPath, err = interactive.PromptSaveSpec(naming.KebabCase(project.Name) + ".yaml")
 		if err != nil {
-			return fmt.Errorf("failed to get output key: %w", err)
+			return fmt.Errorf("failed to prompt for spec file: %w", err)
 		}
-
-		agentModel, err := interactive.PromptModel(prompt.DefaultModel)
-		if err != nil {
-			return fmt.Errorf("failed to get agent model: %w", err)
+	}
+	if specPath != "" {
+		if err := spec.Save(specPath, project); err != nil {
+			return err
 		}
+		ui.Printf("\n💾 Saved spec to %s\n", specPath)
+		ui.Printf("   Regenerate with: agent-builder create --spec %s\n", specPath)
+	}
 
-		agent := model.NewAgent(agentName, agentType, instruction, outputKey, agentModel)
-		orchestrator.AddSubAgent(agent)
+	return createProject(project)
+}
 
-		fmt.Printf("\n✓ Sub-agent \"%s\" added to %s\n\n", agentName, orchName)
+func runCreateFromSpec(cmd *cobra.Command, adkVersion adk.Version) error {
+	project, steps, err := spec.Load(specFlag)
+	if err != nil {
+		return err
+	}
+	printMigrationNotice(specFlag, steps)
+	if cmd.Flags().Changed("adk-version") {
+		project.ADKVersion = adkVersion.Name
+	}
+	if outputDirFlag != "" {
+		project.OutputDir = outputDirFlag
+	}
 
-		addMore, err := interactive.PromptAddAnotherAgent()
-		if err != nil {
-			return fmt.Errorf("failed to prompt for another agent: %w", err)
-		}
+	if err := validateProject(project); err != nil {
+		return err
+	}
 
-		if !addMore {
-			break
+	if saveSpecFlag != "" {
+		if err := spec.Save(saveSpecFlag, project); err != nil {
+			return err
 		}
-
-		agentNumber++
 	}
 
-	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println("📦 PROJECT SETUP")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-
-	project := model.NewProject(projectName, orchestrator)
-
-	fmt.Println("\n💡 Project location:")
-	fmt.Printf("   Your project will be created at: ./%s/\n", projectName)
-	fmt.Println()
+	ui.Printf("Generating %s from %s\n", project.Name, specFlag)
+	return createProject(project)
+}
 
-	outputDir, err := interactive.PromptOutputDirectory(project.OutputDir)
-	if err != nil {
-		return fmt.Errorf("failed to get output directory: %w", err)
+func validateProject(project *model.Project) error {
+	if err := project.Validate(); err != nil {
+		return fmt.Errorf("project validation failed: %w", err)
 	}
-	project.OutputDir = outputDir
 
-	addExample, err := interactive.PromptAddExample()
-	if err != nil {
-		return fmt.Errorf("failed to prompt for example: %w", err)
+	if err := generator.ValidateIdentifiers(project); err != nil {
+		return fmt.Errorf("project validation failed: %w", err)
 	}
-	project.AddExample = addExample
 
-	addDocker, err := interactive.PromptAddDocker()
-	if err != nil {
-		return fmt.Errorf("failed to prompt for Docker: %w", err)
+	for _, warning := range project.Orchestrator.PlaceholderWarnings() {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
 	}
-	project.AddDocker = addDocker
+	return nil
+}
 
-	if err := project.Validate(); err != nil {
-		return fmt.Errorf("project validation failed: %w", err)
+func createProject(project *model.Project) error {
+	orchestrator := project.Orchestrator
+	adkVersion, err := adk.Lookup(project.ADKVersion)
+	if err != nil {
+		return err
 	}
 
-	fmt.Println("\n✨ Generating project structure...")
+	ui.Println("\n✨ Generating project structure...")
 
 	if err := generateProject(project); err != nil {
 		return fmt.Errorf("failed to generate project: %w", err)
 	}
 
-	fmt.Println("\n📁 System Architecture:")
-	fmt.Printf("   %s (%s)\n", orchestrator.Name, pattern.String())
+	ui.Println("\n📁 System Architecture:")
+	ui.Printf("   %s (%s)\n", orchestrator.Name, orchestrator.Pattern.String())
 	for _, agent := range orchestrator.SubAgents {
-		fmt.Printf("   ├── %s (%s)\n", agent.Name, agent.Type)
+		ui.Printf("   ├── %s (%s)\n", agent.Name, agent.Type)
 	}
 
-	fmt.Printf("\n✓ Created %s/\n", project.OutputDir)
-	fmt.Printf("  ├── %s/\n", toSnakeCase(orchestrator.Name))
-	fmt.Println("  │   └── agent.py       # Orchestrator")
+	ui.Printf("\n✓ Created %s/ (google-adk%s)\n", project.OutputDir, adkVersion.Requirement)
+	ui.Printf("  ├── %s/\n", naming.SnakeCase(orchestrator.Name))
+	ui.Println("  │   └── agent.py       # Orchestrator")
 	for _, agent := range orchestrator.SubAgents {
-		fmt.Printf("  ├── %s/\n", toSnakeCase(agent.Name))
-		fmt.Println("  │   └── agent.py       # Sub-agent")
+		ui.Printf("  ├── %s/\n", naming.SnakeCase(agent.Name))
+		ui.Println("  │   └── agent.py       # Sub-agent")
 	}
 	if project.AddExample {
-		fmt.Println("  ├── main.py            # Example usage")
+		ui.Println("  ├── main.py            # Example usage")
+	}
+	if project.AddTests {
+		ui.Println("  ├── tests/             # Unit tests (fake model, no network)")
+	}
+	if project.AddEval {
+		ui.Println("  ├── eval/              # Evaluation set and pytest harness")
 	}
-	fmt.Println("  ├── requirements.txt   # Dependencies (google-adk)")
+	if project.Packaging == model.PackagingRequirements {
+		ui.Println("  ├── requirements.txt   # Dependencies")
+		ui.Println("  ├── requirements-dev.txt # Test and lint dependencies")
+	} else {
+		ui.Printf("  ├── pyproject.toml     # Dependencies (%s)\n", project.Packaging)
+	}
+	ui.Println("  ├── .env.example       # Credentials template")
+	ui.Printf("  ├── %s # Project spec and file hashes\n", spec.ManifestName)
 	if project.AddReadme {
-		fmt.Println("  └── README.md          # Documentation")
+		ui.Println("  └── README.md          # Documentation")
 	}
 
-	fmt.Println("\n🚀 Next steps:")
-	fmt.Printf("  cd %s\n", project.OutputDir)
-	fmt.Println("  pip install -r requirements.txt")
-	fmt.Println()
-	fmt.Println("  # Run with Python:")
+	run := project.Packaging.RunPrefix()
+
+	ui.Println("\n🚀 Next steps:")
+	ui.Printf("  cd %s\n", project.OutputDir)
+	ui.Printf("  %s\n", project.Packaging.InstallCommand())
+	ui.Printf("  cp .env.example .env   # then set %s\n", strings.Join(project.Backend.EnvVars(), ", "))
+	if project.Backend == model.BackendVertexAI {
+		ui.Println("  gcloud auth application-default login")
+	}
+	ui.Println()
+	ui.Println("  # Run with Python:")
 	if project.AddExample {
-		fmt.Println("  python main.py \"Your prompt here\"")
+		ui.Printf("  %spython main.py \"Your prompt here\"\n", run)
+	}
+	ui.Println()
+	ui.Println("  # Or use ADK web interface:")
+	ui.Printf("  %sadk web\n", run)
+	ui.Println("  # Then open http://localhost:8000 in your browser")
+	if project.AddTests {
+		ui.Println()
+		ui.Println("  # Check agent wiring offline:")
+		ui.Printf("  %spytest\n", run)
+	}
+	if project.AddEval {
+		ui.Println()
+		ui.Println("  # Evaluate agent behaviour (calls the model):")
+		ui.Printf("  %spytest eval\n", run)
+		if !hasExamples(orchestrator) {
+			ui.Printf("  # The evaluation set is empty until you add cases to eval/%s.test.json\n", naming.SnakeCase(orchestrator.Name))
+		}
 	}
-	fmt.Println()
-	fmt.Println("  # Or use ADK web interface:")
-	fmt.Println("  adk web")
-	fmt.Println("  # Then open http://localhost:8000 in your browser")
 
 	return nil
 }
 
-func runCreateSingleAgent(interactive *prompt.Interactive) error {
-	fmt.Println("Let's create a single agent to add to your project.")
-
-	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println("🤖 AGENT CONFIGURATION")
-	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-
-	agentName, err := interactive.PromptAgentName(1)
-	if err != nil {
-		return fmt.Errorf("failed to get agent name: %w", err)
-	}
-
-	agentType, err := interactive.PromptAgentType()
-	if err != nil {
-		return fmt.Errorf("failed to get agent type: %w", err)
-	}
-
-	var instruction string
-	if agentType == model.AgentTypeLLM {
-		instruction, err = interactive.PromptAgentInstruction(agentName)
-		if err != nil {
-			return fmt.Errorf("failed to get agent instruction: %w", err)
+// hasExamples reports whether any sub-agent has example prompts, from which
+// the evaluation set is built.
+func hasExamples(orchestrator *model.Orchestrator) bool {
+	for _, agent := range orchestrator.SubAgents {
+		if len(agent.Examples) > 0 {
+			return true
 		}
 	}
+	return false
+}
 
-	outputKey, err := interactive.PromptOutputKey()
-	if err != nil {
-		return fmt.Errorf("failed to get output key: %w", err)
-	}
-
-	agentModel, err := interactive.PromptModel(prompt.DefaultModel)
+func runCreateSingleAgent(interactive *prompt.Interactive) error {
+	agent, err := interactive.Agent()
 	if err != nil {
-		return fmt.Errorf("failed to get agent model: %w", err)
+		return err
 	}
 
-	agent := model.NewAgent(agentName, agentType, instruction, outputKey, agentModel)
+	ui.Println("\n✨ Generating agent...")
 
-	fmt.Println("\n✨ Generating agent...")
-
-	agentFolderName := toSnakeCase(agentName)
-	agentDir := filepath.Join(".", agentFolderName)
-
-	if err := os.MkdirAll(agentDir, 0755); err != nil {
-		return fmt.Errorf("failed to create agent directory: %w", err)
-	}
+	agentFolderName := naming.SnakeCase(agent.Name)
 
 	gen := generator.NewGenerator()
-	agentPy, err := gen.GenerateSubAgentPy(agent)
+	files, err := gen.RenderSingleAgent(agent)
 	if err != nil {
-		return fmt.Errorf("failed to generate agent.py: %w", err)
+		return fmt.Errorf("failed to generate agent: %w", err)
 	}
 
-	if err := os.WriteFile(filepath.Join(agentDir, "agent.py"), []byte(agentPy), 0644); err != nil {
-		return fmt.Errorf("failed to write agent.py: %w", err)
+	if err := generator.WriteFiles(".", files); err != nil {
+		return err
 	}
 
-	fmt.Printf("\n✓ Created %s/\n", agentFolderName)
-	fmt.Println("  └── agent.py")
+	ui.Println("\n✓ Created:")
+	for i, file := range files {
+		branch := "├──"
+		if i == len(files)-1 {
+			branch = "└──"
+		}
+		ui.Printf("  %s %s\n", branch, filepath.ToSlash(file.Path))
+	}
 
-	fmt.Println("\n💡 To use this agent in your project:")
-	fmt.Println("   1. Import it in your orchestrator's agent.py:")
-	fmt.Printf("      from %s.agent import agent as %s\n", agentFolderName, agentFolderName)
-	fmt.Println()
-	fmt.Println("   2. Add it to your orchestrator's sub_agents list:")
-	fmt.Printf("      sub_agents=[..., %s]\n", agentFolderName)
-	fmt.Println()
-	fmt.Println("📚 Learn more: https://google.github.io/adk-docs/")
+	ui.Println("\n💡 To use this agent in your project:")
+	ui.Println("   1. Import it in your orchestrator's agent.py:")
+	ui.Printf("      from %s.agent import agent as %s\n", agentFolderName, agentFolderName)
+	ui.Println()
+	ui.Println("   2. Add it to your orchestrator's sub_agents list:")
+	ui.Printf("      sub_agents=[..., %s]\n", agentFolderName)
+	ui.Println()
+	ui.Println("📚 Learn more: https://google.github.io/adk-docs/")
 
 	return nil
 }
 
 func generateProject(project *model.Project) error {
-	if err := os.MkdirAll(project.OutputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
-	}
-
 	gen := generator.NewGenerator()
-
-	orchFolderName := toSnakeCase(project.Orchestrator.Name)
-	orchDir := filepath.Join(project.OutputDir, orchFolderName)
-	if err := os.MkdirAll(orchDir, 0755); err != nil {
-		return fmt.Errorf("failed to create orchestrator directory: %w", err)
-	}
-
-	orchPy, err := gen.GenerateOrchestratorPy(project.Orchestrator)
+	files, err := gen.RenderProject(project)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(orchDir, "agent.py"), []byte(orchPy), 0644); err != nil {
-		return fmt.Errorf("failed to write orchestrator agent.py: %w", err)
-	}
-
-	for _, agent := range project.Orchestrator.SubAgents {
-		agentFolderName := toSnakeCase(agent.Name)
-		agentDir := filepath.Join(project.OutputDir, agentFolderName)
-		if err := os.MkdirAll(agentDir, 0755); err != nil {
-			return fmt.Errorf("failed to create sub-agent directory: %w", err)
-		}
-
-		agentPy, err := gen.GenerateSubAgentPy(agent)
-		if err != nil {
-			return err
-		}
-		if err := os.WriteFile(filepath.Join(agentDir, "agent.py"), []byte(agentPy), 0644); err != nil {
-			return fmt.Errorf("failed to write sub-agent agent.py: %w", err)
-		}
-	}
-
-	if project.AddExample {
-		mainPy, err := gen.GenerateMainPy(project)
-		if err != nil {
-			return err
-		}
-		if err := os.WriteFile(filepath.Join(project.OutputDir, "main.py"), []byte(mainPy), 0644); err != nil {
-			return fmt.Errorf("failed to write main.py: %w", err)
-		}
-	}
 
-	requirementsTxt, err := gen.GenerateRequirementsTxt()
+	manifest, err := spec.ManifestFile(project, files)
 	if err != nil {
 		return err
 	}
-	if err := os.WriteFile(filepath.Join(project.OutputDir, "requirements.txt"), []byte(requirementsTxt), 0644); err != nil {
-		return fmt.Errorf("failed to write requirements.txt: %w", err)
-	}
+	files = append(files, manifest)
 
-	if project.AddReadme {
-		readme, err := gen.GenerateReadme(project)
-		if err != nil {
-			return err
-		}
-		if err := os.WriteFile(filepath.Join(project.OutputDir, "README.md"), []byte(readme), 0644); err != nil {
-			return fmt.Errorf("failed to write README.md: %w", err)
-		}
+	if err := os.MkdirAll(project.OutputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	return nil
-}
-
-func toSnakeCase(s string) string {
-	s = strings.ReplaceAll(s, "-", "_")
-	var result strings.Builder
-	for i, r := range s {
-		if i > 0 && r >= 'A' && r <= 'Z' {
-			result.WriteRune('_')
-		}
-		result.WriteRune(r)
-	}
-	return strings.ToLower(result.String())
+	return generator.WriteFiles(project.OutputDir, files)
 }
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/doji-co/agent-builder/internal/doctor"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [PATH]",
+	Short: "Check the Python environment and a generated project",
+	Long: `Inspect the local Python environment and an agent project directory.
+
+Reports the Python version, the installed google-adk version, credential
+environment variables, agent packages without __init__.py, import cycles and
+files changed since generation. Exits with status 1 if any check fails, or if
+any check warns when --strict is set.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runDoctor,
+}
+
+var doctorStrict bool
+
+// errDoctorProblems makes doctor exit with status 1 once the report that
+// explains it has been printed.
+var errDoctorProblems = errors.New("doctor found problems")
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&doctorStrict, "strict", false, "treat warnings as failures")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+
+	sections := doctor.New(dir).Run()
+
+	counts := make(map[doctor.Status]int)
+	for i, section := range sections {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println(section.Title)
+		for _, result := range section.Results {
+			fmt.Printf("  [%s] %s: %s\n", result.Status, result.Name, result.Detail)
+			counts[result.Status]++
+		}
+	}
+
+	fmt.Printf("\n%d passed, %d warned, %d failed\n", counts[doctor.Pass], counts[doctor.Warn], counts[doctor.Fail])
+
+	worst := doctor.Worst(sections)
+	if worst == doctor.Fail || (doctorStrict && worst == doctor.Warn) {
+		cmd.SilenceErrors = true
+		return errDoctorProblems
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/spec"
+	"github.com/doji-co/agent-builder/internal/tui"
+	"github.com/doji-co/agent-builder/internal/ui"
+	"github.com/muesli/termenv"
+	"github.com/spf13/cobra"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit [PATH]",
+	Short: "Edit a generated project in a terminal UI",
+	Long: `Open the agent-builder.yaml of the project directory at PATH (default ".")
+in a full-screen editor: the agent tree on the left, a form for the selected
+node on the right and a live preview of the agent.py it generates.
+
+Saving writes the spec back to agent-builder.yaml; regenerate the project
+from it with agent-builder create --spec.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runEdit,
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	path := filepath.Join(dir, spec.ManifestName)
+
+	manifest, err := spec.LoadManifest(dir)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%s has no %s; generate the project with agent-builder create first", dir, spec.ManifestName)
+	}
+	if err != nil {
+		return err
+	}
+	printMigrationNotice(path, manifest.Migrated)
+
+	if !ui.IsTerminal(os.Stdin) || !ui.IsTerminal(os.Stdout) {
+		return fmt.Errorf("no terminal: edit is a full-screen editor; edit %s directly instead", path)
+	}
+	if ui.Plain() {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	editor := tui.New(&manifest.Project, func(*model.Project) error {
+		return spec.SaveManifest(dir, manifest)
+	})
+	if _, err := tea.NewProgram(editor, tea.WithAltScreen()).Run(); err != nil {
+		return fmt.Errorf("failed to run editor: %w", err)
+	}
+
+	if editor.Saved() {
+		ui.Printf("✓ Saved %s\n", path)
+		ui.Printf("   Regenerate with: agent-builder create --spec %s --output-dir %s\n", path, dir)
+	}
+	return nil
+}
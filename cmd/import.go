@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/doji-co/agent-builder/internal/generator"
+	"github.com/doji-co/agent-builder/internal/importer"
+	"github.com/doji-co/agent-builder/internal/spec"
+	"github.com/doji-co/agent-builder/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import PATH",
+	Short: "Import an existing ADK project into a spec",
+	Long: `Read the agent.py files of an existing ADK project without running Python
+and rebuild its spec from the LlmAgent, SequentialAgent, ParallelAgent and
+LoopAgent constructor calls they contain.
+
+By default the result is written to PATH/agent-builder.yaml as a manifest that
+records the imported files, so "agent-builder doctor" can report later edits.
+Anything the spec cannot represent is reported as a warning.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runImport,
+}
+
+var (
+	importOutput string
+	importForce  bool
+)
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringVarP(&importOutput, "output", "o", "", "write a plain spec to this file instead of the project manifest")
+	importCmd.Flags().BoolVar(&importForce, "force", false, "overwrite an existing file")
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+
+	result, err := importer.Import(dir)
+	if err != nil {
+		return fmt.Errorf("failed to import project: %w", err)
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+
+	path := importOutput
+	if path == "" {
+		path = filepath.Join(dir, spec.ManifestName)
+	}
+	if _, err := os.Stat(path); err == nil && !importForce {
+		return fmt.Errorf("%s already exists; use --force to overwrite it", path)
+	}
+
+	if importOutput != "" {
+		if err := spec.Save(path, result.Project); err != nil {
+			return err
+		}
+	} else {
+		manifest, err := spec.ManifestFile(result.Project, result.Files)
+		if err != nil {
+			return err
+		}
+		if err := generator.WriteFiles(dir, []generator.File{manifest}); err != nil {
+			return err
+		}
+	}
+
+	orchestrator := result.Project.Orchestrator
+	ui.Printf("✓ Imported %s (%s) with %d sub-agents to %s\n",
+		orchestrator.Name, orchestrator.Pattern.String(), len(orchestrator.SubAgents), path)
+	if len(result.Warnings) > 0 {
+		fmt.Printf("  %d warnings; review the spec before regenerating\n", len(result.Warnings))
+	}
+	return nil
+}
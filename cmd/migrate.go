@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/doji-co/agent-builder/internal/diff"
+	"github.com/doji-co/agent-builder/internal/spec"
+	"github.com/doji-co/agent-builder/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate [PATH]",
+	Short: "Upgrade a spec or manifest to the current format",
+	Long: `Rewrite a spec file, or the agent-builder.yaml of the project directory at
+PATH (default "."), in the current spec format and print the diff.
+
+Other commands migrate older specs in memory when they load them; this command
+makes the upgrade permanent. Comments and key order are kept.`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runMigrate,
+}
+
+var migrateDryRun bool
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "print the diff without writing the file")
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, spec.ManifestName)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read spec: %w", err)
+	}
+	migrated, steps, err := spec.Migrate(data)
+	if err != nil {
+		return fmt.Errorf("failed to migrate %s: %w", path, err)
+	}
+	if _, err := spec.Unmarshal(migrated); err != nil {
+		return fmt.Errorf("failed to parse migrated %s: %w", path, err)
+	}
+
+	if len(steps) == 0 {
+		fmt.Printf("%s is already %s\n", path, spec.APIVersion)
+		return nil
+	}
+
+	for _, step := range steps {
+		fmt.Printf("  %s\n", step)
+	}
+	fmt.Print(diff.Unified("a/"+filepath.ToSlash(path), "b/"+filepath.ToSlash(path), string(data), string(migrated)))
+
+	if migrateDryRun {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to write spec: %w", err)
+	}
+	if err := os.WriteFile(path, migrated, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("failed to write spec: %w", err)
+	}
+	ui.Printf("✓ Migrated %s to %s\n", path, spec.APIVersion)
+	return nil
+}
+
+// printMigrationNotice tells the user a spec was upgraded in memory.
+func printMigrationNotice(path string, steps []spec.Step) {
+	if len(steps) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "notice: %s uses spec format %s and was migrated to %s in memory; run \"agent-builder migrate %s\" to update the file\n",
+		path, spec.FormatName(steps[0].From), spec.APIVersion, path)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 
+	"github.com/doji-co/agent-builder/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -10,8 +11,13 @@ var rootCmd = &cobra.Command{
 	Use:   "agent-builder",
 	Short: "ADK Multi-Agent Builder CLI",
 	Long:  "A CLI tool to help build ADK (Agent Development Kit) multi-agent systems.",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		ui.SetPlain(noColorFlag || ui.PlainRequested())
+	},
 }
 
+var noColorFlag bool
+
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
@@ -21,4 +27,6 @@ func Execute() {
 
 func init() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.PersistentFlags().BoolVar(&noColorFlag, "no-color", false,
+		"plain output without colors or emoji (also set by NO_COLOR or when output is not a terminal)")
 }
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/doji-co/agent-builder/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema of the project spec",
+	Long: `Print the JSON Schema that specs and agent-builder.yaml manifests follow.
+
+Specs written by agent-builder already point editors at the published schema
+through their $schema key; use this command to validate offline or to pin the
+schema of this agent-builder version.`,
+	Args: cobra.NoArgs,
+	RunE: runSchema,
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	schema, err := spec.Schema()
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(schema)
+	return err
+}
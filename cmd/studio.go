@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/spec"
+	"github.com/doji-co/agent-builder/internal/studio"
+	"github.com/doji-co/agent-builder/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var studioCmd = &cobra.Command{
+	Use:   "studio",
+	Short: "Compose a project in a visual builder in the browser",
+	Long: `Serve a visual builder on localhost: drag sub-agents onto an orchestrator,
+edit them in a form and watch the generated Python update as you go. The
+project is validated as you edit, and can be exported as a spec or generated
+into a directory with the same generator as agent-builder create.
+
+The page and its assets are built into agent-builder; nothing is loaded from
+the internet.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runStudio,
+}
+
+var (
+	studioPortFlag      int
+	studioSpecFlag      string
+	studioOutputDirFlag string
+)
+
+func init() {
+	rootCmd.AddCommand(studioCmd)
+	studioCmd.Flags().IntVar(&studioPortFlag, "port", 8484, "port to serve the studio on (0 picks a free port)")
+	studioCmd.Flags().StringVar(&studioSpecFlag, "spec", "", "start from a saved spec or manifest")
+	studioCmd.Flags().StringVar(&studioOutputDirFlag, "output-dir", "", "directory to generate into (default ./<project name>)")
+}
+
+func runStudio(cmd *cobra.Command, args []string) error {
+	project := starterProject()
+	if studioSpecFlag != "" {
+		loaded, steps, err := spec.Load(studioSpecFlag)
+		if err != nil {
+			return err
+		}
+		printMigrationNotice(studioSpecFlag, steps)
+		project = loaded
+	}
+
+	server := studio.New(project, func(project *model.Project) error {
+		if err := generateProject(project); err != nil {
+			return err
+		}
+		ui.Printf("✓ Generated %s/\n", project.OutputDir)
+		return nil
+	})
+	server.OutputDir = studioOutputDirFlag
+
+	// Only loopback: the studio writes files wherever it is told to.
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", studioPortFlag))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+	httpServer := &http.Server{Handler: server.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdown)
+	}()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	ui.Printf("🎨 agent-builder studio is running at http://localhost:%d\n", port)
+	ui.Println("   Press Ctrl+C to stop.")
+
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve studio: %w", err)
+	}
+	return nil
+}
+
+// starterProject is what the studio opens without --spec: a sequential
+// orchestrator with one agent to build on.
+func starterProject() *model.Project {
+	orchestrator := model.NewOrchestrator("Coordinator", model.PatternSequential, "Coordinates the sub-agents", model.DefaultModel)
+	orchestrator.AddSubAgent(model.NewAgent("Assistant", model.AgentTypeLLM, "Answer the user's request.", "answer", model.DefaultModel))
+	return model.NewProject("my-agents", orchestrator)
+}
@@ -0,0 +1,77 @@
+package adk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Version describes one supported range of google-adk releases and the API
+// differences the templates have to account for.
+type Version struct {
+	Name        string
+	Requirement string
+	Description string
+
+	// AsyncSessions is set when session and artifact service methods are
+	// coroutines (create_session, get_session, save_artifact), which they
+	// are from 1.0 on.
+	AsyncSessions bool
+
+	// EvalSets is set when AgentEvaluator.evaluate is a coroutine that
+	// reads the EvalSet file format generated into eval/.
+	EvalSets bool
+
+	// StaticInstruction is set when LlmAgent accepts static_instruction,
+	// which it does from 1.15 on.
+	StaticInstruction bool
+}
+
+const Default = "1.0"
+
+var versions = []Version{
+	{
+		Name:              "0.5",
+		Requirement:       ">=0.5.0,<1.0.0",
+		Description:       "Pre-1.0 API with synchronous session services",
+		AsyncSessions:     false,
+		EvalSets:          false,
+		StaticInstruction: false,
+	},
+	{
+		Name:              "1.0",
+		Requirement:       ">=1.0.0,<2.0.0",
+		Description:       "Stable 1.x API with async session services and EvalSet evaluation",
+		AsyncSessions:     true,
+		EvalSets:          true,
+		StaticInstruction: false,
+	},
+	{
+		Name:              "1.15",
+		Requirement:       ">=1.15.0,<2.0.0",
+		Description:       "1.x API with static instructions for context caching",
+		AsyncSessions:     true,
+		EvalSets:          true,
+		StaticInstruction: true,
+	},
+}
+
+func Versions() []Version {
+	return append([]Version(nil), versions...)
+}
+
+func Names() []string {
+	names := make([]string, len(versions))
+	for i, v := range versions {
+		names[i] = v.Name
+	}
+	return names
+}
+
+func Lookup(name string) (Version, error) {
+	for _, v := range versions {
+		if v.Name == name {
+			return v, nil
+		}
+	}
+	return Version{}, fmt.Errorf("unsupported ADK version %q (supported: %s)", name, strings.Join(Names(), ", "))
+}
@@ -0,0 +1,76 @@
+package adk
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name            string
+		version         string
+		wantErr         bool
+		wantRequirement string
+	}{
+		{
+			name:            "default version",
+			version:         Default,
+			wantErr:         false,
+			wantRequirement: ">=1.0.0,<2.0.0",
+		},
+		{
+			name:            "pre-1.0 version",
+			version:         "0.5",
+			wantErr:         false,
+			wantRequirement: ">=0.5.0,<1.0.0",
+		},
+		{
+			name:            "static instruction version",
+			version:         "1.15",
+			wantErr:         false,
+			wantRequirement: ">=1.15.0,<2.0.0",
+		},
+		{
+			name:    "unknown version",
+			version: "2.0",
+			wantErr: true,
+		},
+		{
+			name:    "empty version",
+			version: "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Lookup(tt.version)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Lookup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.Requirement != tt.wantRequirement {
+				t.Errorf("Requirement = %v, want %v", got.Requirement, tt.wantRequirement)
+			}
+		})
+	}
+}
+
+func TestLookup_ErrorListsSupportedVersions(t *testing.T) {
+	_, err := Lookup("9.9")
+	if err == nil {
+		t.Fatal("Lookup() expected error")
+	}
+
+	want := `unsupported ADK version "9.9" (supported: 0.5, 1.0, 1.15)`
+	if err.Error() != want {
+		t.Errorf("Lookup() error message = %v, want %v", err.Error(), want)
+	}
+}
+
+func TestVersions_ReturnsCopy(t *testing.T) {
+	got := Versions()
+	got[0].Name = "changed"
+
+	if Versions()[0].Name == "changed" {
+		t.Error("Versions() should return a copy of the matrix")
+	}
+}
@@ -0,0 +1,619 @@
+package doctor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/doji-co/agent-builder/internal/adk"
+	"github.com/doji-co/agent-builder/internal/generator"
+	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/pysyntax"
+	"github.com/doji-co/agent-builder/internal/spec"
+)
+
+type Status int
+
+const (
+	Pass Status = iota
+	Warn
+	Fail
+)
+
+func (s Status) String() string {
+	switch s {
+	case Pass:
+		return "pass"
+	case Warn:
+		return "warn"
+	default:
+		return "fail"
+	}
+}
+
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+}
+
+type Section struct {
+	Title   string
+	Results []Result
+}
+
+// Doctor inspects the Python environment and an agent project directory.
+type Doctor struct {
+	Dir    string
+	Python string
+	Getenv func(string) string
+
+	manifest    *spec.Manifest
+	manifestErr error
+}
+
+func New(dir string) *Doctor {
+	d := &Doctor{
+		Dir:    dir,
+		Python: FindPython(dir),
+		Getenv: os.Getenv,
+	}
+	d.manifest, d.manifestErr = spec.LoadManifest(dir)
+	return d
+}
+
+// FindPython prefers the project's virtual environment over the interpreter
+// on PATH, since that is where its dependencies are installed.
+func FindPython(dir string) string {
+	for _, candidate := range []string{
+		filepath.Join(dir, ".venv", "bin", "python"),
+		filepath.Join(dir, ".venv", "Scripts", "python.exe"),
+	} {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	for _, name := range []string{"python3", "python"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+func (d *Doctor) Run() []Section {
+	return []Section{
+		{Title: "Environment", Results: d.Environment()},
+		{Title: "Project " + d.Dir, Results: d.Project()},
+	}
+}
+
+func (d *Doctor) Environment() []Result {
+	results := []Result{d.CheckPython(), d.CheckADK()}
+	return append(results, d.CheckCredentials()...)
+}
+
+func (d *Doctor) Project() []Result {
+	packages, result := d.CheckPackages()
+	return []Result{result, d.CheckImports(packages), d.CheckManifest()}
+}
+
+func (d *Doctor) CheckPython() Result {
+	result := Result{Name: "Python"}
+	if d.Python == "" {
+		result.Status = Fail
+		result.Detail = "no python3 or python on PATH"
+		return result
+	}
+
+	version, err := d.runPython("import sys; print('%d.%d.%d' % sys.version_info[:3])")
+	if err != nil {
+		result.Status = Fail
+		result.Detail = fmt.Sprintf("%s did not run: %v", d.Python, err)
+		return result
+	}
+
+	if !Satisfies(version, generator.PythonRequirement) {
+		result.Status = Fail
+		result.Detail = fmt.Sprintf("%s at %s; generated projects need Python %s", version, d.Python, generator.PythonRequirement)
+		return result
+	}
+
+	result.Detail = fmt.Sprintf("%s at %s", version, d.Python)
+	return result
+}
+
+func (d *Doctor) CheckADK() Result {
+	result := Result{Name: "google-adk"}
+	if d.Python == "" {
+		result.Status = Fail
+		result.Detail = "cannot check without Python"
+		return result
+	}
+
+	installed, err := d.runPython("from importlib.metadata import version; print(version('google-adk'))")
+	if err != nil {
+		result.Status = Fail
+		result.Detail = fmt.Sprintf("not installed for %s; run %s", d.Python, d.installCommand())
+		return result
+	}
+
+	if d.manifest != nil {
+		version, err := adk.Lookup(d.manifest.ADKVersion)
+		if err != nil {
+			result.Status = Warn
+			result.Detail = fmt.Sprintf("%s installed; %v", installed, err)
+			return result
+		}
+		if !Satisfies(installed, version.Requirement) {
+			result.Status = Fail
+			result.Detail = fmt.Sprintf("%s installed, but the project targets ADK %s (%s); run %s",
+				installed, version.Name, version.Requirement, d.installCommand())
+			return result
+		}
+	}
+
+	result.Detail = installed
+	return result
+}
+
+// CheckCredentials looks for the variables the configured backend and
+// services need in the environment and in the project's .env file, which
+// python-dotenv loads without overriding variables that are already set.
+func (d *Doctor) CheckCredentials() []Result {
+	dotenv, _ := readDotenv(filepath.Join(d.Dir, ".env"))
+	lookup := func(key string) (string, string) {
+		if value := d.Getenv(key); value != "" {
+			return value, "environment"
+		}
+		if value := dotenv[key]; value != "" {
+			return value, ".env"
+		}
+		return "", ""
+	}
+
+	backend := model.BackendAIStudio
+	if d.manifest != nil {
+		backend = d.manifest.Backend
+	}
+	if value, _ := lookup("GOOGLE_GENAI_USE_VERTEXAI"); value != "" {
+		backend = model.BackendAIStudio
+		if strings.EqualFold(value, "true") || value == "1" {
+			backend = model.BackendVertexAI
+		}
+	}
+
+	type requirement struct{ key, by string }
+	var required []requirement
+	for _, key := range backend.EnvVars() {
+		required = append(required, requirement{key, backend.String()})
+	}
+	if d.manifest != nil {
+		for _, key := range d.manifest.Services.EnvVars() {
+			required = append(required, requirement{key, "main.py"})
+		}
+	}
+
+	var results []Result
+	for _, r := range required {
+		result := Result{Name: r.key}
+		value, source := lookup(r.key)
+		switch {
+		case value == "":
+			result.Status = Warn
+			result.Detail = fmt.Sprintf("not set; %s needs it (copy .env.example to .env and fill it in)", r.by)
+		case strings.Contains(value, "your-"):
+			result.Status = Warn
+			result.Detail = fmt.Sprintf("still the placeholder from .env.example (%s)", source)
+		default:
+			result.Detail = fmt.Sprintf("set (%s)", source)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// CheckPackages finds the agent packages in the project, the directories
+// holding an agent.py, and reports any that Python cannot import.
+func (d *Doctor) CheckPackages() ([]string, Result) {
+	result := Result{Name: "Agent packages"}
+
+	matches, err := filepath.Glob(filepath.Join(d.Dir, "*", "agent.py"))
+	if err != nil || len(matches) == 0 {
+		result.Status = Fail
+		result.Detail = "no <package>/agent.py found; is this an agent project?"
+		return nil, result
+	}
+
+	var packages, missing, noImport []string
+	for _, match := range matches {
+		pkg := filepath.Base(filepath.Dir(match))
+		packages = append(packages, pkg)
+
+		init, err := os.ReadFile(filepath.Join(d.Dir, pkg, "__init__.py"))
+		if err != nil {
+			missing = append(missing, pkg)
+			continue
+		}
+		if !importsAgent(string(init)) {
+			noImport = append(noImport, pkg)
+		}
+	}
+
+	switch {
+	case len(missing) > 0:
+		result.Status = Fail
+		result.Detail = fmt.Sprintf("missing __init__.py in %s", strings.Join(missing, ", "))
+	case len(noImport) > 0:
+		result.Status = Warn
+		result.Detail = fmt.Sprintf("__init__.py does not import agent in %s; adk web will not find them", strings.Join(noImport, ", "))
+	default:
+		result.Detail = strings.Join(packages, ", ")
+	}
+	return packages, result
+}
+
+// CheckImports reads the imports of every agent.py and fails on syntax errors
+// and on import cycles between the project's packages.
+func (d *Doctor) CheckImports(packages []string) Result {
+	result := Result{Name: "Imports"}
+
+	local := make(map[string]bool, len(packages))
+	for _, pkg := range packages {
+		local[pkg] = true
+	}
+
+	graph := make(map[string][]string)
+	for _, pkg := range packages {
+		path := filepath.Join(pkg, "agent.py")
+		src, err := os.ReadFile(filepath.Join(d.Dir, path))
+		if err != nil {
+			result.Status = Fail
+			result.Detail = fmt.Sprintf("failed to read %s: %v", path, err)
+			return result
+		}
+		modules, err := Imports(string(src))
+		if err != nil {
+			result.Status = Fail
+			result.Detail = fmt.Sprintf("%s: %v", filepath.ToSlash(path), err)
+			return result
+		}
+		for _, module := range modules {
+			top := strings.SplitN(module, ".", 2)[0]
+			if local[top] && top != pkg {
+				graph[pkg] = append(graph[pkg], top)
+			}
+		}
+	}
+
+	if cycle := FindCycle(graph); cycle != nil {
+		result.Status = Fail
+		result.Detail = "import cycle: " + strings.Join(cycle, " → ")
+		return result
+	}
+
+	result.Detail = "no cycles"
+	return result
+}
+
+func (d *Doctor) CheckManifest() Result {
+	result := Result{Name: "Manifest"}
+
+	if d.manifestErr != nil {
+		if os.IsNotExist(d.manifestErr) {
+			result.Status = Warn
+			result.Detail = fmt.Sprintf("no %s; drift cannot be checked", spec.ManifestName)
+		} else {
+			result.Status = Fail
+			result.Detail = d.manifestErr.Error()
+		}
+		return result
+	}
+
+	var modified, deleted []string
+	for path, hash := range d.manifest.Files {
+		content, err := os.ReadFile(filepath.Join(d.Dir, filepath.FromSlash(path)))
+		switch {
+		case err != nil:
+			deleted = append(deleted, path)
+		case spec.Hash(content) != hash:
+			modified = append(modified, path)
+		}
+	}
+	sort.Strings(modified)
+	sort.Strings(deleted)
+
+	var drift []string
+	if len(modified) > 0 {
+		drift = append(drift, "modified: "+strings.Join(modified, ", "))
+	}
+	if len(deleted) > 0 {
+		drift = append(drift, "deleted: "+strings.Join(deleted, ", "))
+	}
+
+	if len(drift) > 0 {
+		result.Status = Warn
+		result.Detail = "changed since generation; " + strings.Join(drift, "; ")
+		return result
+	}
+
+	result.Detail = fmt.Sprintf("%d generated files unchanged", len(d.manifest.Files))
+	if len(d.manifest.Migrated) > 0 {
+		result.Status = Warn
+		result.Detail += fmt.Sprintf("; written in spec format %s, run \"agent-builder migrate\" to upgrade it",
+			spec.FormatName(d.manifest.Migrated[0].From))
+	}
+	return result
+}
+
+func (d *Doctor) runPython(code string) (string, error) {
+	out, err := exec.Command(d.Python, "-c", code).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (d *Doctor) installCommand() string {
+	if d.manifest != nil {
+		return d.manifest.Packaging.InstallCommand()
+	}
+	return model.PackagingRequirements.InstallCommand()
+}
+
+// Worst returns the most severe status in sections.
+func Worst(sections []Section) Status {
+	worst := Pass
+	for _, section := range sections {
+		for _, result := range section.Results {
+			if result.Status > worst {
+				worst = result.Status
+			}
+		}
+	}
+	return worst
+}
+
+// Imports lists the modules a Python source file imports, skipping relative
+// imports.
+func Imports(src string) ([]string, error) {
+	lines, err := importLines(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []string
+	for _, line := range lines {
+		switch line[0].Text {
+		case "from":
+			if module := dottedName(line[1:]); module != "" {
+				modules = append(modules, module)
+			}
+		case "import":
+			start := 1
+			for i := 1; i <= len(line); i++ {
+				if i == len(line) || line[i].Text == "," {
+					if module := dottedName(line[start:i]); module != "" {
+						modules = append(modules, module)
+					}
+					start = i + 1
+				}
+			}
+		}
+	}
+	return modules, nil
+}
+
+// importLines returns the logical lines of src that are import statements.
+func importLines(src string) ([][]pysyntax.Token, error) {
+	tokens, err := pysyntax.Tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines [][]pysyntax.Token
+	var line []pysyntax.Token
+	for _, tok := range tokens {
+		if tok.Kind != pysyntax.TokenNewline && tok.Kind != pysyntax.TokenEOF {
+			line = append(line, tok)
+			continue
+		}
+		if len(line) > 0 && line[0].Kind == pysyntax.TokenName && (line[0].Text == "from" || line[0].Text == "import") {
+			lines = append(lines, line)
+		}
+		line = nil
+	}
+	return lines, nil
+}
+
+// dottedName reads a module name such as google.adk.agents from the start of
+// tokens. It returns "" for relative imports.
+func dottedName(tokens []pysyntax.Token) string {
+	var b strings.Builder
+	for _, tok := range tokens {
+		switch {
+		case tok.Kind == pysyntax.TokenName && tok.Text != "import" && tok.Text != "as":
+			b.WriteString(tok.Text)
+		case tok.Text == "." && b.Len() > 0:
+			b.WriteString(".")
+		case tok.Text == ".":
+			return ""
+		default:
+			return b.String()
+		}
+	}
+	return b.String()
+}
+
+// importsAgent reports whether an __init__.py imports the package's agent
+// module, which is how adk web finds root_agent.
+func importsAgent(src string) bool {
+	lines, err := importLines(src)
+	if err != nil {
+		return false
+	}
+	for _, line := range lines {
+		for _, tok := range line[1:] {
+			if tok.Kind == pysyntax.TokenName && tok.Text == "agent" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FindCycle returns the first import cycle in graph as a list of packages
+// that starts and ends with the same package, or nil.
+func FindCycle(graph map[string][]string) []string {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int)
+	var stack []string
+	var cycle []string
+
+	var visit func(node string) bool
+	visit = func(node string) bool {
+		state[node] = inProgress
+		stack = append(stack, node)
+		for _, next := range graph[node] {
+			switch state[next] {
+			case inProgress:
+				for i, n := range stack {
+					if n == next {
+						cycle = append(append([]string{}, stack[i:]...), next)
+						return true
+					}
+				}
+			case unvisited:
+				if visit(next) {
+					return true
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[node] = done
+		return false
+	}
+
+	nodes := make([]string, 0, len(graph))
+	for node := range graph {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for _, node := range nodes {
+		if state[node] == unvisited && visit(node) {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// Satisfies reports whether version meets a comma-separated requirement such
+// as ">=1.0.0,<2.0.0". Pre-release and local suffixes are ignored.
+func Satisfies(version, requirement string) bool {
+	for _, clause := range strings.Split(requirement, ",") {
+		clause = strings.TrimSpace(clause)
+		op := "=="
+		for _, candidate := range []string{">=", "<=", "==", "!=", ">", "<"} {
+			if strings.HasPrefix(clause, candidate) {
+				op = candidate
+				break
+			}
+		}
+		cmp := compareVersions(version, strings.TrimSpace(strings.TrimPrefix(clause, op)))
+		ok := false
+		switch op {
+		case ">=":
+			ok = cmp >= 0
+		case "<=":
+			ok = cmp <= 0
+		case "==":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case "<":
+			ok = cmp < 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	var parts []int
+	for _, field := range strings.Split(v, ".") {
+		end := 0
+		for end < len(field) && field[end] >= '0' && field[end] <= '9' {
+			end++
+		}
+		n, err := strconv.Atoi(field[:end])
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+		if end < len(field) {
+			break
+		}
+	}
+	return parts
+}
+
+func readDotenv(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	return values, scanner.Err()
+}
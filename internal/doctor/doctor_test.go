@@ -0,0 +1,437 @@
+package doctor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/doji-co/agent-builder/internal/generator"
+	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/spec"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newTestDoctor(dir string, env map[string]string) *Doctor {
+	d := New(dir)
+	d.Getenv = func(key string) string { return env[key] }
+	return d
+}
+
+func TestImports(t *testing.T) {
+	src := `"""Docstring mentioning import os."""
+from google.adk.agents import LlmAgent, SequentialAgent
+from researcher.agent import agent as researcher
+from . import agent
+import os, json as j
+import writer.agent
+
+def build():
+    from helper import tool
+`
+
+	got, err := Imports(src)
+	if err != nil {
+		t.Fatalf("Imports() error = %v", err)
+	}
+
+	want := []string{"google.adk.agents", "researcher.agent", "os", "json", "writer.agent", "helper"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Imports() = %v, want %v", got, want)
+	}
+}
+
+func TestFindCycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		graph map[string][]string
+		want  []string
+	}{
+		{
+			name:  "no cycle",
+			graph: map[string][]string{"coordinator": {"researcher", "writer"}, "writer": {"researcher"}},
+			want:  nil,
+		},
+		{
+			name:  "two packages importing each other",
+			graph: map[string][]string{"researcher": {"writer"}, "writer": {"researcher"}},
+			want:  []string{"researcher", "writer", "researcher"},
+		},
+		{
+			name:  "longer cycle below the root",
+			graph: map[string][]string{"a": {"b"}, "b": {"c"}, "c": {"d"}, "d": {"b"}},
+			want:  []string{"b", "c", "d", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindCycle(tt.graph); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindCycle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSatisfies(t *testing.T) {
+	tests := []struct {
+		version     string
+		requirement string
+		want        bool
+	}{
+		{"1.15.1", ">=1.0.0,<2.0.0", true},
+		{"2.0.0", ">=1.0.0,<2.0.0", false},
+		{"0.5.0", ">=1.0.0,<2.0.0", false},
+		{"1.0.0rc1", ">=1.0.0,<2.0.0", true},
+		{"3.12.1", ">=3.10", true},
+		{"3.9.18", ">=3.10", false},
+		{"1.2", "==1.2.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+tt.requirement, func(t *testing.T) {
+			if got := Satisfies(tt.version, tt.requirement); got != tt.want {
+				t.Errorf("Satisfies(%q, %q) = %v, want %v", tt.version, tt.requirement, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoctor_CheckPackages(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantStatus Status
+		wantDetail string
+	}{
+		{
+			name: "all packages importable",
+			files: map[string]string{
+				"coordinator/__init__.py": "from . import agent\n",
+				"coordinator/agent.py":    "root_agent = None\n",
+				"researcher/__init__.py":  "from . import agent\n",
+				"researcher/agent.py":     "agent = None\n",
+			},
+			wantStatus: Pass,
+			wantDetail: "coordinator, researcher",
+		},
+		{
+			name: "missing __init__.py",
+			files: map[string]string{
+				"coordinator/__init__.py": "from . import agent\n",
+				"coordinator/agent.py":    "root_agent = None\n",
+				"researcher/agent.py":     "agent = None\n",
+			},
+			wantStatus: Fail,
+			wantDetail: "missing __init__.py in researcher",
+		},
+		{
+			name: "__init__.py without agent import",
+			files: map[string]string{
+				"coordinator/__init__.py": "",
+				"coordinator/agent.py":    "root_agent = None\n",
+			},
+			wantStatus: Warn,
+			wantDetail: "__init__.py does not import agent in coordinator",
+		},
+		{
+			name:       "no agent packages",
+			files:      map[string]string{"main.py": "print()\n"},
+			wantStatus: Fail,
+			wantDetail: "no <package>/agent.py found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+
+			_, result := newTestDoctor(dir, nil).CheckPackages()
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("CheckPackages() status = %v, want %v (%s)", result.Status, tt.wantStatus, result.Detail)
+			}
+			if !strings.Contains(result.Detail, tt.wantDetail) {
+				t.Errorf("CheckPackages() detail = %q, want it to contain %q", result.Detail, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestDoctor_CheckImports(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantStatus Status
+		wantDetail string
+	}{
+		{
+			name: "tree of imports",
+			files: map[string]string{
+				"coordinator/agent.py": "from researcher.agent import agent as researcher\nfrom writer.agent import agent as writer\n",
+				"researcher/agent.py":  "from google.adk.agents import LlmAgent\n",
+				"writer/agent.py":      "from google.adk.agents import LlmAgent\n",
+			},
+			wantStatus: Pass,
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"researcher/agent.py": "from writer.agent import agent as writer\n",
+				"writer/agent.py":     "import researcher.agent\n",
+			},
+			wantStatus: Fail,
+			wantDetail: "import cycle: researcher → writer → researcher",
+		},
+		{
+			name: "syntax error",
+			files: map[string]string{
+				"researcher/agent.py": "agent = LlmAgent(\n",
+			},
+			wantStatus: Fail,
+			wantDetail: "researcher/agent.py",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+
+			d := newTestDoctor(dir, nil)
+			packages, _ := d.CheckPackages()
+			result := d.CheckImports(packages)
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("CheckImports() status = %v, want %v (%s)", result.Status, tt.wantStatus, result.Detail)
+			}
+			if !strings.Contains(result.Detail, tt.wantDetail) {
+				t.Errorf("CheckImports() detail = %q, want it to contain %q", result.Detail, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestDoctor_CheckManifest(t *testing.T) {
+	orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.5-flash")
+	orch.AddSubAgent(model.NewAgent("Researcher", model.AgentTypeLLM, "Research", "research", "gemini-2.5-flash"))
+	project := model.NewProject("demo", orch)
+
+	files := []generator.File{
+		{Path: "coordinator/agent.py", Content: "root_agent = None\n"},
+		{Path: "researcher/agent.py", Content: "agent = None\n"},
+		{Path: "main.py", Content: "print()\n"},
+	}
+	manifest, err := spec.ManifestFile(project, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		edit       func(dir string)
+		noManifest bool
+		wantStatus Status
+		wantDetail string
+	}{
+		{
+			name:       "unchanged",
+			wantStatus: Pass,
+			wantDetail: "3 generated files unchanged",
+		},
+		{
+			name: "edited and deleted files",
+			edit: func(dir string) {
+				os.WriteFile(filepath.Join(dir, "researcher", "agent.py"), []byte("agent = 1\n"), 0644)
+				os.Remove(filepath.Join(dir, "main.py"))
+			},
+			wantStatus: Warn,
+			wantDetail: "modified: researcher/agent.py; deleted: main.py",
+		},
+		{
+			name: "older spec format",
+			edit: func(dir string) {
+				path := filepath.Join(dir, spec.ManifestName)
+				data, _ := os.ReadFile(path)
+				old := strings.Replace(string(data), "apiVersion: "+spec.APIVersion+"\n", "", 1)
+				os.WriteFile(path, []byte(old), 0644)
+			},
+			wantStatus: Warn,
+			wantDetail: "written in spec format agent-builder/v0",
+		},
+		{
+			name:       "no manifest",
+			noManifest: true,
+			wantStatus: Warn,
+			wantDetail: "no agent-builder.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := generator.WriteFiles(dir, files); err != nil {
+				t.Fatal(err)
+			}
+			if !tt.noManifest {
+				if err := generator.WriteFiles(dir, []generator.File{manifest}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.edit != nil {
+				tt.edit(dir)
+			}
+
+			result := newTestDoctor(dir, nil).CheckManifest()
+
+			if result.Status != tt.wantStatus {
+				t.Errorf("CheckManifest() status = %v, want %v (%s)", result.Status, tt.wantStatus, result.Detail)
+			}
+			if !strings.Contains(result.Detail, tt.wantDetail) {
+				t.Errorf("CheckManifest() detail = %q, want it to contain %q", result.Detail, tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestDoctor_CheckCredentials(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		dotenv   string
+		services *model.Services
+		want     map[string]Status
+	}{
+		{
+			name: "api key in environment",
+			env:  map[string]string{"GOOGLE_API_KEY": "abc"},
+			want: map[string]Status{"GOOGLE_API_KEY": Pass},
+		},
+		{
+			name:   "placeholder api key in .env",
+			dotenv: "GOOGLE_GENAI_USE_VERTEXAI=FALSE\nGOOGLE_API_KEY=your-api-key\n",
+			want:   map[string]Status{"GOOGLE_API_KEY": Warn},
+		},
+		{
+			name:   "vertex ai from .env",
+			dotenv: "# comment\nexport GOOGLE_GENAI_USE_VERTEXAI=TRUE\nGOOGLE_CLOUD_PROJECT=\"my-project\"\n",
+			want:   map[string]Status{"GOOGLE_CLOUD_PROJECT": Pass, "GOOGLE_CLOUD_LOCATION": Warn},
+		},
+		{
+			name: "nothing set",
+			want: map[string]Status{"GOOGLE_API_KEY": Warn},
+		},
+		{
+			name: "vertex ai services",
+			services: &model.Services{
+				Session:  model.SessionVertexAI,
+				Memory:   model.MemoryVertexAIRAG,
+				Artifact: model.ArtifactGCS,
+			},
+			env:    map[string]string{"GOOGLE_CLOUD_PROJECT": "my-project", "GOOGLE_CLOUD_LOCATION": "us-central1", "AGENT_ENGINE_ID": "123"},
+			dotenv: "RAG_CORPUS=projects/your-project-id/locations/us-central1/ragCorpora/your-corpus-id\n",
+			want: map[string]Status{
+				"GOOGLE_CLOUD_PROJECT":  Pass,
+				"GOOGLE_CLOUD_LOCATION": Pass,
+				"AGENT_ENGINE_ID":       Pass,
+				"RAG_CORPUS":            Warn,
+				"ARTIFACT_BUCKET":       Warn,
+			},
+		},
+		{
+			name: "bucket in the spec",
+			services: &model.Services{
+				Session:  model.SessionInMemory,
+				Memory:   model.MemoryNone,
+				Artifact: model.ArtifactGCS,
+				Bucket:   "my-bucket",
+			},
+			env:  map[string]string{"GOOGLE_CLOUD_PROJECT": "my-project", "GOOGLE_CLOUD_LOCATION": "us-central1"},
+			want: map[string]Status{"GOOGLE_CLOUD_PROJECT": Pass, "GOOGLE_CLOUD_LOCATION": Pass},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.dotenv != "" {
+				writeFiles(t, dir, map[string]string{".env": tt.dotenv})
+			}
+			if tt.services != nil {
+				orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.5-flash")
+				orch.AddSubAgent(model.NewAgent("Researcher", model.AgentTypeLLM, "Research", "research", "gemini-2.5-flash"))
+				project := model.NewProject("demo", orch)
+				project.Backend = model.BackendVertexAI
+				project.Services = *tt.services
+				manifest, err := spec.ManifestFile(project, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				writeFiles(t, dir, map[string]string{manifest.Path: manifest.Content})
+			}
+
+			results := newTestDoctor(dir, tt.env).CheckCredentials()
+
+			got := make(map[string]Status)
+			for _, result := range results {
+				got[result.Name] = result.Status
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckCredentials() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDoctor_CheckPython(t *testing.T) {
+	d := newTestDoctor(t.TempDir(), nil)
+	d.Python = ""
+
+	if result := d.CheckPython(); result.Status != Fail {
+		t.Errorf("CheckPython() without Python status = %v, want fail", result.Status)
+	}
+	if result := d.CheckADK(); result.Status != Fail {
+		t.Errorf("CheckADK() without Python status = %v, want fail", result.Status)
+	}
+
+	path, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not available")
+	}
+	d.Python = path
+
+	result := d.CheckPython()
+	if !strings.Contains(result.Detail, path) {
+		t.Errorf("CheckPython() detail = %q, want it to name %s", result.Detail, path)
+	}
+}
+
+func TestWorst(t *testing.T) {
+	sections := []Section{
+		{Results: []Result{{Status: Pass}, {Status: Warn}}},
+		{Results: []Result{{Status: Pass}}},
+	}
+	if got := Worst(sections); got != Warn {
+		t.Errorf("Worst() = %v, want warn", got)
+	}
+
+	sections[1].Results = append(sections[1].Results, Result{Status: Fail})
+	if got := Worst(sections); got != Fail {
+		t.Errorf("Worst() = %v, want fail", got)
+	}
+}
@@ -3,33 +3,341 @@ package generator
 import (
 	"bytes"
 	"embed"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 
+	"github.com/doji-co/agent-builder/internal/adk"
 	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/naming"
+	"github.com/doji-co/agent-builder/internal/pysyntax"
 )
 
 //go:embed templates/*
 var templatesFS embed.FS
 
+// PythonRequirement is the interpreter range every generated project
+// declares.
+const PythonRequirement = ">=3.10"
+
+const pythonVersion = "3.12"
+
 type Generator struct {
 	templates *template.Template
+	checker   pysyntax.Checker
+}
+
+type File struct {
+	Path     string
+	Template string
+	Content  string
 }
 
 func NewGenerator() *Generator {
+	return NewGeneratorWithChecker(pysyntax.NewChecker())
+}
+
+// NewGeneratorWithChecker returns a generator that verifies the Python it
+// renders with checker.
+func NewGeneratorWithChecker(checker pysyntax.Checker) *Generator {
 	tmpl := template.Must(template.New("").Funcs(template.FuncMap{
-		"lower":          strings.ToLower,
-		"snakeCase":      toSnakeCase,
-		"getAgentClass":  getAgentClass,
-		"getImports":     getImports,
+		"lower":             strings.ToLower,
+		"snakeCase":         naming.SnakeCase,
+		"pascalCase":        naming.PascalCase,
+		"kebabCase":         naming.KebabCase,
+		"getAgentClass":     getAgentClass,
+		"getImports":        getImports,
+		"evalCases":         evalCases,
+		"stateKeys":         stateKeys,
+		"agentSeed":         agentSeed,
+		"orchestratorSeed":  orchestratorSeed,
+		"json":              toJSON,
+		"pythonRequirement": func() string { return PythonRequirement },
+		"pythonVersion":     func() string { return pythonVersion },
+		"adk":               adkVersion,
+		"inputModel":        inputModel,
+		"outputModel":       outputModel,
+		"outputSample":      outputSample,
+		"routingLines":      routingLines,
+		"localData":         localData,
 	}).ParseFS(templatesFS, "templates/*.tmpl"))
 
 	return &Generator{
 		templates: tmpl,
+		checker:   checker,
 	}
 }
 
+func (g *Generator) RenderProject(project *model.Project) ([]File, error) {
+	if err := ValidateIdentifiers(project); err != nil {
+		return nil, err
+	}
+	if err := project.Validate(); err != nil {
+		return nil, err
+	}
+
+	initPy, err := g.GenerateInitPy()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+
+	orchPy, err := g.GenerateOrchestratorPy(project.Orchestrator)
+	if err != nil {
+		return nil, err
+	}
+	orchFolderName := naming.SnakeCase(project.Orchestrator.Name)
+	files = append(files,
+		File{Path: filepath.Join(orchFolderName, "__init__.py"), Template: "init.py.tmpl", Content: initPy},
+		File{Path: filepath.Join(orchFolderName, "agent.py"), Template: "orchestrator_agent.py.tmpl", Content: orchPy},
+	)
+
+	for _, agent := range project.Orchestrator.SubAgents {
+		agentFiles, err := g.renderAgent(withDefaults(project.Orchestrator, agent))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: filepath.Join(naming.SnakeCase(agent.Name), "__init__.py"), Template: "init.py.tmpl", Content: initPy})
+		files = append(files, agentFiles...)
+	}
+
+	if project.AddExample {
+		mainPy, err := g.GenerateMainPy(project)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: "main.py", Template: "main.py.tmpl", Content: mainPy})
+
+		if project.Services.Artifact == model.ArtifactLocal {
+			artifactsPy, err := g.GenerateLocalArtifactsPy(project)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, File{Path: "local_artifacts.py", Template: "local_artifacts.py.tmpl", Content: artifactsPy})
+		}
+	}
+
+	if project.AddEval {
+		evalSet, err := g.GenerateEvalSet(project)
+		if err != nil {
+			return nil, err
+		}
+		evalConfig, err := g.GenerateEvalConfig()
+		if err != nil {
+			return nil, err
+		}
+		evalTestPy, err := g.GenerateEvalTestPy(project)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files,
+			File{Path: filepath.Join("eval", orchFolderName+".test.json"), Template: "eval.test.json.tmpl", Content: evalSet},
+			File{Path: filepath.Join("eval", "test_config.json"), Template: "test_config.json.tmpl", Content: evalConfig},
+			File{Path: filepath.Join("eval", "test_eval.py"), Template: "test_eval.py.tmpl", Content: evalTestPy},
+		)
+	}
+
+	if project.AddTests {
+		conftestPy, err := g.GenerateConftestPy(project)
+		if err != nil {
+			return nil, err
+		}
+		orchTestPy, err := g.GenerateOrchestratorTestPy(project.Orchestrator)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files,
+			File{Path: filepath.Join("tests", "conftest.py"), Template: "conftest.py.tmpl", Content: conftestPy},
+			File{Path: filepath.Join("tests", "test_"+orchFolderName+".py"), Template: "test_orchestrator.py.tmpl", Content: orchTestPy},
+		)
+
+		for _, agent := range project.Orchestrator.SubAgents {
+			agentTestPy, err := g.GenerateAgentTestPy(project.Orchestrator, agent)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, File{
+				Path:     filepath.Join("tests", "test_"+naming.SnakeCase(agent.Name)+".py"),
+				Template: "test_agent.py.tmpl",
+				Content:  agentTestPy,
+			})
+		}
+	}
+
+	if project.AddTests || project.AddEval {
+		pytestIni, err := g.GeneratePytestIni(project)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: "pytest.ini", Template: "pytest.ini.tmpl", Content: pytestIni})
+	}
+
+	packagingFiles, err := g.renderPackaging(project)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, packagingFiles...)
+
+	envExample, err := g.GenerateEnvExample(project)
+	if err != nil {
+		return nil, err
+	}
+	gitignore, err := g.GenerateGitignore(project)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files,
+		File{Path: ".env.example", Template: "env.example.tmpl", Content: envExample},
+		File{Path: ".gitignore", Template: "gitignore.tmpl", Content: gitignore},
+	)
+
+	if project.AddReadme {
+		readme, err := g.GenerateReadme(project)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: "README.md", Template: "README.md.tmpl", Content: readme})
+	}
+
+	if err := g.Verify(files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func (g *Generator) renderPackaging(project *model.Project) ([]File, error) {
+	var files []File
+
+	switch project.Packaging {
+	case model.PackagingUV, model.PackagingPoetry:
+		pyproject, err := g.GeneratePyprojectToml(project)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: "pyproject.toml", Template: "pyproject.toml.tmpl", Content: pyproject})
+	default:
+		requirementsTxt, err := g.GenerateRequirementsTxt(project)
+		if err != nil {
+			return nil, err
+		}
+		requirementsDevTxt, err := g.GenerateRequirementsDevTxt(project)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files,
+			File{Path: "requirements.txt", Template: "requirements.txt.tmpl", Content: requirementsTxt},
+			File{Path: "requirements-dev.txt", Template: "requirements-dev.txt.tmpl", Content: requirementsDevTxt},
+		)
+	}
+
+	// Poetry pins the interpreter in pyproject.toml; pip and uv read
+	// .python-version.
+	if project.Packaging != model.PackagingPoetry {
+		pythonVersionFile, err := g.GeneratePythonVersion()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: ".python-version", Template: "python-version.tmpl", Content: pythonVersionFile})
+	}
+
+	return files, nil
+}
+
+func (g *Generator) RenderSingleAgent(agent *model.Agent) ([]File, error) {
+	if err := CheckIdentifier(agent.Name, nil); err != nil {
+		return nil, err
+	}
+	if err := checkAgentCode(agent); err != nil {
+		return nil, err
+	}
+
+	files, err := g.renderAgent(agent)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.Verify(files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// renderAgent renders a sub-agent's agent.py, plus schemas.py, callbacks.py
+// and tools.py when it declares schemas, callbacks or tools, and its prompt
+// file when the instruction is kept out of agent.py.
+func (g *Generator) renderAgent(agent *model.Agent) ([]File, error) {
+	folder := naming.SnakeCase(agent.Name)
+
+	agentPy, err := g.GenerateSubAgentPy(agent)
+	if err != nil {
+		return nil, err
+	}
+	files := []File{{Path: filepath.Join(folder, "agent.py"), Template: "agent_single.py.tmpl", Content: agentPy}}
+
+	if agent.PromptFile {
+		files = append(files, File{Path: PromptPath(agent.Name), Content: strings.TrimSpace(agent.Instruction) + "\n"})
+	}
+
+	schemasPy, err := g.GenerateSchemasPy(agent)
+	if err != nil {
+		return nil, err
+	}
+	if schemasPy != "" {
+		files = append(files, File{Path: filepath.Join(folder, "schemas.py"), Template: "schemas.py.tmpl", Content: schemasPy})
+	}
+
+	if len(agent.Callbacks) > 0 {
+		callbacksPy, err := g.GenerateCallbacksPy(agent)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: filepath.Join(folder, "callbacks.py"), Template: "callbacks.py.tmpl", Content: callbacksPy})
+	}
+
+	if len(agent.Tools) > 0 {
+		toolsPy, err := g.GenerateToolsPy(agent)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Path: filepath.Join(folder, "tools.py"), Template: "tools.py.tmpl", Content: toolsPy})
+	}
+	return files, nil
+}
+
+// PromptPath is where the instruction of the named agent is written when it
+// uses a prompt file.
+func PromptPath(name string) string {
+	return filepath.Join("prompts", naming.SnakeCase(name)+".md")
+}
+
+// withDefaults returns a copy of agent whose generation config includes the
+// orchestrator's defaults.
+func withDefaults(orchestrator *model.Orchestrator, agent *model.Agent) *model.Agent {
+	effective := *agent
+	effective.GenerationConfig = orchestrator.GenerationConfig.Merge(agent.GenerationConfig)
+	return &effective
+}
+
+// WriteFiles writes rendered files below root, creating directories as
+// needed.
+func WriteFiles(root string, files []File) error {
+	for _, file := range files {
+		path := filepath.Join(root, file.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", file.Path, err)
+		}
+		if err := os.WriteFile(path, []byte(file.Content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Path, err)
+		}
+	}
+	return nil
+}
+
 func (g *Generator) GenerateAgentPy(project *model.Project) (string, error) {
 	var buf bytes.Buffer
 	err := g.templates.ExecuteTemplate(&buf, "agent.py.tmpl", project)
@@ -57,6 +365,49 @@ func (g *Generator) GenerateSubAgentPy(agent *model.Agent) (string, error) {
 	return buf.String(), nil
 }
 
+// GenerateSchemasPy renders the Pydantic models of the agent's input and
+// output schemas. It returns "" when the agent has neither.
+func (g *Generator) GenerateSchemasPy(agent *model.Agent) (string, error) {
+	module, err := agentSchemas(agent)
+	if err != nil || module == nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	err = g.templates.ExecuteTemplate(&buf, "schemas.py.tmpl", module)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate schemas.py: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GenerateToolsPy(agent *model.Agent) (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "tools.py.tmpl", agent)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate tools.py: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GenerateCallbacksPy(agent *model.Agent) (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "callbacks.py.tmpl", agent)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate callbacks.py: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GenerateLocalArtifactsPy(project *model.Project) (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "local_artifacts.py.tmpl", project)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate local_artifacts.py: %w", err)
+	}
+	return buf.String(), nil
+}
+
 func (g *Generator) GenerateMainPy(project *model.Project) (string, error) {
 	var buf bytes.Buffer
 	err := g.templates.ExecuteTemplate(&buf, "main.py.tmpl", project)
@@ -66,15 +417,137 @@ func (g *Generator) GenerateMainPy(project *model.Project) (string, error) {
 	return buf.String(), nil
 }
 
-func (g *Generator) GenerateRequirementsTxt() (string, error) {
+func (g *Generator) GenerateRequirementsTxt(project *model.Project) (string, error) {
 	var buf bytes.Buffer
-	err := g.templates.ExecuteTemplate(&buf, "requirements.txt.tmpl", nil)
+	err := g.templates.ExecuteTemplate(&buf, "requirements.txt.tmpl", project)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate requirements.txt: %w", err)
 	}
 	return buf.String(), nil
 }
 
+func (g *Generator) GenerateInitPy() (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "init.py.tmpl", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate __init__.py: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GenerateEvalSet(project *model.Project) (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "eval.test.json.tmpl", project)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate evaluation set: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GenerateEvalConfig() (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "test_config.json.tmpl", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate test_config.json: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GenerateEvalTestPy(project *model.Project) (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "test_eval.py.tmpl", project)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate eval test: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GeneratePytestIni(project *model.Project) (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "pytest.ini.tmpl", project)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate pytest.ini: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GenerateConftestPy(project *model.Project) (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "conftest.py.tmpl", project)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate conftest.py: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GenerateOrchestratorTestPy(orchestrator *model.Orchestrator) (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "test_orchestrator.py.tmpl", orchestrator)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate orchestrator test: %w", err)
+	}
+	return buf.String(), nil
+}
+
+type agentTestData struct {
+	Orchestrator *model.Orchestrator
+	Agent        *model.Agent
+}
+
+func (g *Generator) GenerateAgentTestPy(orchestrator *model.Orchestrator, agent *model.Agent) (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "test_agent.py.tmpl", agentTestData{Orchestrator: orchestrator, Agent: agent})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate test for %s: %w", agent.Name, err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GenerateRequirementsDevTxt(project *model.Project) (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "requirements-dev.txt.tmpl", project)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate requirements-dev.txt: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GeneratePyprojectToml(project *model.Project) (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "pyproject.toml.tmpl", project)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate pyproject.toml: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GeneratePythonVersion() (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "python-version.tmpl", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate .python-version: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GenerateEnvExample(project *model.Project) (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "env.example.tmpl", project)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate .env.example: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (g *Generator) GenerateGitignore(project *model.Project) (string, error) {
+	var buf bytes.Buffer
+	err := g.templates.ExecuteTemplate(&buf, "gitignore.tmpl", project)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate .gitignore: %w", err)
+	}
+	return buf.String(), nil
+}
+
 func (g *Generator) GenerateReadme(project *model.Project) (string, error) {
 	var buf bytes.Buffer
 	err := g.templates.ExecuteTemplate(&buf, "README.md.tmpl", project)
@@ -84,17 +557,8 @@ func (g *Generator) GenerateReadme(project *model.Project) (string, error) {
 	return buf.String(), nil
 }
 
-func toSnakeCase(s string) string {
-	s = strings.ReplaceAll(s, "-", "_")
-
-	var result strings.Builder
-	for i, r := range s {
-		if i > 0 && r >= 'A' && r <= 'Z' {
-			result.WriteRune('_')
-		}
-		result.WriteRune(r)
-	}
-	return strings.ToLower(result.String())
+func adkVersion(project *model.Project) (adk.Version, error) {
+	return adk.Lookup(project.ADKVersion)
 }
 
 func getAgentClass(pattern model.OrchestrationPattern) string {
@@ -112,6 +576,60 @@ func getAgentClass(pattern model.OrchestrationPattern) string {
 	}
 }
 
+var routingBraces = strings.NewReplacer("{", "", "}", "")
+
+// RoutingInstruction is the instruction generated for an llm-coordinated
+// orchestrator.
+func RoutingInstruction(orchestrator *model.Orchestrator) string {
+	return strings.Join(routingLines(orchestrator), "")
+}
+
+// routingLines is the instruction of an llm-coordinated orchestrator, one
+// line per element, listing each sub-agent and when to transfer to it. Agents
+// without a description are described by the first line of their
+// instruction. Braces are dropped so that the coordinator does not read the
+// sub-agents' placeholders from state.
+func routingLines(orchestrator *model.Orchestrator) []string {
+	lines := []string{
+		"You coordinate a team of sub-agents. Do not answer requests yourself;",
+		"transfer each request to the sub-agent best suited to it:",
+		"",
+	}
+	for _, agent := range orchestrator.SubAgents {
+		when := agent.Description
+		if when == "" {
+			when, _, _ = strings.Cut(strings.TrimSpace(agent.Instruction), "\n")
+		}
+		when = strings.TrimSpace(routingBraces.Replace(when))
+		if when == "" {
+			when = "no description"
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s", naming.SnakeCase(agent.Name), when))
+	}
+	lines = append(lines, "", "If no sub-agent fits, ask the user to clarify the request.")
+
+	for i := range lines[:len(lines)-1] {
+		lines[i] += "\n"
+	}
+	return lines
+}
+
+// localData lists the files main.py writes inside the project: a relative
+// SQLite session database and the local artifact directory.
+func localData(services model.Services) []string {
+	var paths []string
+	if services.Session == model.SessionDatabase {
+		url := services.DatabaseURLOrDefault()
+		if file, ok := strings.CutPrefix(url, "sqlite:///"); ok && !strings.HasPrefix(file, "/") {
+			paths = append(paths, strings.TrimPrefix(file, "./"))
+		}
+	}
+	if services.Artifact == model.ArtifactLocal && !filepath.IsAbs(services.ArtifactDirOrDefault()) {
+		paths = append(paths, strings.TrimPrefix(filepath.ToSlash(services.ArtifactDirOrDefault()), "./")+"/")
+	}
+	return paths
+}
+
 func getImports(project *model.Project) string {
 	imports := []string{"LlmAgent"}
 
@@ -122,3 +640,89 @@ func getImports(project *model.Project) string {
 
 	return strings.Join(imports, ", ")
 }
+
+type evalCase struct {
+	ID       string
+	Prompt   string
+	Expected string
+}
+
+// evalCases builds the evaluation set from the examples collected for each
+// sub-agent. An agent without examples adds no case: a made-up expected
+// answer would only make the evaluation fail.
+func evalCases(orchestrator *model.Orchestrator) []evalCase {
+	var cases []evalCase
+	for _, agent := range orchestrator.SubAgents {
+		id := naming.SnakeCase(agent.Name)
+		for i, example := range agent.Examples {
+			cases = append(cases, evalCase{
+				ID:       fmt.Sprintf("%s_%d", id, i+1),
+				Prompt:   example.Prompt,
+				Expected: example.Expected,
+			})
+		}
+	}
+	return cases
+}
+
+// testSeed is what a generated test puts in the session before a run so that
+// the placeholders in the instructions resolve: state keys and artifact
+// names, each seeded with an example value.
+type testSeed struct {
+	State     []string
+	Artifacts []string
+}
+
+// agentSeed seeds a run of one sub-agent: the keys every sub-agent writes,
+// any other key its instruction reads and the artifacts it reads.
+func agentSeed(orchestrator *model.Orchestrator, agent *model.Agent) testSeed {
+	seed := testSeed{State: stateKeys(orchestrator)}
+	seed.add(agent.Instruction, seed.State)
+	return seed
+}
+
+// orchestratorSeed seeds a run of the whole tree: the keys no agent writes
+// before they are read, such as those a tool or callback would write, and the
+// artifacts any instruction reads.
+func orchestratorSeed(orchestrator *model.Orchestrator) testSeed {
+	var seed testSeed
+	seed.add(orchestrator.GlobalInstruction, nil)
+	for i, agent := range orchestrator.SubAgents {
+		seed.add(agent.Instruction, orchestrator.StateKeysBefore(i))
+	}
+	return seed
+}
+
+func (s *testSeed) add(instruction string, known []string) {
+	for _, p := range model.Placeholders(instruction) {
+		switch {
+		case p.Optional || p.Key == "":
+		case p.Artifact:
+			if !slices.Contains(s.Artifacts, p.Key) {
+				s.Artifacts = append(s.Artifacts, p.Key)
+			}
+		case !slices.Contains(known, p.Key) && !slices.Contains(s.State, p.Key):
+			s.State = append(s.State, p.Key)
+		}
+	}
+}
+
+func toJSON(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// stateKeys lists the output keys the sub-agents write to session state, in
+// the order they are declared.
+func stateKeys(orchestrator *model.Orchestrator) []string {
+	var keys []string
+	for _, agent := range orchestrator.SubAgents {
+		if agent.OutputKey != "" {
+			keys = append(keys, agent.OutputKey)
+		}
+	}
+	return keys
+}
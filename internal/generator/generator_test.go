@@ -1,8 +1,12 @@
 package generator
 
 import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
 	"strings"
 	"testing"
+	"text/template"
 
 	"github.com/doji-co/agent-builder/internal/model"
 )
@@ -70,8 +74,19 @@ func TestGenerator_GenerateMainPy(t *testing.T) {
 	t.Logf("Generated main.py:\n%s", content)
 
 	expectedStrings := []string{
-		"from coordinator.agent import agent as root_agent",
-		"root_agent.run(",
+		"from coordinator.agent import root_agent",
+		"load_dotenv()",
+		"from google.adk.runners import Runner",
+		"from google.adk.sessions import InMemorySessionService",
+		"return Runner(\n        agent=root_agent,\n        app_name=APP_NAME,\n        session_service=session_service,\n        artifact_service=artifact_service,\n    )",
+		"session = await runner.session_service.create_session(",
+		"async for event in runner.run_async(",
+		"event.is_final_response()",
+		`OUTPUT_KEYS = ["result"]`,
+		`"--user-id"`,
+		`"--session-id"`,
+		`"--interactive"`,
+		"asyncio.run(run(parse_args()))",
 		`if __name__ == "__main__":`,
 	}
 
@@ -80,11 +95,31 @@ func TestGenerator_GenerateMainPy(t *testing.T) {
 			t.Errorf("GenerateMainPy() missing expected string: %s", expected)
 		}
 	}
+
+	if strings.Contains(content, "root_agent.run(") {
+		t.Error("GenerateMainPy() should run the agent through a Runner, not root_agent.run()")
+	}
+
+	project.ADKVersion = "0.5"
+	project.AddEval = false
+	content, err = gen.GenerateMainPy(project)
+	if err != nil {
+		t.Fatalf("GenerateMainPy() error = %v", err)
+	}
+
+	if !strings.Contains(content, "session = runner.session_service.create_session(") {
+		t.Error("GenerateMainPy() should call the session service synchronously for ADK 0.5")
+	}
 }
 
 func TestGenerator_GenerateRequirementsTxt(t *testing.T) {
+	orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.0-flash")
+	orch.AddSubAgent(model.NewAgent("Agent1", model.AgentTypeLLM, "Task", "result", "gemini-2.0-flash"))
+
+	project := model.NewProject("test-project", orch)
+
 	gen := NewGenerator()
-	content, err := gen.GenerateRequirementsTxt()
+	content, err := gen.GenerateRequirementsTxt(project)
 
 	if err != nil {
 		t.Fatalf("GenerateRequirementsTxt() error = %v", err)
@@ -94,8 +129,221 @@ func TestGenerator_GenerateRequirementsTxt(t *testing.T) {
 		t.Error("GenerateRequirementsTxt() returned empty content")
 	}
 
-	if !strings.Contains(content, "google-adk") {
-		t.Error("GenerateRequirementsTxt() missing google-adk dependency")
+	if !strings.Contains(content, "google-adk>=1.0.0,<2.0.0") {
+		t.Error("GenerateRequirementsTxt() missing pinned google-adk dependency")
+	}
+
+	if !strings.Contains(content, "python-dotenv") {
+		t.Error("GenerateRequirementsTxt() missing python-dotenv for loading .env")
+	}
+
+	if strings.Contains(content, "pytest") {
+		t.Error("GenerateRequirementsTxt() should leave test dependencies to requirements-dev.txt")
+	}
+}
+
+func TestGenerator_GenerateRequirementsDevTxt(t *testing.T) {
+	orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.0-flash")
+	orch.AddSubAgent(model.NewAgent("Agent1", model.AgentTypeLLM, "Task", "result", "gemini-2.0-flash"))
+
+	project := model.NewProject("test-project", orch)
+	project.AddEval = false
+	project.AddTests = false
+
+	gen := NewGenerator()
+	content, err := gen.GenerateRequirementsDevTxt(project)
+
+	if err != nil {
+		t.Fatalf("GenerateRequirementsDevTxt() error = %v", err)
+	}
+
+	for _, expected := range []string{"-r requirements.txt", "ruff"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("GenerateRequirementsDevTxt() missing expected string: %s", expected)
+		}
+	}
+
+	if strings.Contains(content, "pytest") {
+		t.Error("GenerateRequirementsDevTxt() should not include pytest without tests or an evaluation set")
+	}
+
+	project.AddEval = true
+	content, err = gen.GenerateRequirementsDevTxt(project)
+	if err != nil {
+		t.Fatalf("GenerateRequirementsDevTxt() error = %v", err)
+	}
+
+	for _, expected := range []string{"google-adk[eval]>=1.0.0,<2.0.0", "pytest", "pytest-asyncio"} {
+		if !strings.Contains(content, expected) {
+			t.Errorf("GenerateRequirementsDevTxt() missing expected string: %s", expected)
+		}
+	}
+}
+
+func TestGenerator_GeneratePyprojectToml(t *testing.T) {
+	tests := []struct {
+		name      string
+		packaging model.Packaging
+		artifact  model.ArtifactService
+		prompts   bool
+		expected  []string
+	}{
+		{
+			name:      "uv",
+			packaging: model.PackagingUV,
+			expected: []string{
+				"[project]",
+				`name = "research-assistant"`,
+				`requires-python = ">=3.10"`,
+				`"google-adk>=1.0.0,<2.0.0",`,
+				"[project.scripts]",
+				`research-assistant = "main:main"`,
+				"[dependency-groups]",
+				`"google-adk[eval]>=1.0.0,<2.0.0",`,
+				`"pytest>=8.0",`,
+				`"ruff>=0.5",`,
+				`"research_coordinator",`,
+				`"researcher",`,
+			},
+		},
+		{
+			name:      "poetry",
+			packaging: model.PackagingPoetry,
+			expected: []string{
+				"[tool.poetry]",
+				`name = "research-assistant"`,
+				`python = ">=3.10,<4.0"`,
+				`google-adk = { version = ">=1.0.0,<2.0.0", extras = ["eval"] }`,
+				"[tool.poetry.group.dev.dependencies]",
+				`pytest = ">=8.0"`,
+				`ruff = ">=0.5"`,
+				"[tool.poetry.scripts]",
+				`research-assistant = "main:main"`,
+				`{ include = "researcher" },`,
+				`{ include = "main.py" },`,
+			},
+		},
+		{
+			name:      "uv with local artifacts",
+			packaging: model.PackagingUV,
+			artifact:  model.ArtifactLocal,
+			expected:  []string{`"main.py",`, `"local_artifacts.py",`},
+		},
+		{
+			name:      "poetry with local artifacts",
+			packaging: model.PackagingPoetry,
+			artifact:  model.ArtifactLocal,
+			expected:  []string{`{ include = "main.py" },`, `{ include = "local_artifacts.py" },`},
+		},
+		{
+			name:      "uv with prompt files",
+			packaging: model.PackagingUV,
+			prompts:   true,
+			expected:  []string{`"prompts",`},
+		},
+		{
+			name:      "poetry with prompt files",
+			packaging: model.PackagingPoetry,
+			prompts:   true,
+			expected:  []string{`include = [{ path = "prompts/*.md", format = ["sdist", "wheel"] }]`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orch := model.NewOrchestrator("ResearchCoordinator", model.PatternSequential, "Coordinates \"research\"", "gemini-2.0-flash")
+			researcher := model.NewAgent("Researcher", model.AgentTypeLLM, "Research the topic", "research_data", "gemini-2.0-flash")
+			researcher.PromptFile = tt.prompts
+			orch.AddSubAgent(researcher)
+
+			project := model.NewProject("research_assistant", orch)
+			project.Packaging = tt.packaging
+			if tt.artifact != "" {
+				project.Services.Artifact = tt.artifact
+			}
+
+			gen := NewGenerator()
+			content, err := gen.GeneratePyprojectToml(project)
+
+			if err != nil {
+				t.Fatalf("GeneratePyprojectToml() error = %v", err)
+			}
+
+			t.Logf("Generated pyproject.toml:\n%s", content)
+
+			if !strings.Contains(content, `description = "Coordinates \"research\""`) {
+				t.Error("GeneratePyprojectToml() should escape quotes in the description")
+			}
+
+			for _, expected := range tt.expected {
+				if !strings.Contains(content, expected) {
+					t.Errorf("GeneratePyprojectToml() missing expected string: %s", expected)
+				}
+			}
+			if tt.artifact != model.ArtifactLocal && strings.Contains(content, "local_artifacts.py") {
+				t.Error("GeneratePyprojectToml() should only package local_artifacts.py with the local artifact service")
+			}
+			if !tt.prompts && strings.Contains(content, "prompts") {
+				t.Error("GeneratePyprojectToml() should only package prompts/ when an agent uses a prompt file")
+			}
+		})
+	}
+}
+
+func TestGenerator_RenderProject_Packaging(t *testing.T) {
+	tests := []struct {
+		packaging model.Packaging
+		want      []string
+		notWant   []string
+	}{
+		{
+			packaging: model.PackagingRequirements,
+			want:      []string{"requirements.txt", "requirements-dev.txt", ".python-version"},
+			notWant:   []string{"pyproject.toml"},
+		},
+		{
+			packaging: model.PackagingUV,
+			want:      []string{"pyproject.toml", ".python-version"},
+			notWant:   []string{"requirements.txt", "requirements-dev.txt"},
+		},
+		{
+			packaging: model.PackagingPoetry,
+			want:      []string{"pyproject.toml"},
+			notWant:   []string{"requirements.txt", "requirements-dev.txt", ".python-version"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.packaging), func(t *testing.T) {
+			orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.0-flash")
+			orch.AddSubAgent(model.NewAgent("Agent1", model.AgentTypeLLM, "Task", "result", "gemini-2.0-flash"))
+
+			project := model.NewProject("test-project", orch)
+			project.Packaging = tt.packaging
+
+			gen := NewGenerator()
+			files, err := gen.RenderProject(project)
+
+			if err != nil {
+				t.Fatalf("RenderProject() error = %v", err)
+			}
+
+			paths := map[string]bool{}
+			for _, file := range files {
+				paths[filepath.ToSlash(file.Path)] = true
+			}
+
+			for _, path := range tt.want {
+				if !paths[path] {
+					t.Errorf("RenderProject() missing %s", path)
+				}
+			}
+			for _, path := range tt.notWant {
+				if paths[path] {
+					t.Errorf("RenderProject() should not generate %s", path)
+				}
+			}
+		})
 	}
 }
 
@@ -269,3 +517,1371 @@ func TestGenerator_GenerateSubAgentPy(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerator_RenderProject(t *testing.T) {
+	orch := model.NewOrchestrator("ResearchCoordinator", model.PatternSequential, "Coordinates research tasks", "gemini-2.0-flash")
+	orch.AddSubAgent(model.NewAgent("Researcher", model.AgentTypeLLM, "Research the topic", "research_data", "gemini-2.0-flash"))
+	orch.AddSubAgent(model.NewAgent("Writer", model.AgentTypeLLM, "Write based on research", "draft", "gemini-2.0-flash"))
+
+	project := model.NewProject("test-project", orch)
+
+	gen := NewGenerator()
+	files, err := gen.RenderProject(project)
+
+	if err != nil {
+		t.Fatalf("RenderProject() error = %v", err)
+	}
+
+	expectedFiles := map[string]string{
+		"research_coordinator/__init__.py":    "init.py.tmpl",
+		"research_coordinator/agent.py":       "orchestrator_agent.py.tmpl",
+		"researcher/__init__.py":              "init.py.tmpl",
+		"researcher/agent.py":                 "agent_single.py.tmpl",
+		"writer/__init__.py":                  "init.py.tmpl",
+		"writer/agent.py":                     "agent_single.py.tmpl",
+		"main.py":                             "main.py.tmpl",
+		"eval/research_coordinator.test.json": "eval.test.json.tmpl",
+		"eval/test_config.json":               "test_config.json.tmpl",
+		"eval/test_eval.py":                   "test_eval.py.tmpl",
+		"tests/conftest.py":                   "conftest.py.tmpl",
+		"tests/test_research_coordinator.py":  "test_orchestrator.py.tmpl",
+		"tests/test_researcher.py":            "test_agent.py.tmpl",
+		"tests/test_writer.py":                "test_agent.py.tmpl",
+		"pytest.ini":                          "pytest.ini.tmpl",
+		"requirements.txt":                    "requirements.txt.tmpl",
+		"requirements-dev.txt":                "requirements-dev.txt.tmpl",
+		".python-version":                     "python-version.tmpl",
+		".env.example":                        "env.example.tmpl",
+		".gitignore":                          "gitignore.tmpl",
+		"README.md":                           "README.md.tmpl",
+	}
+
+	if len(files) != len(expectedFiles) {
+		t.Errorf("RenderProject() returned %d files, want %d", len(files), len(expectedFiles))
+	}
+
+	for _, file := range files {
+		tmpl, ok := expectedFiles[filepath.ToSlash(file.Path)]
+		if !ok {
+			t.Errorf("RenderProject() returned unexpected file %s", file.Path)
+			continue
+		}
+		if file.Template != tmpl {
+			t.Errorf("%s Template = %v, want %v", file.Path, file.Template, tmpl)
+		}
+		if file.Content == "" {
+			t.Errorf("%s has empty content", file.Path)
+		}
+	}
+}
+
+func TestGenerator_RenderProject_QuotedModel(t *testing.T) {
+	orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.0-flash")
+	orch.AddSubAgent(model.NewAgent("Writer", model.AgentTypeLLM, "Write an article", "draft", "gemini-2.0-flash\""))
+
+	files, err := NewGenerator().RenderProject(model.NewProject("test-project", orch))
+	if err != nil {
+		t.Fatalf("RenderProject() error = %v", err)
+	}
+	for _, file := range files {
+		if file.Path == filepath.Join("writer", "agent.py") && !strings.Contains(file.Content, `model="gemini-2.0-flash\""`) {
+			t.Errorf("writer/agent.py should escape the quote in the model:\n%s", file.Content)
+		}
+	}
+}
+
+func TestGenerator_RenderProject_InvalidPython(t *testing.T) {
+	orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.0-flash")
+	orch.AddSubAgent(model.NewAgent("Writer", model.AgentTypeLLM, "Write an article", "draft", "gemini-2.0-flash\""))
+
+	project := model.NewProject("test-project", orch)
+
+	// A template that forgets to quote a value renders invalid Python.
+	gen := NewGenerator()
+	gen.templates = template.Must(template.Must(gen.templates.Clone()).New("agent_single.py.tmpl").Parse(
+		"writer = LlmAgent(\n    model=\"{{ .Model }}\",\n)\n"))
+	_, err := gen.RenderProject(project)
+
+	if err == nil {
+		t.Fatal("RenderProject() expected error for unescaped quote in model")
+	}
+
+	var verifyErr *VerifyError
+	if !errors.As(err, &verifyErr) {
+		t.Fatalf("RenderProject() error type = %T, want *VerifyError", err)
+	}
+
+	if verifyErr.Template != "agent_single.py.tmpl" {
+		t.Errorf("Template = %v, want agent_single.py.tmpl", verifyErr.Template)
+	}
+	if verifyErr.Path != filepath.Join("writer", "agent.py") {
+		t.Errorf("Path = %v, want writer/agent.py", verifyErr.Path)
+	}
+	if !strings.Contains(verifyErr.Source, "model=") {
+		t.Errorf("Source = %q, want the offending model line", verifyErr.Source)
+	}
+	if !strings.Contains(err.Error(), "agent_single.py.tmpl") {
+		t.Errorf("Error() = %q, want template name", err.Error())
+	}
+}
+
+func TestGenerator_Verify(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []File
+		wantErr bool
+	}{
+		{
+			name: "valid python",
+			files: []File{
+				{Path: "main.py", Template: "main.py.tmpl", Content: "print('ok')\n"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "non-python files are not checked",
+			files: []File{
+				{Path: "README.md", Template: "README.md.tmpl", Content: "x = (\n"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unclosed bracket",
+			files: []File{
+				{Path: "coordinator/agent.py", Template: "orchestrator_agent.py.tmpl", Content: "agent = SequentialAgent(\n"},
+			},
+			wantErr: true,
+		},
+	}
+
+	gen := NewGenerator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := gen.Verify(tt.files)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckIdentifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		taken   []string
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid name",
+			input:   "DataFetcher",
+			wantErr: false,
+		},
+		{
+			name:    "valid name with distinct taken names",
+			input:   "Writer",
+			taken:   []string{"Coordinator", "Researcher"},
+			wantErr: false,
+		},
+		{
+			name:    "collides after snake_case conversion",
+			input:   "data_fetcher",
+			taken:   []string{"Coordinator", "Data-Fetcher"},
+			wantErr: true,
+			errMsg:  `agent name "data_fetcher" and "Data-Fetcher" both become "data_fetcher"; try "data_fetcher_2"`,
+		},
+		{
+			name:    "exact duplicate",
+			input:   "Writer",
+			taken:   []string{"Writer", "writer_2"},
+			wantErr: true,
+			errMsg:  `agent name "Writer" is already used; try "writer_3"`,
+		},
+		{
+			name:    "leading digit",
+			input:   "2fetcher",
+			wantErr: true,
+			errMsg:  `agent name "2fetcher" becomes "2fetcher", which starts with a digit; try "agent_2fetcher"`,
+		},
+		{
+			name:    "python keyword",
+			input:   "class",
+			wantErr: true,
+			errMsg:  `agent name "class" becomes the Python keyword "class"; try "class_agent"`,
+		},
+		{
+			name:    "python keyword after conversion",
+			input:   "Lambda",
+			wantErr: true,
+			errMsg:  `agent name "Lambda" becomes the Python keyword "lambda"; try "lambda_agent"`,
+		},
+		{
+			name:    "python builtin",
+			input:   "list",
+			wantErr: true,
+			errMsg:  `agent name "list" would shadow the Python builtin "list"; try "list_agent"`,
+		},
+		{
+			name:    "google package",
+			input:   "Google",
+			wantErr: true,
+			errMsg:  `agent name "Google" becomes "google", which would shadow the google package that ADK lives in; try "google_agent"`,
+		},
+		{
+			name:    "root_agent",
+			input:   "root_agent",
+			wantErr: true,
+			errMsg:  `agent name "root_agent" is reserved for the root agent ADK loads; try "root_agent_agent"`,
+		},
+		{
+			name:    "prompts folder",
+			input:   "Prompts",
+			wantErr: true,
+			errMsg:  `agent name "Prompts" becomes "prompts", which is reserved for the folder of instruction files; try "prompts_agent"`,
+		},
+		{
+			name:    "tests folder",
+			input:   "Tests",
+			wantErr: true,
+			errMsg:  `agent name "Tests" becomes "tests", which is reserved for the generated tests folder; try "tests_agent"`,
+		},
+		{
+			name:    "main.py",
+			input:   "Main",
+			wantErr: true,
+			errMsg:  `agent name "Main" becomes "main", which is reserved for main.py; try "main_agent"`,
+		},
+		{
+			name:    "eval folder",
+			input:   "eval",
+			wantErr: true,
+			errMsg:  `agent name "eval" is reserved for the evaluation folder; try "eval_agent"`,
+		},
+		{
+			name:    "imported module",
+			input:   "Asyncio",
+			wantErr: true,
+			errMsg:  `agent name "Asyncio" becomes "asyncio", which would shadow the asyncio module the generated code imports; try "asyncio_agent"`,
+		},
+		{
+			name:    "dependency module",
+			input:   "Pydantic",
+			wantErr: true,
+			errMsg:  `agent name "Pydantic" becomes "pydantic", which would shadow the pydantic module the generated code imports; try "pydantic_agent"`,
+		},
+		{
+			name:    "soft keyword",
+			input:   "Match",
+			wantErr: false,
+		},
+		{
+			name:    "type builtin",
+			input:   "Type",
+			wantErr: true,
+			errMsg:  `agent name "Type" would shadow the Python builtin "type"; try "type_agent"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckIdentifier(tt.input, tt.taken)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckIdentifier() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr && err.Error() != tt.errMsg {
+				t.Errorf("CheckIdentifier() error message = %v, want %v", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestValidateIdentifiers(t *testing.T) {
+	orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.0-flash")
+	orch.AddSubAgent(model.NewAgent("DataFetcher", model.AgentTypeLLM, "Fetch", "raw", "gemini-2.0-flash"))
+	orch.AddSubAgent(model.NewAgent("data_fetcher", model.AgentTypeLLM, "Fetch again", "raw2", "gemini-2.0-flash"))
+
+	project := model.NewProject("test-project", orch)
+
+	if err := ValidateIdentifiers(project); err == nil {
+		t.Error("ValidateIdentifiers() expected error for colliding agent names")
+	}
+
+	gen := NewGenerator()
+	if _, err := gen.RenderProject(project); err == nil {
+		t.Error("RenderProject() expected error for colliding agent names")
+	}
+}
+
+func TestGenerator_GenerateEvalSet(t *testing.T) {
+	orch := model.NewOrchestrator("ResearchCoordinator", model.PatternSequential, "Coordinates research tasks", "gemini-2.0-flash")
+	researcher := model.NewAgent("Researcher", model.AgentTypeLLM, "Research the topic", "research_data", "gemini-2.0-flash")
+	writer := model.NewAgent("Writer", model.AgentTypeLLM, "Write based on research", "draft", "gemini-2.0-flash")
+	writer.Examples = []model.Example{
+		{Prompt: "Write about \"tides\"", Expected: "An article about tides"},
+		{Prompt: "Write about volcanoes", Expected: "An article about volcanoes"},
+	}
+	orch.AddSubAgent(researcher)
+	orch.AddSubAgent(writer)
+
+	project := model.NewProject("test-project", orch)
+
+	gen := NewGenerator()
+	content, err := gen.GenerateEvalSet(project)
+
+	if err != nil {
+		t.Fatalf("GenerateEvalSet() error = %v", err)
+	}
+
+	t.Logf("Generated eval set:\n%s", content)
+
+	var evalSet struct {
+		EvalSetID string `json:"eval_set_id"`
+		EvalCases []struct {
+			EvalID       string `json:"eval_id"`
+			Conversation []struct {
+				UserContent struct {
+					Parts []struct {
+						Text string `json:"text"`
+					} `json:"parts"`
+				} `json:"user_content"`
+				FinalResponse struct {
+					Parts []struct {
+						Text string `json:"text"`
+					} `json:"parts"`
+				} `json:"final_response"`
+			} `json:"conversation"`
+			SessionInput struct {
+				AppName string `json:"app_name"`
+			} `json:"session_input"`
+		} `json:"eval_cases"`
+	}
+	if err := json.Unmarshal([]byte(content), &evalSet); err != nil {
+		t.Fatalf("GenerateEvalSet() returned invalid JSON: %v", err)
+	}
+
+	if evalSet.EvalSetID != "research_coordinator" {
+		t.Errorf("eval_set_id = %v, want research_coordinator", evalSet.EvalSetID)
+	}
+
+	wantCases := []struct {
+		id     string
+		prompt string
+	}{
+		{"writer_1", "Write about \"tides\""},
+		{"writer_2", "Write about volcanoes"},
+	}
+
+	if len(evalSet.EvalCases) != len(wantCases) {
+		t.Fatalf("eval_cases length = %d, want %d", len(evalSet.EvalCases), len(wantCases))
+	}
+
+	for i, want := range wantCases {
+		got := evalSet.EvalCases[i]
+		if got.EvalID != want.id {
+			t.Errorf("eval_cases[%d].eval_id = %v, want %v", i, got.EvalID, want.id)
+		}
+		if prompt := got.Conversation[0].UserContent.Parts[0].Text; prompt != want.prompt {
+			t.Errorf("eval_cases[%d] prompt = %v, want %v", i, prompt, want.prompt)
+		}
+		if got.SessionInput.AppName != "research_coordinator" {
+			t.Errorf("eval_cases[%d].session_input.app_name = %v, want research_coordinator", i, got.SessionInput.AppName)
+		}
+	}
+}
+
+func TestGenerator_GenerateEvalSet_NoExamples(t *testing.T) {
+	orch := model.NewOrchestrator("ResearchCoordinator", model.PatternSequential, "Coordinates research tasks", "gemini-2.0-flash")
+	orch.AddSubAgent(model.NewAgent("Researcher", model.AgentTypeLLM, "Research the topic", "research_data", "gemini-2.0-flash"))
+
+	content, err := NewGenerator().GenerateEvalSet(model.NewProject("test-project", orch))
+	if err != nil {
+		t.Fatalf("GenerateEvalSet() error = %v", err)
+	}
+
+	var evalSet struct {
+		EvalCases []interface{} `json:"eval_cases"`
+	}
+	if err := json.Unmarshal([]byte(content), &evalSet); err != nil {
+		t.Fatalf("GenerateEvalSet() returned invalid JSON: %v\n%s", err, content)
+	}
+	if len(evalSet.EvalCases) != 0 {
+		t.Errorf("eval_cases = %v, want none without examples", evalSet.EvalCases)
+	}
+}
+
+func TestGenerator_GenerateEvalTestPy(t *testing.T) {
+	orch := model.NewOrchestrator("ResearchCoordinator", model.PatternSequential, "Coordinates research tasks", "gemini-2.0-flash")
+	orch.AddSubAgent(model.NewAgent("Researcher", model.AgentTypeLLM, "Research the topic", "research_data", "gemini-2.0-flash"))
+
+	project := model.NewProject("test-project", orch)
+
+	gen := NewGenerator()
+	content, err := gen.GenerateEvalTestPy(project)
+
+	if err != nil {
+		t.Fatalf("GenerateEvalTestPy() error = %v", err)
+	}
+
+	expectedStrings := []string{
+		"from google.adk.evaluation.agent_evaluator import AgentEvaluator",
+		"await AgentEvaluator.evaluate(",
+		`agent_module="research_coordinator"`,
+		`"research_coordinator.test.json"`,
+	}
+
+	for _, expected := range expectedStrings {
+		if !strings.Contains(content, expected) {
+			t.Errorf("GenerateEvalTestPy() missing expected string: %s", expected)
+		}
+	}
+	if !strings.Contains(content, "@pytest.mark.skip(") {
+		t.Errorf("GenerateEvalTestPy() should skip an evaluation set without cases:\n%s", content)
+	}
+
+	orch.SubAgents[0].Examples = []model.Example{{Prompt: "Solar power", Expected: "A summary"}}
+	content, err = gen.GenerateEvalTestPy(project)
+	if err != nil {
+		t.Fatalf("GenerateEvalTestPy() error = %v", err)
+	}
+	if strings.Contains(content, "@pytest.mark.skip(") {
+		t.Errorf("GenerateEvalTestPy() should run an evaluation set with cases:\n%s", content)
+	}
+}
+
+func TestGenerator_RenderProject_WithoutEvalOrTests(t *testing.T) {
+	orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.0-flash")
+	orch.AddSubAgent(model.NewAgent("Agent1", model.AgentTypeLLM, "Task", "result", "gemini-2.0-flash"))
+
+	project := model.NewProject("test-project", orch)
+	project.AddEval = false
+	project.AddTests = false
+
+	gen := NewGenerator()
+	files, err := gen.RenderProject(project)
+
+	if err != nil {
+		t.Fatalf("RenderProject() error = %v", err)
+	}
+
+	for _, file := range files {
+		path := filepath.ToSlash(file.Path)
+		if strings.HasPrefix(path, "eval/") || strings.HasPrefix(path, "tests/") || path == "pytest.ini" {
+			t.Errorf("RenderProject() should not generate %s when AddEval and AddTests are false", file.Path)
+		}
+	}
+}
+
+func TestGenerator_RenderProject_ADKVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		addEval     bool
+		wantErr     bool
+		requirement string
+		session     string
+	}{
+		{
+			name:        "1.0 awaits session service calls",
+			version:     "1.0",
+			addEval:     true,
+			requirement: "google-adk>=1.0.0,<2.0.0",
+			session:     "session = await runner.session_service.create_session(",
+		},
+		{
+			name:        "0.5 calls session service synchronously",
+			version:     "0.5",
+			addEval:     false,
+			requirement: "google-adk>=0.5.0,<1.0.0",
+			session:     "session = runner.session_service.create_session(",
+		},
+		{
+			name:    "0.5 refuses evaluation set",
+			version: "0.5",
+			addEval: true,
+			wantErr: true,
+		},
+		{
+			name:    "unknown version",
+			version: "3.0",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.0-flash")
+			orch.AddSubAgent(model.NewAgent("Agent1", model.AgentTypeLLM, "Task", "result", "gemini-2.0-flash"))
+
+			project := model.NewProject("test-project", orch)
+			project.ADKVersion = tt.version
+			project.AddEval = tt.addEval
+
+			gen := NewGenerator()
+			files, err := gen.RenderProject(project)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RenderProject() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			contents := make(map[string]string)
+			for _, file := range files {
+				contents[filepath.ToSlash(file.Path)] = file.Content
+			}
+
+			if !strings.Contains(contents["requirements.txt"], tt.requirement) {
+				t.Errorf("requirements.txt missing %s:\n%s", tt.requirement, contents["requirements.txt"])
+			}
+			if !strings.Contains(contents["tests/conftest.py"], tt.session) {
+				t.Errorf("tests/conftest.py missing %s", tt.session)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateEnvExample(t *testing.T) {
+	tests := []struct {
+		name       string
+		backend    model.Backend
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:       "ai studio",
+			backend:    model.BackendAIStudio,
+			expected:   []string{"GOOGLE_GENAI_USE_VERTEXAI=FALSE", "GOOGLE_API_KEY=your-api-key"},
+			unexpected: []string{"GOOGLE_CLOUD_PROJECT"},
+		},
+		{
+			name:       "vertex ai",
+			backend:    model.BackendVertexAI,
+			expected:   []string{"GOOGLE_GENAI_USE_VERTEXAI=TRUE", "GOOGLE_CLOUD_PROJECT=", "GOOGLE_CLOUD_LOCATION=", "gcloud auth application-default login"},
+			unexpected: []string{"GOOGLE_API_KEY"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.0-flash")
+			orch.AddSubAgent(model.NewAgent("Agent1", model.AgentTypeLLM, "Task", "result", "gemini-2.0-flash"))
+
+			project := model.NewProject("test-project", orch)
+			project.Backend = tt.backend
+
+			gen := NewGenerator()
+			content, err := gen.GenerateEnvExample(project)
+			if err != nil {
+				t.Fatalf("GenerateEnvExample() error = %v", err)
+			}
+
+			for _, expected := range tt.expected {
+				if !strings.Contains(content, expected) {
+					t.Errorf("GenerateEnvExample() missing expected string: %s", expected)
+				}
+			}
+			for _, unexpected := range tt.unexpected {
+				if strings.Contains(content, unexpected) {
+					t.Errorf("GenerateEnvExample() should not contain: %s", unexpected)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateGitignore(t *testing.T) {
+	gen := NewGenerator()
+	project := model.NewProject("test-project", model.NewOrchestrator("Coordinator", model.PatternSequential, "", ""))
+	content, err := gen.GenerateGitignore(project)
+	if err != nil {
+		t.Fatalf("GenerateGitignore() error = %v", err)
+	}
+
+	if !strings.Contains(content, "\n.env\n") {
+		t.Errorf("GenerateGitignore() should ignore .env:\n%s", content)
+	}
+	if strings.Contains(content, ".env.example") {
+		t.Error("GenerateGitignore() should not ignore .env.example")
+	}
+}
+
+func TestGenerator_GenerateAgentTestPy(t *testing.T) {
+	orch := model.NewOrchestrator("ResearchCoordinator", model.PatternSequential, "Coordinates research tasks", "gemini-2.0-flash")
+	researcher := model.NewAgent("Researcher", model.AgentTypeLLM, "Research the topic", "research_data", "gemini-2.0-flash")
+	writer := model.NewAgent("Writer", model.AgentTypeLLM, "Write based on {research_data} in {user:tone}, styled like {artifact.style} and {artifact.extra?}", "draft", "gemini-2.0-flash")
+	orch.AddSubAgent(researcher)
+	orch.AddSubAgent(writer)
+
+	gen := NewGenerator()
+	content, err := gen.GenerateAgentTestPy(orch, writer)
+
+	if err != nil {
+		t.Fatalf("GenerateAgentTestPy() error = %v", err)
+	}
+
+	t.Logf("Generated agent test:\n%s", content)
+
+	expectedStrings := []string{
+		"from writer.agent import agent",
+		`assert agent.name == "writer"`,
+		`assert agent.model == "gemini-2.0-flash"`,
+		`assert agent.output_key == "draft"`,
+		"llm = fake_llm(agent)",
+		`"research_data": "example research_data",`,
+		`"user:tone": "example user:tone",`,
+		`"style": "example style",`,
+		`events, state = await run_agent(agent, "Hello", state=state, artifacts=artifacts)`,
+		`assert state["draft"] == llm.reply`,
+	}
+
+	for _, expected := range expectedStrings {
+		if !strings.Contains(content, expected) {
+			t.Errorf("GenerateAgentTestPy() missing expected string: %s", expected)
+		}
+	}
+}
+
+func TestGenerator_GenerateOrchestratorTestPy(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    model.OrchestrationPattern
+		expected   []string
+		unexpected []string
+	}{
+		{
+			name:    "sequential checks every output key",
+			pattern: model.PatternSequential,
+			expected: []string{
+				"from coordinator.agent import root_agent",
+				`assert root_agent.name == "coordinator"`,
+				`"task1",`,
+				`assert state["result1"] == llm.reply`,
+				`assert state["result2"] == llm.reply`,
+			},
+			unexpected: []string{"root_agent.model", "max_iterations", "state=state", "artifacts"},
+		},
+		{
+			name:    "loop limits iterations",
+			pattern: model.PatternLoop,
+			expected: []string{
+				`monkeypatch.setattr(root_agent, "max_iterations", 1)`,
+			},
+		},
+		{
+			name:    "llm-coordinated checks model and final response",
+			pattern: model.PatternLLMCoordinated,
+			expected: []string{
+				`assert root_agent.model == "gemini-2.0-flash"`,
+				"event.is_final_response()",
+			},
+			unexpected: []string{`state["result1"]`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orch := model.NewOrchestrator("Coordinator", tt.pattern, "Test", "gemini-2.0-flash")
+			orch.AddSubAgent(model.NewAgent("Task1", model.AgentTypeLLM, "Do task 1", "result1", "gemini-2.0-flash"))
+			orch.AddSubAgent(model.NewAgent("Task2", model.AgentTypeLLM, "Do task 2", "result2", "gemini-2.0-flash"))
+
+			gen := NewGenerator()
+			content, err := gen.GenerateOrchestratorTestPy(orch)
+
+			if err != nil {
+				t.Fatalf("GenerateOrchestratorTestPy() error = %v", err)
+			}
+
+			for _, expected := range tt.expected {
+				if !strings.Contains(content, expected) {
+					t.Errorf("GenerateOrchestratorTestPy() missing expected string: %s", expected)
+				}
+			}
+			for _, unexpected := range tt.unexpected {
+				if strings.Contains(content, unexpected) {
+					t.Errorf("GenerateOrchestratorTestPy() should not contain: %s", unexpected)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateOrchestratorTestPy_Seed(t *testing.T) {
+	orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.0-flash")
+	orch.AddSubAgent(model.NewAgent("Reader", model.AgentTypeLLM, "Summarize {artifact.brief}", "summary", "gemini-2.0-flash"))
+	orch.AddSubAgent(model.NewAgent("Writer", model.AgentTypeLLM, "Write from {summary} and {notes}", "draft", "gemini-2.0-flash"))
+
+	content, err := NewGenerator().GenerateOrchestratorTestPy(orch)
+	if err != nil {
+		t.Fatalf("GenerateOrchestratorTestPy() error = %v", err)
+	}
+
+	expectedStrings := []string{
+		`"notes": "example notes",`,
+		`"brief": "example brief",`,
+		`events, state = await run_agent(root_agent, "Hello", state=state, artifacts=artifacts)`,
+	}
+	for _, expected := range expectedStrings {
+		if !strings.Contains(content, expected) {
+			t.Errorf("GenerateOrchestratorTestPy() missing expected string: %s\n%s", expected, content)
+		}
+	}
+	// The reader writes summary during the run.
+	if strings.Contains(content, `"summary": "example summary"`) {
+		t.Errorf("GenerateOrchestratorTestPy() should not seed a key an earlier agent writes:\n%s", content)
+	}
+}
+
+func TestGenerator_RenderProject_SchemasAndTools(t *testing.T) {
+	orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.5-flash")
+	researcher := model.NewAgent("Researcher", model.AgentTypeLLM, "Research the topic", "research", "gemini-2.5-flash")
+	researcher.InputSchema = &model.Schema{Fields: []model.Field{
+		{Name: "topic", Type: model.FieldTypeString, Description: `What to "research"`},
+	}}
+	researcher.OutputSchema = &model.Schema{JSONSchema: map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"summary", "sources"},
+		"properties": map[string]interface{}{
+			"summary": map[string]interface{}{"type": "string"},
+			"sources": map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"type":       "object",
+					"required":   []interface{}{"url"},
+					"properties": map[string]interface{}{"url": map[string]interface{}{"type": "string"}},
+				},
+			},
+			"confidence": map[string]interface{}{"type": "string", "enum": []interface{}{"low", "high"}},
+		},
+	}}
+	writer := model.NewAgent("Writer", model.AgentTypeLLM, "Write", "draft", "gemini-2.5-flash")
+	writer.Tools = []string{"lookup_style_guide", "count_words"}
+	orch.AddSubAgent(researcher)
+	orch.AddSubAgent(writer)
+
+	gen := NewGenerator()
+	files, err := gen.RenderProject(model.NewProject("typed", orch))
+	if err != nil {
+		t.Fatalf("RenderProject() error = %v", err)
+	}
+
+	contents := make(map[string]string)
+	for _, file := range files {
+		contents[filepath.ToSlash(file.Path)] = file.Content
+	}
+
+	expected := map[string][]string{
+		"researcher/schemas.py": {
+			"from typing import Literal\n\nfrom pydantic import BaseModel, Field\n",
+			"class ResearcherInput(BaseModel):\n    topic: str = Field(description=\"What to \\\"research\\\"\")\n",
+			"class ResearcherOutputSourcesItem(BaseModel):\n    url: str\n",
+			"class ResearcherOutput(BaseModel):\n    summary: str\n    sources: list[ResearcherOutputSourcesItem]\n    confidence: Literal[\"low\", \"high\"] | None = None\n",
+		},
+		"researcher/agent.py": {
+			"from .schemas import ResearcherInput, ResearcherOutput\n",
+			"input_schema=ResearcherInput,",
+			"output_schema=ResearcherOutput,",
+		},
+		"writer/tools.py": {
+			"def lookup_style_guide() -> dict:",
+			"def count_words() -> dict:",
+		},
+		"writer/agent.py": {
+			"from .tools import lookup_style_guide, count_words\n",
+			"tools=[lookup_style_guide, count_words],",
+		},
+		"tests/test_researcher.py": {
+			"from researcher.schemas import ResearcherInput, ResearcherOutput",
+			"assert agent.output_schema is ResearcherOutput",
+			`llm.reply = "{\"sources\":[{\"url\":\"example\"}],\"summary\":\"example\"}"`,
+			`ResearcherOutput.model_validate(state["research"])`,
+		},
+		"tests/test_coordinator.py": {
+			"researcher_llm = fake_llm(root_agent.sub_agents[0])\n",
+			`assert state["research"] == json.loads(researcher_llm.reply)`,
+			`assert state["draft"] == llm.reply`,
+		},
+		"tests/test_writer.py": {
+			`assert [tool.__name__ for tool in agent.tools] == ["lookup_style_guide", "count_words"]`,
+		},
+	}
+
+	for path, expectedStrings := range expected {
+		content, ok := contents[path]
+		if !ok {
+			t.Errorf("RenderProject() did not generate %s", path)
+			continue
+		}
+		for _, s := range expectedStrings {
+			if !strings.Contains(content, s) {
+				t.Errorf("%s missing expected string: %q\n%s", path, s, content)
+			}
+		}
+	}
+
+	if _, ok := contents["writer/schemas.py"]; ok {
+		t.Error("RenderProject() should not generate schemas.py for an agent without schemas")
+	}
+	if _, ok := contents["researcher/tools.py"]; ok {
+		t.Error("RenderProject() should not generate tools.py for an agent without tools")
+	}
+}
+
+func TestValidateIdentifiers_SchemasAndTools(t *testing.T) {
+	object := func(properties map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"type": "object", "properties": properties}
+	}
+	str := map[string]interface{}{"type": "string"}
+
+	tests := []struct {
+		name   string
+		setup  func(agent *model.Agent)
+		errMsg string
+	}{
+		{
+			name: "field name is a keyword",
+			setup: func(agent *model.Agent) {
+				agent.OutputSchema = &model.Schema{Fields: []model.Field{{Name: "class", Type: model.FieldTypeString}}}
+			},
+			errMsg: `agent "Researcher" output schema: field name "class" is reserved in Python or Pydantic`,
+		},
+		{
+			name: "field name is not an identifier",
+			setup: func(agent *model.Agent) {
+				agent.OutputSchema = &model.Schema{JSONSchema: object(map[string]interface{}{"first-name": str})}
+			},
+			errMsg: `agent "Researcher" output schema: schema: field name "first-name" is not a valid Python identifier`,
+		},
+		{
+			name: "reference",
+			setup: func(agent *model.Agent) {
+				agent.OutputSchema = &model.Schema{JSONSchema: object(map[string]interface{}{
+					"author": map[string]interface{}{"$ref": "#/$defs/Author"},
+				})}
+			},
+			errMsg: `agent "Researcher" output schema: property author uses $ref, which is not supported; inline the schema instead`,
+		},
+		{
+			name: "array without items",
+			setup: func(agent *model.Agent) {
+				agent.OutputSchema = &model.Schema{JSONSchema: object(map[string]interface{}{
+					"tags": map[string]interface{}{"type": "array"},
+				})}
+			},
+			errMsg: `agent "Researcher" output schema: property tags is an array without items`,
+		},
+		{
+			name: "top level is not an object",
+			setup: func(agent *model.Agent) {
+				agent.OutputSchema = &model.Schema{JSONSchema: map[string]interface{}{"type": "string"}}
+			},
+			errMsg: `agent "Researcher" output schema: schema must have type object`,
+		},
+		{
+			name: "input and output classes clash",
+			setup: func(agent *model.Agent) {
+				agent.InputSchema = &model.Schema{Name: "Report", Fields: []model.Field{{Name: "topic", Type: model.FieldTypeString}}}
+				agent.OutputSchema = &model.Schema{Name: "Report", Fields: []model.Field{{Name: "summary", Type: model.FieldTypeString}}}
+			},
+			errMsg: `agent "Researcher" output schema: class Report is generated twice; give one of the schemas another name or title`,
+		},
+		{
+			name: "tool name is a builtin",
+			setup: func(agent *model.Agent) {
+				agent.Tools = []string{"print"}
+			},
+			errMsg: `agent "Researcher" tool "print" is a Python keyword or builtin`,
+		},
+		{
+			name: "tool name clashes with a callback",
+			setup: func(agent *model.Agent) {
+				agent.Tools = []string{"before_model"}
+				agent.Callbacks = []model.Callback{model.CallbackRequestLogging}
+			},
+			errMsg: `agent "Researcher" tool "before_model" clashes with a callback function`,
+		},
+		{
+			name: "tool name clashes with the prompt loader",
+			setup: func(agent *model.Agent) {
+				agent.Tools = []string{"load_prompt"}
+				agent.PromptFile = true
+			},
+			errMsg: `agent "Researcher" tool "load_prompt" clashes with the prompt file loader`,
+		},
+		{
+			name: "tool name clashes with load_memory",
+			setup: func(agent *model.Agent) {
+				agent.Tools = []string{"load_memory"}
+				agent.Memory = true
+			},
+			errMsg: `agent "Researcher" tool "load_memory" clashes with ADK's load_memory tool, which memory adds`,
+		},
+		{
+			name: "tool name clashes with the agent variable",
+			setup: func(agent *model.Agent) {
+				agent.Tools = []string{"agent"}
+			},
+			errMsg: `agent "Researcher" tool "agent" is already used in agent.py`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.5-flash")
+			agent := model.NewAgent("Researcher", model.AgentTypeLLM, "Research", "research", "gemini-2.5-flash")
+			tt.setup(agent)
+			orch.AddSubAgent(agent)
+
+			err := ValidateIdentifiers(model.NewProject("typed", orch))
+			if err == nil || err.Error() != tt.errMsg {
+				t.Errorf("ValidateIdentifiers() error = %v, want %v", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestGenerator_RenderProject_Callbacks(t *testing.T) {
+	orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.5-flash")
+	writer := model.NewAgent("Writer", model.AgentTypeLLM, "Write", "draft", "gemini-2.5-flash")
+	writer.Tools = []string{"count_words"}
+	writer.Callbacks = []model.Callback{
+		model.CallbackAfterTool,
+		model.CallbackInputBlocklist,
+		model.CallbackRequestLogging,
+		model.CallbackOutputLengthLimit,
+	}
+	reviewer := model.NewAgent("Reviewer", model.AgentTypeLLM, "Review", "review", "gemini-2.5-flash")
+	reviewer.Callbacks = []model.Callback{model.CallbackBeforeAgent}
+	plain := model.NewAgent("Editor", model.AgentTypeLLM, "Edit", "edit", "gemini-2.5-flash")
+	orch.AddSubAgent(writer)
+	orch.AddSubAgent(reviewer)
+	orch.AddSubAgent(plain)
+
+	gen := NewGenerator()
+	files, err := gen.RenderProject(model.NewProject("hooked", orch))
+	if err != nil {
+		t.Fatalf("RenderProject() error = %v", err)
+	}
+
+	contents := make(map[string]string)
+	for _, file := range files {
+		contents[filepath.ToSlash(file.Path)] = file.Content
+	}
+
+	expected := map[string][]string{
+		"writer/callbacks.py": {
+			"import logging\nfrom typing import Any, Optional\n",
+			"from google.adk.models import LlmRequest, LlmResponse\n",
+			"BLOCKED_TERMS: list[str] = []",
+			"MAX_OUTPUT_CHARS = 4000",
+			"def before_model(",
+			"logger.info(\n        \"%s calls %s with %d contents\"",
+			"for term in BLOCKED_TERMS:",
+			"def after_model(",
+			"part.text = part.text[:remaining]",
+			"def after_tool(",
+			"# TODO: inspect or rewrite tool_response.",
+		},
+		"writer/agent.py": {
+			"from .callbacks import before_model, after_model, after_tool\nfrom .tools import count_words\n",
+			"    before_model_callback=before_model,\n    after_model_callback=after_model,\n    after_tool_callback=after_tool,\n)",
+		},
+		"reviewer/callbacks.py": {
+			"from typing import Optional\n\nfrom google.adk.agents.callback_context import CallbackContext\nfrom google.genai import types\n\n\ndef before_agent(",
+		},
+		"reviewer/agent.py": {
+			"from .callbacks import before_agent\n",
+			"before_agent_callback=before_agent,",
+		},
+		"tests/test_writer.py": {
+			"from writer import callbacks\n",
+			"assert agent.before_model_callback is callbacks.before_model",
+			"assert agent.after_tool_callback is callbacks.after_tool",
+		},
+	}
+
+	for path, expectedStrings := range expected {
+		content, ok := contents[path]
+		if !ok {
+			t.Errorf("RenderProject() did not generate %s", path)
+			continue
+		}
+		for _, s := range expectedStrings {
+			if !strings.Contains(content, s) {
+				t.Errorf("%s missing expected string: %q\n%s", path, s, content)
+			}
+		}
+	}
+
+	unexpected := map[string][]string{
+		"writer/callbacks.py":   {"import json", "# TODO: inspect or modify llm_request", "def before_tool("},
+		"reviewer/callbacks.py": {"import logging", "LlmRequest", "BaseTool"},
+		"editor/agent.py":       {"callbacks", "_callback="},
+	}
+	for path, strs := range unexpected {
+		for _, s := range strs {
+			if strings.Contains(contents[path], s) {
+				t.Errorf("%s should not contain %q", path, s)
+			}
+		}
+	}
+	if _, ok := contents["editor/callbacks.py"]; ok {
+		t.Error("RenderProject() should not generate callbacks.py for an agent without callbacks")
+	}
+}
+
+func TestGenerator_RenderProject_GenerationConfig(t *testing.T) {
+	temperature, creative := 0.2, 1.0
+	maxTokens, budget := 2048, 0
+
+	orch := model.NewOrchestrator("Coordinator", model.PatternLLMCoordinated, "Routes requests", "gemini-2.5-flash")
+	orch.GenerationConfig = &model.GenerationConfig{
+		Temperature:    &temperature,
+		SafetySettings: []model.SafetySetting{{Category: model.HarmCategoryHarassment, Threshold: model.HarmThresholdOnlyHigh}},
+	}
+	writer := model.NewAgent("Writer", model.AgentTypeLLM, "Write", "draft", "gemini-2.5-flash")
+	writer.GenerationConfig = &model.GenerationConfig{
+		Temperature:     &creative,
+		MaxOutputTokens: &maxTokens,
+		StopSequences:   []string{"END"},
+		ThinkingBudget:  &budget,
+	}
+	orch.AddSubAgent(model.NewAgent("Researcher", model.AgentTypeLLM, "Research", "research", "gemini-2.5-flash"))
+	orch.AddSubAgent(writer)
+
+	gen := NewGenerator()
+	files, err := gen.RenderProject(model.NewProject("tuned", orch))
+	if err != nil {
+		t.Fatalf("RenderProject() error = %v", err)
+	}
+
+	contents := make(map[string]string)
+	for _, file := range files {
+		contents[filepath.ToSlash(file.Path)] = file.Content
+	}
+
+	safety := "        safety_settings=[\n            types.SafetySetting(\n" +
+		"                category=types.HarmCategory.HARM_CATEGORY_HARASSMENT,\n" +
+		"                threshold=types.HarmBlockThreshold.BLOCK_ONLY_HIGH,\n"
+	expected := map[string][]string{
+		"coordinator/agent.py": {
+			"from google.genai import types\n",
+			"    generate_content_config=types.GenerateContentConfig(\n        temperature=0.2,\n",
+			safety,
+		},
+		"researcher/agent.py": {
+			"from google.adk.agents import LlmAgent\nfrom google.genai import types\n",
+			"        temperature=0.2,\n",
+			safety,
+		},
+		"writer/agent.py": {
+			"from google.adk.planners import BuiltInPlanner\nfrom google.genai import types\n",
+			"        temperature=1,\n        max_output_tokens=2048,\n        stop_sequences=[\"END\"],\n",
+			safety,
+			"    planner=BuiltInPlanner(\n        thinking_config=types.ThinkingConfig(thinking_budget=0),\n    ),\n",
+		},
+	}
+
+	for path, expectedStrings := range expected {
+		for _, s := range expectedStrings {
+			if !strings.Contains(contents[path], s) {
+				t.Errorf("%s missing expected string: %q\n%s", path, s, contents[path])
+			}
+		}
+	}
+
+	if strings.Contains(contents["researcher/agent.py"], "planner") {
+		t.Error("researcher/agent.py should not get a planner without a thinking budget")
+	}
+}
+
+func TestGenerator_GenerateOrchestratorPy_WorkflowIgnoresGenerationConfig(t *testing.T) {
+	temperature := 0.2
+	orch := model.NewOrchestrator("Pipeline", model.PatternSequential, "", "")
+	orch.GenerationConfig = &model.GenerationConfig{Temperature: &temperature}
+	orch.AddSubAgent(model.NewAgent("Writer", model.AgentTypeLLM, "Write", "draft", "gemini-2.5-flash"))
+
+	content, err := NewGenerator().GenerateOrchestratorPy(orch)
+	if err != nil {
+		t.Fatalf("GenerateOrchestratorPy() error = %v", err)
+	}
+	if strings.Contains(content, "generate_content_config") || strings.Contains(content, "google.genai") {
+		t.Errorf("SequentialAgent should not get a generation config:\n%s", content)
+	}
+}
+
+func TestGenerator_RenderProject_Routing(t *testing.T) {
+	orch := model.NewOrchestrator("Help Desk", model.PatternLLMCoordinated, "Routes support requests", "gemini-2.5-flash")
+	billing := model.NewAgent("Billing", model.AgentTypeLLM, "Answer billing questions", "billing", "gemini-2.5-flash")
+	billing.Description = `Invoices, "refunds" and payments`
+	billing.DisallowTransferToPeers = true
+	tech := model.NewAgent("Tech", model.AgentTypeLLM, "Troubleshoot technical problems", "tech", "gemini-2.5-flash")
+	tech.DisallowTransferToParent = true
+	orch.AddSubAgent(billing)
+	orch.AddSubAgent(tech)
+
+	gen := NewGenerator()
+	files, err := gen.RenderProject(model.NewProject("help-desk", orch))
+	if err != nil {
+		t.Fatalf("RenderProject() error = %v", err)
+	}
+
+	contents := make(map[string]string)
+	for _, file := range files {
+		contents[filepath.ToSlash(file.Path)] = file.Content
+	}
+
+	expected := map[string][]string{
+		"help_desk/agent.py": {
+			"    instruction=(\n        \"You coordinate a team of sub-agents.",
+			`        "- billing: Invoices, \"refunds\" and payments\n"`,
+			`        "- tech: Troubleshoot technical problems\n"`,
+			"    ),\n    sub_agents=[billing, tech],",
+		},
+		"billing/agent.py": {
+			`description="Invoices, \"refunds\" and payments",`,
+			"disallow_transfer_to_peers=True,",
+		},
+		"tech/agent.py": {
+			"disallow_transfer_to_parent=True,",
+		},
+		"tests/test_help_desk.py": {
+			`assert "- billing:" in root_agent.instruction`,
+		},
+		"tests/test_billing.py": {
+			`assert agent.description == "Invoices, \"refunds\" and payments"`,
+			"assert agent.disallow_transfer_to_peers",
+		},
+	}
+
+	for path, expectedStrings := range expected {
+		for _, s := range expectedStrings {
+			if !strings.Contains(contents[path], s) {
+				t.Errorf("%s missing expected string: %q\n%s", path, s, contents[path])
+			}
+		}
+	}
+
+	if strings.Contains(contents["tech/agent.py"], "description=") {
+		t.Error("tech/agent.py should not get a description it does not have")
+	}
+}
+
+func TestGenerator_GenerateOrchestratorPy_WorkflowHasNoRoutingInstruction(t *testing.T) {
+	orch := model.NewOrchestrator("Pipeline", model.PatternSequential, "", "")
+	orch.AddSubAgent(model.NewAgent("Writer", model.AgentTypeLLM, "Write", "draft", "gemini-2.5-flash"))
+
+	content, err := NewGenerator().GenerateOrchestratorPy(orch)
+	if err != nil {
+		t.Fatalf("GenerateOrchestratorPy() error = %v", err)
+	}
+	if strings.Contains(content, "instruction=") {
+		t.Errorf("SequentialAgent should not get an instruction:\n%s", content)
+	}
+}
+
+func TestGenerator_RenderProject_Services(t *testing.T) {
+	tests := []struct {
+		name       string
+		backend    model.Backend
+		services   model.Services
+		expected   map[string][]string
+		unexpected map[string][]string
+	}{
+		{
+			name:     "in-memory defaults",
+			backend:  model.BackendAIStudio,
+			services: model.DefaultServices(),
+			expected: map[string][]string{
+				"main.py": {
+					"from google.adk.artifacts import InMemoryArtifactService\n",
+					"session_service = InMemorySessionService()",
+					"artifact_service = InMemoryArtifactService()",
+				},
+				"README.md": {"`main.py` keeps sessions in memory, so they end with the process. Artifacts are kept in memory."},
+			},
+			unexpected: map[string][]string{
+				"main.py":    {"import os", "memory_service", "add_session_to_memory"},
+				".gitignore": {"# Local sessions and artifacts"},
+			},
+		},
+		{
+			name:    "offline sqlite, memory and local artifacts",
+			backend: model.BackendAIStudio,
+			services: model.Services{
+				Session:  model.SessionDatabase,
+				Memory:   model.MemoryInMemory,
+				Artifact: model.ArtifactLocal,
+			},
+			expected: map[string][]string{
+				"main.py": {
+					"import os\n",
+					"from google.adk.memory import InMemoryMemoryService\n",
+					"from google.adk.sessions import DatabaseSessionService\n",
+					"from coordinator.agent import root_agent\nfrom local_artifacts import LocalArtifactService\n",
+					`db_url=os.environ.get("DATABASE_URL", "sqlite:///./sessions.db")`,
+					`os.environ.get("ARTIFACT_DIR", "artifacts")`,
+					"        memory_service=memory_service,\n",
+					"    await runner.memory_service.add_session_to_memory(session)",
+				},
+				"local_artifacts.py": {
+					"class LocalArtifactService(BaseArtifactService):",
+					"    async def save_artifact(",
+				},
+				"researcher/agent.py": {
+					"from google.adk.tools import load_memory\n",
+					"tools=[load_memory],",
+				},
+				"tests/test_researcher.py": {"assert agent.tools[-1] is load_memory"},
+				".env.example":             {"DATABASE_URL=sqlite:///./sessions.db", "ARTIFACT_DIR=artifacts"},
+				".gitignore":               {"# Local sessions and artifacts\nsessions.db\nartifacts/\n"},
+				"README.md":                {"├── local_artifacts.py"},
+			},
+		},
+		{
+			name:    "vertex ai",
+			backend: model.BackendVertexAI,
+			services: model.Services{
+				Session:  model.SessionVertexAI,
+				Memory:   model.MemoryVertexAIRAG,
+				Artifact: model.ArtifactGCS,
+				Bucket:   "agent-files",
+			},
+			expected: map[string][]string{
+				"main.py": {
+					"from google.adk.artifacts import GcsArtifactService\n",
+					"from google.adk.memory import VertexAiRagMemoryService\n",
+					`rag_corpus=os.environ["RAG_CORPUS"]`,
+					`bucket_name=os.environ.get("ARTIFACT_BUCKET", "agent-files")`,
+					`app_name=os.environ["AGENT_ENGINE_ID"],`,
+				},
+				".env.example": {"AGENT_ENGINE_ID=", "RAG_CORPUS=", "ARTIFACT_BUCKET=agent-files"},
+			},
+			unexpected: map[string][]string{
+				"main.py": {"app_name=APP_NAME"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orch := model.NewOrchestrator("Coordinator", model.PatternSequential, "Test", "gemini-2.5-flash")
+			researcher := model.NewAgent("Researcher", model.AgentTypeLLM, "Research", "research", "gemini-2.5-flash")
+			researcher.Memory = tt.services.Memory != model.MemoryNone
+			orch.AddSubAgent(researcher)
+			project := model.NewProject("stateful", orch)
+			project.Backend = tt.backend
+			project.Services = tt.services
+
+			files, err := NewGenerator().RenderProject(project)
+			if err != nil {
+				t.Fatalf("RenderProject() error = %v", err)
+			}
+
+			contents := make(map[string]string)
+			for _, file := range files {
+				contents[filepath.ToSlash(file.Path)] = file.Content
+			}
+
+			for path, expectedStrings := range tt.expected {
+				for _, s := range expectedStrings {
+					if !strings.Contains(contents[path], s) {
+						t.Errorf("%s missing expected string: %q\n%s", path, s, contents[path])
+					}
+				}
+			}
+			for path, strs := range tt.unexpected {
+				for _, s := range strs {
+					if strings.Contains(contents[path], s) {
+						t.Errorf("%s should not contain %q", path, s)
+					}
+				}
+			}
+			if _, ok := contents["local_artifacts.py"]; ok != (tt.services.Artifact == model.ArtifactLocal) {
+				t.Errorf("RenderProject() generated local_artifacts.py = %v, want %v", ok, !ok)
+			}
+		})
+	}
+}
+
+func TestGenerator_RenderProject_Instructions(t *testing.T) {
+	orch := model.NewOrchestrator("Help Desk", model.PatternLLMCoordinated, "Routes support requests", "gemini-2.5-flash")
+	orch.GlobalInstruction = "Reply in {user:language?}."
+	billing := model.NewAgent("Billing", model.AgentTypeLLM, "Answer billing questions.\nQuote the \"invoice\" number.\n", "billing", "gemini-2.5-flash")
+	billing.PromptFile = true
+	billing.StaticInstruction = "You are the billing desk of ACME."
+	tech := model.NewAgent("Tech", model.AgentTypeLLM, "Follow up on {billing}.\nBe \"brief\".", "", "gemini-2.5-flash")
+	orch.AddSubAgent(billing)
+	orch.AddSubAgent(tech)
+	project := model.NewProject("help-desk", orch)
+	project.ADKVersion = "1.15"
+
+	files, err := NewGenerator().RenderProject(project)
+	if err != nil {
+		t.Fatalf("RenderProject() error = %v", err)
+	}
+
+	contents := make(map[string]string)
+	for _, file := range files {
+		contents[filepath.ToSlash(file.Path)] = file.Content
+	}
+
+	expected := map[string][]string{
+		"prompts/billing.md": {"Answer billing questions.\nQuote the \"invoice\" number.\n"},
+		"billing/agent.py": {
+			"from pathlib import Path\n\nfrom google.adk.agents import LlmAgent\n",
+			`(Path(__file__).resolve().parent.parent / "prompts" / filename).read_text(encoding="utf-8").strip()`,
+			`instruction=load_prompt("billing.md"),`,
+			`static_instruction="You are the billing desk of ACME.",`,
+		},
+		"tech/agent.py": {`instruction="Follow up on {billing}.\nBe \"brief\".",`},
+		"help_desk/agent.py": {
+			`global_instruction="Reply in {user:language?}.",`,
+			`        "- tech: Follow up on billing.\n"`,
+		},
+		"tests/test_help_desk.py": {`assert root_agent.global_instruction == "Reply in {user:language?}."`},
+		"tests/test_billing.py": {
+			`assert agent.instruction, "prompts/billing.md is empty"`,
+			`assert agent.static_instruction == "You are the billing desk of ACME."`,
+		},
+		"README.md": {"├── prompts/"},
+	}
+
+	for path, expectedStrings := range expected {
+		for _, s := range expectedStrings {
+			if !strings.Contains(contents[path], s) {
+				t.Errorf("%s missing expected string: %q\n%s", path, s, contents[path])
+			}
+		}
+	}
+
+	if strings.Contains(contents["tech/agent.py"], "pathlib") {
+		t.Error("tech/agent.py should not import pathlib without a prompt file")
+	}
+	if _, ok := contents["prompts/tech.md"]; ok {
+		t.Error("RenderProject() generated prompts/tech.md for an inline instruction")
+	}
+}
+
+func TestGenerator_RenderSingleAgent_PromptFile(t *testing.T) {
+	agent := model.NewAgent("Writer", model.AgentTypeLLM, "Write an article", "draft", "gemini-2.5-flash")
+	agent.PromptFile = true
+
+	files, err := NewGenerator().RenderSingleAgent(agent)
+	if err != nil {
+		t.Fatalf("RenderSingleAgent() error = %v", err)
+	}
+
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, filepath.ToSlash(file.Path))
+	}
+	want := []string{"writer/agent.py", "prompts/writer.md"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("RenderSingleAgent() files = %v, want %v", paths, want)
+	}
+}
@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/naming"
+)
+
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true,
+	"assert": true, "async": true, "await": true, "break": true, "class": true,
+	"continue": true, "def": true, "del": true, "elif": true, "else": true,
+	"except": true, "finally": true, "for": true, "from": true, "global": true,
+	"if": true, "import": true, "in": true, "is": true, "lambda": true,
+	"nonlocal": true, "not": true, "or": true, "pass": true, "raise": true,
+	"return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+var pythonBuiltins = map[string]bool{
+	"abs": true, "all": true, "any": true, "ascii": true, "bin": true,
+	"bool": true, "breakpoint": true, "bytearray": true, "bytes": true,
+	"callable": true, "chr": true, "classmethod": true, "compile": true,
+	"complex": true, "delattr": true, "dict": true, "dir": true, "divmod": true,
+	"enumerate": true, "eval": true, "exec": true, "filter": true, "float": true,
+	"format": true, "frozenset": true, "getattr": true, "globals": true,
+	"hasattr": true, "hash": true, "help": true, "hex": true, "id": true,
+	"input": true, "int": true, "isinstance": true, "issubclass": true,
+	"iter": true, "len": true, "list": true, "locals": true, "map": true,
+	"max": true, "memoryview": true, "min": true, "next": true, "object": true,
+	"oct": true, "open": true, "ord": true, "pow": true, "print": true,
+	"property": true, "range": true, "repr": true, "reversed": true,
+	"round": true, "set": true, "setattr": true, "slice": true, "sorted": true,
+	"staticmethod": true, "str": true, "sum": true, "super": true,
+	"tuple": true, "type": true, "vars": true, "zip": true,
+}
+
+// reservedIdentifiers are names the generated project already uses: the
+// google namespace package and the variables every agent module defines.
+var reservedIdentifiers = map[string]string{
+	"google":     "would shadow the google package that ADK lives in",
+	"root_agent": "is reserved for the root agent ADK loads",
+	"agent":      "is reserved for the agent variable in each agent.py",
+
+	"local_artifacts": "is reserved for the local artifact service module",
+	"prompts":         "is reserved for the folder of instruction files",
+}
+
+// reservedModules are the other files and folders of the generated project,
+// and the modules its code imports, which an agent package of the same name
+// would overwrite or shadow.
+var reservedModules = map[string]string{
+	"tests": "is reserved for the generated tests folder",
+	"main":  "is reserved for main.py",
+	"eval":  "is reserved for the evaluation folder",
+
+	"argparse": "", "asyncio": "", "json": "", "logging": "", "os": "",
+	"pathlib": "", "shutil": "", "types": "", "typing": "", "urllib": "",
+	"uuid": "", "dotenv": "", "pydantic": "", "pytest": "",
+}
+
+func reservedModule(ident string) (string, bool) {
+	problem, ok := reservedModules[ident]
+	if ok && problem == "" {
+		problem = fmt.Sprintf("would shadow the %s module the generated code imports", ident)
+	}
+	return problem, ok
+}
+
+// CheckIdentifier reports whether an agent name converts to a usable Python
+// package and variable name that does not collide with any of taken.
+func CheckIdentifier(name string, taken []string) error {
+	if err := naming.Validate(name); err != nil {
+		return fmt.Errorf("invalid agent name: %w", err)
+	}
+	ident := naming.SnakeCase(name)
+
+	used := make(map[string]string, len(taken))
+	for _, t := range taken {
+		used[naming.SnakeCase(t)] = t
+	}
+
+	reserved := reservedIdentifiers[ident]
+	if module, ok := reservedModule(ident); ok {
+		reserved = module
+	}
+
+	var problem string
+	switch {
+	case ident[0] >= '0' && ident[0] <= '9':
+		problem = fmt.Sprintf("becomes %q, which starts with a digit", ident)
+	case pythonKeywords[ident]:
+		problem = fmt.Sprintf("becomes the Python keyword %q", ident)
+	case reserved != "":
+		problem = reserved
+		if ident != name {
+			problem = fmt.Sprintf("becomes %q, which %s", ident, reserved)
+		}
+	case pythonBuiltins[ident]:
+		problem = fmt.Sprintf("would shadow the Python builtin %q", ident)
+	case used[ident] != "":
+		if used[ident] == name {
+			problem = "is already used"
+		} else {
+			problem = fmt.Sprintf("and %q both become %q", used[ident], ident)
+		}
+	default:
+		return nil
+	}
+
+	return fmt.Errorf("agent name %q %s; try %q", name, problem, suggestIdentifier(ident, used))
+}
+
+// ValidateIdentifiers checks the orchestrator and every sub-agent of project
+// with CheckIdentifier, so that no two agents share a folder or variable, and
+// checks the class, field and function names their schemas and tools become.
+func ValidateIdentifiers(project *model.Project) error {
+	var taken []string
+
+	names := []string{project.Orchestrator.Name}
+	for _, agent := range project.Orchestrator.SubAgents {
+		names = append(names, agent.Name)
+	}
+
+	for _, name := range names {
+		if err := CheckIdentifier(name, taken); err != nil {
+			return err
+		}
+		taken = append(taken, name)
+	}
+
+	for _, agent := range project.Orchestrator.SubAgents {
+		if err := checkAgentCode(agent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func suggestIdentifier(ident string, used map[string]string) string {
+	base := ident
+	switch {
+	case base[0] >= '0' && base[0] <= '9':
+		base = "agent_" + base
+	case pythonKeywords[base], pythonBuiltins[base], reservedIdentifiers[base] != "":
+		base += "_agent"
+	default:
+		if _, ok := reservedModules[base]; ok {
+			base += "_agent"
+		}
+	}
+
+	candidate := base
+	for i := 2; used[candidate] != ""; i++ {
+		candidate = fmt.Sprintf("%s_%d", base, i)
+	}
+	return candidate
+}
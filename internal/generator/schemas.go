@@ -0,0 +1,434 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/naming"
+)
+
+var pythonIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// pydanticReserved are names a generated model field cannot take: the names
+// schemas.py imports, and attributes of pydantic.BaseModel that a field would
+// shadow.
+var pydanticReserved = map[string]bool{
+	"BaseModel": true, "Field": true, "Literal": true,
+	"construct": true, "copy": true, "dict": true, "from_orm": true, "json": true,
+	"parse_file": true, "parse_obj": true, "parse_raw": true, "schema": true,
+	"schema_json": true, "update_forward_refs": true, "validate": true,
+}
+
+// unsupportedKeywords are JSON Schema keywords whose meaning cannot be carried
+// over to a plain Pydantic model.
+var unsupportedKeywords = []string{"$ref", "$defs", "definitions", "allOf", "anyOf", "oneOf", "not"}
+
+var jsonSchemaTypes = map[string]string{
+	"string":  "str",
+	"integer": "int",
+	"number":  "float",
+	"boolean": "bool",
+}
+
+// pyModel is a Pydantic model class generated from a schema.
+type pyModel struct {
+	Name        string
+	Description string
+	Fields      []pyField
+}
+
+type pyField struct {
+	Name       string
+	Annotation string
+	Default    string
+}
+
+// schemaModule is the content of an agent's schemas.py.
+type schemaModule struct {
+	Agent       *model.Agent
+	Models      []pyModel
+	UsesField   bool
+	UsesLiteral bool
+}
+
+// inputModel returns the class name of the agent's input schema.
+func inputModel(agent *model.Agent) string {
+	return schemaClassName(agent, agent.InputSchema, "Input")
+}
+
+// outputModel returns the class name of the agent's output schema.
+func outputModel(agent *model.Agent) string {
+	return schemaClassName(agent, agent.OutputSchema, "Output")
+}
+
+func schemaClassName(agent *model.Agent, schema *model.Schema, suffix string) string {
+	if schema == nil {
+		return ""
+	}
+	if schema.Name != "" {
+		return schema.Name
+	}
+	return naming.PascalCase(agent.Name) + suffix
+}
+
+// agentSchemas converts the agent's input and output schemas to Pydantic
+// models, nested models first. It returns nil when the agent has neither.
+func agentSchemas(agent *model.Agent) (*schemaModule, error) {
+	if agent.InputSchema == nil && agent.OutputSchema == nil {
+		return nil, nil
+	}
+
+	c := &schemaConverter{module: &schemaModule{Agent: agent}, classes: make(map[string]bool)}
+	for _, s := range []struct {
+		schema *model.Schema
+		name   string
+		label  string
+	}{
+		{agent.InputSchema, inputModel(agent), "input schema"},
+		{agent.OutputSchema, outputModel(agent), "output schema"},
+	} {
+		if s.schema == nil {
+			continue
+		}
+		var err error
+		if s.schema.JSONSchema != nil {
+			_, _, err = c.jsonObject(s.name, s.schema.JSONSchema, "")
+		} else {
+			err = c.fields(s.name, s.schema.Fields)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("agent %q %s: %w", agent.Name, s.label, err)
+		}
+	}
+	return c.module, nil
+}
+
+// outputSample returns a JSON document that satisfies the agent's output
+// schema, for the fake model in the generated tests to reply with.
+func outputSample(agent *model.Agent) (string, error) {
+	if agent.OutputSchema == nil {
+		return "", nil
+	}
+
+	var sample interface{}
+	if agent.OutputSchema.JSONSchema != nil {
+		c := &schemaConverter{module: &schemaModule{}, classes: make(map[string]bool)}
+		var err error
+		if _, sample, err = c.jsonObject(outputModel(agent), agent.OutputSchema.JSONSchema, ""); err != nil {
+			return "", err
+		}
+	} else {
+		values := make(map[string]interface{})
+		for _, field := range agent.OutputSchema.Fields {
+			if field.Optional {
+				continue
+			}
+			var value interface{} = sampleValue(string(field.Type), field.Enum)
+			if field.List {
+				value = []interface{}{value}
+			}
+			values[field.Name] = value
+		}
+		sample = values
+	}
+	return toJSON(sample)
+}
+
+type schemaConverter struct {
+	module  *schemaModule
+	classes map[string]bool
+}
+
+func (c *schemaConverter) addModel(m pyModel) error {
+	if err := checkClassName(m.Name); err != nil {
+		return err
+	}
+	if c.classes[m.Name] {
+		return fmt.Errorf("class %s is generated twice; give one of the schemas another name or title", m.Name)
+	}
+	c.classes[m.Name] = true
+	c.module.Models = append(c.module.Models, m)
+	return nil
+}
+
+func (c *schemaConverter) fields(className string, fields []model.Field) error {
+	m := pyModel{Name: className}
+	for _, field := range fields {
+		if err := checkFieldName(field.Name); err != nil {
+			return err
+		}
+
+		annotation := jsonSchemaTypes[string(field.Type)]
+		if len(field.Enum) > 0 {
+			annotation = c.literal(field.Enum)
+		}
+		if field.List {
+			annotation = fmt.Sprintf("list[%s]", annotation)
+		}
+		m.Fields = append(m.Fields, c.field(field.Name, annotation, field.Description, field.Optional))
+	}
+	return c.addModel(m)
+}
+
+// jsonObject converts an object schema to a model named className and
+// returns the class name and a sample value.
+func (c *schemaConverter) jsonObject(className string, node map[string]interface{}, path string) (string, interface{}, error) {
+	if err := checkKeywords(node, path); err != nil {
+		return "", nil, err
+	}
+	if t, _ := node["type"].(string); t != "object" {
+		return "", nil, fmt.Errorf("%s must have type object", describePath(path))
+	}
+	properties, _ := node["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		return "", nil, fmt.Errorf("%s must declare properties", describePath(path))
+	}
+
+	required := make(map[string]bool)
+	var order []string
+	if list, ok := node["required"].([]interface{}); ok {
+		for _, item := range list {
+			name, ok := item.(string)
+			if !ok || properties[name] == nil {
+				return "", nil, fmt.Errorf("%s: required lists unknown property %v", describePath(path), item)
+			}
+			if !required[name] {
+				order = append(order, name)
+			}
+			required[name] = true
+		}
+	}
+	var rest []string
+	for name := range properties {
+		if !required[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	order = append(order, rest...)
+
+	m := pyModel{Name: className}
+	m.Description, _ = node["description"].(string)
+	sample := make(map[string]interface{})
+	for _, name := range order {
+		if err := checkFieldName(name); err != nil {
+			return "", nil, fmt.Errorf("%s: %w", describePath(path), err)
+		}
+		prop, ok := properties[name].(map[string]interface{})
+		if !ok {
+			return "", nil, fmt.Errorf("%s must be a schema", describePath(path+"."+name))
+		}
+
+		annotation, value, err := c.jsonType(className+naming.PascalCase(name), prop, path+"."+name)
+		if err != nil {
+			return "", nil, err
+		}
+		description, _ := prop["description"].(string)
+		m.Fields = append(m.Fields, c.field(name, annotation, description, !required[name]))
+		if required[name] {
+			sample[name] = value
+		}
+	}
+
+	if title, ok := node["title"].(string); ok && title != "" && path != "" {
+		m.Name = naming.PascalCase(title)
+	}
+	if err := c.addModel(m); err != nil {
+		return "", nil, err
+	}
+	return m.Name, sample, nil
+}
+
+func (c *schemaConverter) jsonType(className string, node map[string]interface{}, path string) (string, interface{}, error) {
+	if err := checkKeywords(node, path); err != nil {
+		return "", nil, err
+	}
+
+	t, _ := node["type"].(string)
+	switch t {
+	case "object":
+		return c.jsonObject(className, node, path)
+	case "array":
+		items, ok := node["items"].(map[string]interface{})
+		if !ok {
+			return "", nil, fmt.Errorf("%s is an array without items", describePath(path))
+		}
+		annotation, value, err := c.jsonType(className+"Item", items, path+"[]")
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("list[%s]", annotation), []interface{}{value}, nil
+	case "string", "integer", "number", "boolean":
+		var enum []string
+		if values, ok := node["enum"].([]interface{}); ok {
+			if t != "string" {
+				return "", nil, fmt.Errorf("%s: enum is only supported for strings", describePath(path))
+			}
+			for _, v := range values {
+				s, ok := v.(string)
+				if !ok {
+					return "", nil, fmt.Errorf("%s: enum values must be strings", describePath(path))
+				}
+				enum = append(enum, s)
+			}
+		}
+		if len(enum) > 0 {
+			return c.literal(enum), sampleValue(t, enum), nil
+		}
+		return jsonSchemaTypes[t], sampleValue(t, nil), nil
+	case "":
+		return "", nil, fmt.Errorf("%s needs a type", describePath(path))
+	default:
+		return "", nil, fmt.Errorf("%s has unsupported type %q", describePath(path), t)
+	}
+}
+
+func (c *schemaConverter) literal(values []string) string {
+	c.module.UsesLiteral = true
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i], _ = toJSON(v)
+	}
+	return fmt.Sprintf("Literal[%s]", strings.Join(quoted, ", "))
+}
+
+func (c *schemaConverter) field(name, annotation, description string, optional bool) pyField {
+	f := pyField{Name: name, Annotation: annotation}
+	if optional {
+		f.Annotation += " | None"
+	}
+
+	var args []string
+	if optional && description != "" {
+		args = append(args, "default=None")
+	}
+	if description != "" {
+		quoted, _ := toJSON(description)
+		args = append(args, "description="+quoted)
+	}
+
+	switch {
+	case len(args) > 0:
+		c.module.UsesField = true
+		f.Default = fmt.Sprintf(" = Field(%s)", strings.Join(args, ", "))
+	case optional:
+		f.Default = " = None"
+	}
+	return f
+}
+
+func sampleValue(t string, enum []string) interface{} {
+	if len(enum) > 0 {
+		return enum[0]
+	}
+	switch t {
+	case "integer":
+		return 1
+	case "number":
+		return 1.5
+	case "boolean":
+		return true
+	default:
+		return "example"
+	}
+}
+
+func checkKeywords(node map[string]interface{}, path string) error {
+	for _, keyword := range unsupportedKeywords {
+		if _, ok := node[keyword]; ok {
+			return fmt.Errorf("%s uses %s, which is not supported; inline the schema instead", describePath(path), keyword)
+		}
+	}
+	if _, ok := node["type"].([]interface{}); ok {
+		return fmt.Errorf("%s must have a single type; list the property as optional instead of adding null", describePath(path))
+	}
+	return nil
+}
+
+func describePath(path string) string {
+	if path == "" {
+		return "schema"
+	}
+	return "property " + strings.TrimPrefix(path, ".")
+}
+
+func checkClassName(name string) error {
+	switch {
+	case !pythonIdentifier.MatchString(name):
+		return fmt.Errorf("class name %q is not a valid Python identifier", name)
+	case pythonKeywords[name], pythonBuiltins[name], pydanticReserved[name]:
+		return fmt.Errorf("class name %q is reserved in Python or Pydantic", name)
+	}
+	return nil
+}
+
+func checkFieldName(name string) error {
+	switch {
+	case !pythonIdentifier.MatchString(name):
+		return fmt.Errorf("field name %q is not a valid Python identifier", name)
+	case strings.HasPrefix(name, "_"), strings.HasPrefix(name, "model_"):
+		return fmt.Errorf("field name %q cannot start with _ or model_, which Pydantic reserves", name)
+	case pythonKeywords[name], pythonBuiltins[name], pydanticReserved[name]:
+		return fmt.Errorf("field name %q is reserved in Python or Pydantic", name)
+	}
+	return nil
+}
+
+// checkTools checks that the agent's tool names are usable as Python
+// function names in tools.py and agent.py.
+func checkTools(agent *model.Agent, module *schemaModule) error {
+	for _, tool := range agent.Tools {
+		var problem string
+		switch {
+		case !pythonIdentifier.MatchString(tool):
+			problem = "is not a valid Python identifier"
+		case pythonKeywords[tool], pythonBuiltins[tool]:
+			problem = "is a Python keyword or builtin"
+		case reservedIdentifiers[tool] != "", tool == "LlmAgent":
+			problem = "is already used in agent.py"
+		case module != nil && containsModel(module.Models, tool):
+			problem = "clashes with a schema class"
+		case agent.Memory && tool == "load_memory":
+			problem = "clashes with ADK's load_memory tool, which memory adds"
+		case agent.PromptFile && (tool == "load_prompt" || tool == "Path"):
+			problem = "clashes with the prompt file loader"
+		case usesCallbackFunction(agent, tool):
+			problem = "clashes with a callback function"
+		default:
+			continue
+		}
+		return fmt.Errorf("agent %q tool %q %s", agent.Name, tool, problem)
+	}
+	return nil
+}
+
+func usesCallbackFunction(agent *model.Agent, name string) bool {
+	for _, hook := range agent.CallbackHooks() {
+		if naming.SnakeCase(string(hook)) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func containsModel(models []pyModel, name string) bool {
+	for _, m := range models {
+		if m.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAgentCode checks the Python names an agent's schemas and tools
+// generate.
+func checkAgentCode(agent *model.Agent) error {
+	module, err := agentSchemas(agent)
+	if err != nil {
+		return err
+	}
+	return checkTools(agent, module)
+}
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/doji-co/agent-builder/internal/pysyntax"
+)
+
+type VerifyError struct {
+	Path     string
+	Template string
+	Line     int
+	Source   string
+	Msg      string
+}
+
+func (e *VerifyError) Error() string {
+	msg := fmt.Sprintf("generated %s (from %s) is not valid Python: line %d: %s", e.Path, e.Template, e.Line, e.Msg)
+	if e.Source != "" {
+		msg += fmt.Sprintf("\n    %d | %s", e.Line, e.Source)
+	}
+	return msg
+}
+
+// Verify checks that every rendered Python file compiles and every JSON file
+// parses, so a template bug is reported before anything reaches the disk.
+func (g *Generator) Verify(files []File) error {
+	for _, file := range files {
+		if filepath.Ext(file.Path) == ".json" {
+			if !json.Valid([]byte(file.Content)) {
+				return fmt.Errorf("generated %s (from %s) is not valid JSON", file.Path, file.Template)
+			}
+			continue
+		}
+		if filepath.Ext(file.Path) != ".py" {
+			continue
+		}
+
+		err := g.checker.Check(file.Path, file.Content)
+		if err == nil {
+			continue
+		}
+
+		var syntaxErr *pysyntax.Error
+		if !errors.As(err, &syntaxErr) {
+			return fmt.Errorf("failed to verify %s: %w", file.Path, err)
+		}
+
+		return &VerifyError{
+			Path:     file.Path,
+			Template: file.Template,
+			Line:     syntaxErr.Line,
+			Source:   sourceLine(file.Content, syntaxErr.Line),
+			Msg:      syntaxErr.Msg,
+		}
+	}
+	return nil
+}
+
+func sourceLine(content string, line int) string {
+	lines := strings.Split(content, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return strings.TrimRight(lines[line-1], "\r")
+}
@@ -0,0 +1,80 @@
+package importer
+
+import (
+	"strings"
+
+	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/naming"
+)
+
+// callbackKwarg returns the LlmAgent argument a hook is registered with, such
+// as before_model_callback.
+func callbackKwarg(hook model.Callback) string {
+	return naming.SnakeCase(string(hook)) + "_callback"
+}
+
+// callbacksArg maps the agent's *_callback arguments back to the spec's
+// hooks, in the order ADK runs them. A hook function from the package's
+// callbacks.py also brings back the recipes generated into it, which are
+// marked by a "# <recipe>:" comment in its body.
+func (imp *importer) callbacksArg(ref agentRef) []model.Callback {
+	var callbacks []model.Callback
+	for _, hook := range model.CallbackHooks {
+		key := callbackKwarg(hook)
+		v, ok := ref.call.kwarg(key)
+		if !ok {
+			continue
+		}
+		if v.kind != valueName {
+			imp.warn(ref.pkg, v.line, "%s=%s is not a function and will be dropped", key, describe(v))
+			continue
+		}
+
+		recipes, stub := imp.callbackRecipes(ref.pkg, v, hook)
+		if stub {
+			callbacks = append(callbacks, hook)
+		}
+		callbacks = append(callbacks, recipes...)
+	}
+	return callbacks
+}
+
+// callbackRecipes returns the recipes in the hook function v names and
+// whether the function also holds the hook's own stub. A function defined
+// outside the package's modules is taken as the stub.
+func (imp *importer) callbackRecipes(pkg string, v value, hook model.Callback) ([]model.Callback, bool) {
+	path, fn, ok := imp.localImport(pkg, v)
+	if !ok {
+		return nil, true
+	}
+	src, err := imp.readSource(path)
+	if err != nil {
+		return nil, true
+	}
+	body := functionBody(src, fn)
+
+	var recipes []model.Callback
+	for _, recipe := range model.CallbackRecipes {
+		if recipe.Hook() == hook && strings.Contains(body, "# "+string(recipe)+":") {
+			recipes = append(recipes, recipe)
+		}
+	}
+	// The stub leaves a TODO; a function with only recipes has none.
+	return recipes, len(recipes) == 0 || strings.Contains(body, "# TODO")
+}
+
+// functionBody returns the source of the top-level function fn up to the next
+// top-level definition.
+func functionBody(src, fn string) string {
+	start := strings.Index(src, "\ndef "+fn+"(")
+	if start < 0 {
+		return ""
+	}
+	body := src[start+1:]
+	for _, next := range []string{"\ndef ", "\nclass ", "\n@"} {
+		if end := strings.Index(body, next); end >= 0 {
+			body = body[:end]
+		}
+	}
+	return body
+}
@@ -0,0 +1,180 @@
+package importer
+
+import (
+	"strconv"
+
+	"github.com/doji-co/agent-builder/internal/model"
+)
+
+// generationArgs reads generate_content_config=types.GenerateContentConfig(...)
+// and the thinking budget of planner=BuiltInPlanner(...) back into a
+// generation config. Settings the spec has no field for are reported and
+// dropped. It returns nil when the agent sets neither.
+func (imp *importer) generationArgs(ref agentRef) *model.GenerationConfig {
+	config := &model.GenerationConfig{}
+	set := false
+
+	if v, ok := ref.call.kwarg("generate_content_config"); ok {
+		if v.kind != valueCall || className(v.call.fn) != "GenerateContentConfig" {
+			imp.warn(ref.pkg, v.line, "generate_content_config=%s is not a types.GenerateContentConfig(...) call and will be dropped", describe(v))
+		} else {
+			set = imp.contentConfig(ref.pkg, v.call, config) || set
+		}
+	}
+
+	if v, ok := ref.call.kwarg("planner"); ok {
+		if budget, ok := imp.thinkingBudget(ref.pkg, v); ok {
+			config.ThinkingBudget = &budget
+			set = true
+		}
+	}
+
+	if !set {
+		return nil
+	}
+	return config
+}
+
+// contentConfig copies the arguments of a GenerateContentConfig call into
+// config and reports whether it set any.
+func (imp *importer) contentConfig(pkg string, c *call, config *model.GenerationConfig) bool {
+	set := false
+	for _, kw := range c.kwargs {
+		v := kw.value
+		ok := false
+		switch kw.name {
+		case "temperature":
+			config.Temperature, ok = imp.floatValue(pkg, kw)
+		case "top_p":
+			config.TopP, ok = imp.floatValue(pkg, kw)
+		case "top_k":
+			config.TopK, ok = imp.intValue(pkg, kw)
+		case "max_output_tokens":
+			config.MaxOutputTokens, ok = imp.intValue(pkg, kw)
+		case "stop_sequences":
+			config.StopSequences, ok = imp.stopSequences(pkg, v)
+		case "safety_settings":
+			config.SafetySettings, ok = imp.safetySettings(pkg, v)
+		default:
+			imp.warn(pkg, v.line, "generate_content_config: %s=%s is not represented in the spec and will be dropped", kw.name, describe(v))
+		}
+		set = set || ok
+	}
+	if len(c.args) > 0 {
+		imp.warn(pkg, c.args[0].line, "positional arguments to %s are not supported and will be dropped", c.fn)
+	}
+	return set
+}
+
+func (imp *importer) floatValue(pkg string, kw kwarg) (*float64, bool) {
+	if kw.value.kind == valueNumber {
+		if f, err := strconv.ParseFloat(kw.value.text, 64); err == nil {
+			return &f, true
+		}
+	}
+	imp.warn(pkg, kw.value.line, "generate_content_config: %s=%s is not a number literal and will be dropped", kw.name, describe(kw.value))
+	return nil, false
+}
+
+func (imp *importer) intValue(pkg string, kw kwarg) (*int, bool) {
+	if kw.value.kind == valueNumber {
+		if n, err := strconv.Atoi(kw.value.text); err == nil {
+			return &n, true
+		}
+	}
+	imp.warn(pkg, kw.value.line, "generate_content_config: %s=%s is not an integer literal and will be dropped", kw.name, describe(kw.value))
+	return nil, false
+}
+
+func (imp *importer) stopSequences(pkg string, v value) ([]string, bool) {
+	if v.kind != valueList {
+		imp.warn(pkg, v.line, "generate_content_config: stop_sequences=%s is not a list and will be dropped", describe(v))
+		return nil, false
+	}
+	var stops []string
+	for _, item := range v.items {
+		if item.kind != valueString {
+			imp.warn(pkg, item.line, "generate_content_config: stop sequence %s is not a string literal and will be dropped", describe(item))
+			continue
+		}
+		stops = append(stops, item.text)
+	}
+	return stops, len(stops) > 0
+}
+
+// safetySettings reads a list of
+// types.SafetySetting(category=types.HarmCategory.X, threshold=types.HarmBlockThreshold.Y).
+func (imp *importer) safetySettings(pkg string, v value) ([]model.SafetySetting, bool) {
+	if v.kind != valueList {
+		imp.warn(pkg, v.line, "generate_content_config: safety_settings=%s is not a list and will be dropped", describe(v))
+		return nil, false
+	}
+
+	var settings []model.SafetySetting
+	for _, item := range v.items {
+		if item.kind != valueCall || className(item.call.fn) != "SafetySetting" {
+			imp.warn(pkg, item.line, "generate_content_config: safety setting %s is not a types.SafetySetting(...) call and will be dropped", describe(item))
+			continue
+		}
+		var setting model.SafetySetting
+		if category, ok := item.call.kwarg("category"); ok {
+			for _, c := range model.HarmCategories {
+				if enumMember(category) == c.Python() {
+					setting.Category = c
+				}
+			}
+		}
+		if threshold, ok := item.call.kwarg("threshold"); ok {
+			for _, t := range model.HarmThresholds {
+				if enumMember(threshold) == t.Python() {
+					setting.Threshold = t
+				}
+			}
+		}
+		if setting.Category == "" || setting.Threshold == "" {
+			imp.warn(pkg, item.line, "generate_content_config: safety setting %s has a category or threshold the spec does not know and will be dropped", describe(item))
+			continue
+		}
+		settings = append(settings, setting)
+	}
+	return settings, len(settings) > 0
+}
+
+// thinkingBudget reads planner=BuiltInPlanner(thinking_config=
+// types.ThinkingConfig(thinking_budget=N)).
+func (imp *importer) thinkingBudget(pkg string, v value) (int, bool) {
+	if v.kind != valueCall || className(v.call.fn) != "BuiltInPlanner" {
+		imp.warn(pkg, v.line, "planner=%s is not a BuiltInPlanner and will be dropped", describe(v))
+		return 0, false
+	}
+	config, ok := v.call.kwarg("thinking_config")
+	if !ok || config.kind != valueCall || className(config.call.fn) != "ThinkingConfig" {
+		imp.warn(pkg, v.line, "planner=%s has no types.ThinkingConfig(...) and will be dropped", describe(v))
+		return 0, false
+	}
+
+	budget := 0
+	found := false
+	for _, kw := range config.call.kwargs {
+		if kw.name != "thinking_budget" {
+			imp.warn(pkg, kw.value.line, "planner: %s=%s is not represented in the spec and will be dropped", kw.name, describe(kw.value))
+			continue
+		}
+		n, err := strconv.Atoi(kw.value.text)
+		if kw.value.kind != valueNumber || err != nil {
+			imp.warn(pkg, kw.value.line, "planner: thinking_budget=%s is not an integer literal and will be dropped", describe(kw.value))
+			continue
+		}
+		budget, found = n, true
+	}
+	return budget, found
+}
+
+// enumMember returns the member name of types.HarmCategory.X, or the text of
+// a string such as "HARM_CATEGORY_X", which google.genai accepts as well.
+func enumMember(v value) string {
+	if v.kind == valueString {
+		return v.text
+	}
+	return className(v.text)
+}
@@ -0,0 +1,783 @@
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/doji-co/agent-builder/internal/adk"
+	"github.com/doji-co/agent-builder/internal/doctor"
+	"github.com/doji-co/agent-builder/internal/generator"
+	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/naming"
+)
+
+var workflowPatterns = map[string]model.OrchestrationPattern{
+	"SequentialAgent": model.PatternSequential,
+	"ParallelAgent":   model.PatternParallel,
+	"LoopAgent":       model.PatternLoop,
+}
+
+var llmClasses = map[string]bool{
+	"LlmAgent": true,
+	"Agent":    true,
+}
+
+// handledKwargs are the constructor arguments the spec represents; any other
+// argument produces a warning.
+var handledKwargs = map[string]bool{
+	"name":        true,
+	"model":       true,
+	"description": true,
+	"instruction": true,
+	"output_key":  true,
+	"sub_agents":  true,
+	"tools":       true,
+
+	"global_instruction":          true,
+	"static_instruction":          true,
+	"disallow_transfer_to_parent": true,
+	"disallow_transfer_to_peers":  true,
+
+	"input_schema":            true,
+	"output_schema":           true,
+	"generate_content_config": true,
+	"planner":                 true,
+
+	"before_agent_callback": true,
+	"after_agent_callback":  true,
+	"before_model_callback": true,
+	"after_model_callback":  true,
+	"before_tool_callback":  true,
+	"after_tool_callback":   true,
+}
+
+type Warning struct {
+	Path string
+	Line int
+	Msg  string
+}
+
+func (w Warning) String() string {
+	if w.Line == 0 {
+		return fmt.Sprintf("%s: %s", w.Path, w.Msg)
+	}
+	return fmt.Sprintf("%s:%d: %s", w.Path, w.Line, w.Msg)
+}
+
+type Result struct {
+	Project *model.Project
+	// Files are the Python files the project was read from.
+	Files    []generator.File
+	Warnings []Warning
+}
+
+type importer struct {
+	dir      string
+	modules  map[string]*module
+	result   *Result
+	imported map[string]bool
+	// sources caches the other modules of the packages read so far, by path.
+	sources map[string]string
+}
+
+// agentRef is an agent constructor call and the module it was found in.
+type agentRef struct {
+	pkg  string
+	name string
+	call *call
+	line int
+}
+
+// Import reads the agent packages in dir, each a folder with an agent.py,
+// and rebuilds the project from the agent constructor calls it finds. Python
+// is never executed, so only literal arguments can be recovered; everything
+// else is reported as a warning.
+func Import(dir string) (*Result, error) {
+	imp := &importer{
+		dir:      dir,
+		modules:  make(map[string]*module),
+		result:   &Result{},
+		imported: make(map[string]bool),
+		sources:  make(map[string]string),
+	}
+
+	if err := imp.parsePackages(); err != nil {
+		return nil, err
+	}
+
+	root, err := imp.findRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	orchestrator, err := imp.orchestrator(root)
+	if err != nil {
+		return nil, err
+	}
+
+	project := model.NewProject(projectName(dir), orchestrator)
+	imp.detectSettings(project)
+	imp.result.Project = project
+
+	if err := project.Validate(); err != nil {
+		imp.warn("", 0, "imported project is not valid yet: %v", err)
+	} else if err := generator.ValidateIdentifiers(project); err != nil {
+		imp.warn("", 0, "imported project cannot be generated as is: %v", err)
+	}
+	for _, warning := range orchestrator.PlaceholderWarnings() {
+		imp.warn("", 0, "%s", warning)
+	}
+
+	return imp.result, nil
+}
+
+func (imp *importer) parsePackages() error {
+	matches, err := filepath.Glob(filepath.Join(imp.dir, "*", "agent.py"))
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no <package>/agent.py found in %s", imp.dir)
+	}
+	sort.Strings(matches)
+
+	for _, match := range matches {
+		pkg := filepath.Base(filepath.Dir(match))
+		path := filepath.Join(pkg, "agent.py")
+
+		src, err := os.ReadFile(match)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		m, err := parseModule(string(src))
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", filepath.ToSlash(path), err)
+		}
+		imp.modules[pkg] = m
+		imp.result.Files = append(imp.result.Files, generator.File{Path: path, Content: string(src)})
+
+		initPath := filepath.Join(pkg, "__init__.py")
+		if init, err := os.ReadFile(filepath.Join(imp.dir, initPath)); err == nil {
+			imp.result.Files = append(imp.result.Files, generator.File{Path: initPath, Content: string(init)})
+		}
+	}
+	return nil
+}
+
+// findRoot returns the root_agent of the package that is not itself used as a
+// sub-agent by another package.
+func (imp *importer) findRoot() (agentRef, error) {
+	var roots []agentRef
+	for _, pkg := range imp.packageNames() {
+		if _, ok := imp.modules[pkg].lookup("root_agent"); !ok {
+			continue
+		}
+		ref, ok := imp.resolve(pkg, value{kind: valueName, text: "root_agent"}, 0)
+		if !ok {
+			imp.warn(pkg, 0, "root_agent is not an agent constructor call agent-builder can read")
+			continue
+		}
+		roots = append(roots, ref)
+	}
+
+	if len(roots) == 0 {
+		return agentRef{}, fmt.Errorf("no root_agent defined in any agent.py in %s", imp.dir)
+	}
+
+	for _, candidate := range roots {
+		if !imp.usedAsSubAgent(candidate) {
+			for _, other := range roots {
+				if other != candidate && !imp.usedAsSubAgent(other) {
+					imp.warn(other.pkg, other.line, "also defines root_agent; using %s", candidate.pkg)
+				}
+			}
+			return candidate, nil
+		}
+	}
+	return roots[0], nil
+}
+
+func (imp *importer) usedAsSubAgent(ref agentRef) bool {
+	for _, pkg := range imp.packageNames() {
+		for _, a := range imp.modules[pkg].assigns {
+			if a.value.kind != valueCall {
+				continue
+			}
+			subAgents, ok := a.value.call.kwarg("sub_agents")
+			if !ok {
+				continue
+			}
+			for _, item := range subAgents.items {
+				if sub, ok := imp.resolve(pkg, item, 0); ok && sub.call == ref.call {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (imp *importer) orchestrator(root agentRef) (*model.Orchestrator, error) {
+	class := className(root.call.fn)
+
+	pattern, ok := workflowPatterns[class]
+	if !ok {
+		if !llmClasses[class] {
+			return nil, fmt.Errorf("%s: root agent is a %s, which agent-builder cannot represent", imp.path(root.pkg), class)
+		}
+		pattern = model.PatternLLMCoordinated
+	}
+
+	subAgents, ok := root.call.kwarg("sub_agents")
+	if !ok || len(subAgents.items) == 0 {
+		return nil, fmt.Errorf("%s: root agent %s has no sub_agents; agent-builder projects need an orchestrator", imp.path(root.pkg), root.name)
+	}
+
+	name := imp.stringArg(root, "name", root.name)
+	// Workflow agents take no model; the spec still records the default.
+	modelName := imp.stringArg(root, "model", model.DefaultModel)
+	orchestrator := model.NewOrchestrator(name, pattern, imp.stringArg(root, "description", ""), modelName)
+	orchestrator.GlobalInstruction = imp.stringArg(root, "global_instruction", "")
+	orchestrator.GenerationConfig = imp.generationArgs(root)
+
+	// Settings the spec only has for sub-agents.
+	subAgentOnly := []string{"output_key", "input_schema", "output_schema"}
+	for _, hook := range model.CallbackHooks {
+		subAgentOnly = append(subAgentOnly, callbackKwarg(hook))
+	}
+	for _, key := range subAgentOnly {
+		if v, ok := root.call.kwarg(key); ok {
+			imp.warn(root.pkg, v.line, "%s of the orchestrator is not represented in the spec and will be dropped", key)
+		}
+	}
+	imp.warnUnhandled(root)
+	imp.imported[root.pkg] = true
+
+	for _, item := range subAgents.items {
+		ref, ok := imp.resolve(root.pkg, item, 0)
+		if !ok {
+			imp.warn(root.pkg, item.line, "cannot resolve sub-agent %s; skipped", describe(item))
+			continue
+		}
+		if agent := imp.subAgent(ref, pattern); agent != nil {
+			orchestrator.AddSubAgent(agent)
+		}
+	}
+
+	// The coordinator's instruction is generated from the sub-agents'
+	// descriptions; only a hand-written one is lost.
+	if v, ok := root.call.kwarg("instruction"); ok && pattern == model.PatternLLMCoordinated {
+		if v.kind != valueString || v.text != generator.RoutingInstruction(orchestrator) {
+			imp.warn(root.pkg, v.line, "instruction of the orchestrator is generated from the sub-agent descriptions; the current one will be replaced")
+		}
+	}
+
+	imp.warnUnused()
+	return orchestrator, nil
+}
+
+func (imp *importer) subAgent(ref agentRef, pattern model.OrchestrationPattern) *model.Agent {
+	class := className(ref.call.fn)
+	imp.imported[ref.pkg] = true
+
+	if _, ok := workflowPatterns[class]; ok {
+		imp.warn(ref.pkg, ref.line, "nested %s %s is not supported; skipped", class, ref.name)
+		return nil
+	}
+
+	name := imp.stringArg(ref, "name", ref.name)
+	agentType := model.AgentTypeLLM
+	if !llmClasses[class] {
+		agentType = model.AgentTypeCustom
+		imp.warn(ref.pkg, ref.line, "%s is a custom %s; imported as a custom agent without its implementation", name, class)
+	}
+
+	if _, ok := ref.call.kwarg("model"); !ok && agentType == model.AgentTypeLLM {
+		imp.warn(ref.pkg, ref.line, "%s inherits its model; the spec pins %s", name, model.DefaultModel)
+	}
+
+	instruction, promptFile := imp.instructionArg(ref, name)
+	agent := model.NewAgent(name,
+		agentType,
+		instruction,
+		imp.stringArg(ref, "output_key", ""),
+		imp.stringArg(ref, "model", model.DefaultModel),
+	)
+
+	agent.Description = imp.stringArg(ref, "description", "")
+	agent.PromptFile = promptFile
+	agent.StaticInstruction = imp.stringArg(ref, "static_instruction", "")
+	agent.Tools, agent.Memory = imp.toolsArg(ref)
+	agent.InputSchema = imp.schemaArg(ref, "input_schema", naming.PascalCase(name)+"Input")
+	agent.OutputSchema = imp.schemaArg(ref, "output_schema", naming.PascalCase(name)+"Output")
+	agent.GenerationConfig = imp.generationArgs(ref)
+	agent.Callbacks = imp.callbacksArg(ref)
+	for _, key := range []string{"disallow_transfer_to_parent", "disallow_transfer_to_peers"} {
+		if !imp.boolArg(ref, key) {
+			continue
+		}
+		if agentType != model.AgentTypeLLM || pattern != model.PatternLLMCoordinated {
+			imp.warn(ref.pkg, ref.line, "%s of %s only applies under an llm-coordinated orchestrator and will be dropped", key, name)
+			continue
+		}
+		if key == "disallow_transfer_to_parent" {
+			agent.DisallowTransferToParent = true
+		} else {
+			agent.DisallowTransferToPeers = true
+		}
+	}
+
+	if v, ok := ref.call.kwarg("sub_agents"); ok {
+		imp.warn(ref.pkg, v.line, "sub-agents of %s are not supported; skipped", name)
+	}
+	imp.warnUnhandled(ref)
+
+	if folder := naming.SnakeCase(name); folder != ref.pkg && exportedByPackage(ref) {
+		imp.warn(ref.pkg, ref.line, "agent %s is in %s/ but will be generated as %s/", name, ref.pkg, folder)
+	}
+	return agent
+}
+
+// exportedByPackage reports whether ref is the agent its package exports, as
+// opposed to a helper agent defined alongside another one.
+func exportedByPackage(ref agentRef) bool {
+	return ref.name == "agent" || ref.name == "root_agent"
+}
+
+// resolve follows names and imports from the module of pkg to an agent
+// constructor call.
+func (imp *importer) resolve(pkg string, v value, depth int) (agentRef, bool) {
+	if depth > 10 {
+		return agentRef{}, false
+	}
+
+	switch v.kind {
+	case valueCall:
+		return agentRef{pkg: pkg, name: pkg, call: v.call, line: v.line}, true
+	case valueName:
+	default:
+		return agentRef{}, false
+	}
+
+	m := imp.modules[pkg]
+	if m == nil {
+		return agentRef{}, false
+	}
+
+	if assigned, ok := m.lookup(v.text); ok {
+		if assigned.kind == valueCall {
+			return agentRef{pkg: pkg, name: v.text, call: assigned.call, line: assigned.line}, true
+		}
+		return imp.resolve(pkg, assigned, depth+1)
+	}
+
+	// researcher.agent for "import researcher.agent" or "from researcher import agent".
+	head, attr, dottedRef := strings.Cut(v.text, ".")
+	if dottedRef {
+		if ref, ok := m.imports[head]; ok {
+			target := strings.TrimPrefix(ref.module+"."+ref.name, ".")
+			if ref.name == "" {
+				target = ref.module
+			}
+			return imp.resolveIn(target+"."+attr, depth)
+		}
+		return imp.resolveIn(v.text, depth)
+	}
+
+	ref, ok := m.imports[v.text]
+	if !ok || ref.name == "" {
+		return agentRef{}, false
+	}
+	return imp.resolveIn(ref.module+"."+ref.name, depth)
+}
+
+// resolveIn resolves a fully qualified name such as researcher.agent.agent.
+func (imp *importer) resolveIn(qualified string, depth int) (agentRef, bool) {
+	parts := strings.Split(qualified, ".")
+	if len(parts) < 2 || parts[1] != "agent" {
+		return agentRef{}, false
+	}
+	pkg := parts[0]
+	if imp.modules[pkg] == nil {
+		return agentRef{}, false
+	}
+	name := "agent"
+	if len(parts) > 2 {
+		name = strings.Join(parts[2:], ".")
+	}
+	return imp.resolve(pkg, value{kind: valueName, text: name}, depth+1)
+}
+
+func (imp *importer) stringArg(ref agentRef, key, fallback string) string {
+	v, ok := ref.call.kwarg(key)
+	if !ok {
+		return fallback
+	}
+	if v.kind != valueString {
+		imp.warn(ref.pkg, v.line, "%s=%s is not a string literal; using %q", key, describe(v), fallback)
+		return fallback
+	}
+	if v.err != nil {
+		imp.warn(ref.pkg, v.line, "%s: %v; using its text as written", key, v.err)
+	}
+	return v.text
+}
+
+// instructionArg returns the agent's instruction and whether it is read
+// from prompts/ with the load_prompt helper of generated agents.
+func (imp *importer) instructionArg(ref agentRef, name string) (string, bool) {
+	v, ok := ref.call.kwarg("instruction")
+	if !ok || v.kind != valueCall || v.call.fn != "load_prompt" {
+		return imp.stringArg(ref, "instruction", ""), false
+	}
+	if len(v.call.args) != 1 || v.call.args[0].kind != valueString {
+		imp.warn(ref.pkg, v.line, "instruction=%s does not name a prompt file; using \"\"", describe(v))
+		return "", false
+	}
+
+	path := filepath.Join("prompts", v.call.args[0].text)
+	content, err := os.ReadFile(filepath.Join(imp.dir, path))
+	if err != nil {
+		imp.warn(ref.pkg, v.line, "failed to read prompt file %s: %v; using \"\"", filepath.ToSlash(path), err)
+		return "", false
+	}
+	imp.result.Files = append(imp.result.Files, generator.File{Path: path, Content: string(content)})
+
+	if want := generator.PromptPath(name); path != want {
+		imp.warn(ref.pkg, v.line, "prompt file %s will be generated as %s", filepath.ToSlash(path), filepath.ToSlash(want))
+	}
+	return strings.TrimSpace(string(content)), true
+}
+
+// schemaArg reads the Pydantic model passed as key from the module of the
+// agent's package it is imported from, usually schemas.py. defaultName is the
+// class name the generator gives the schema when the spec does not name it.
+func (imp *importer) schemaArg(ref agentRef, key, defaultName string) *model.Schema {
+	v, ok := ref.call.kwarg(key)
+	if !ok {
+		return nil
+	}
+	path, class, ok := imp.localImport(ref.pkg, v)
+	if !ok {
+		imp.warn(ref.pkg, v.line, "%s=%s is not a class imported from a module of %s/ and will be dropped", key, describe(v), ref.pkg)
+		return nil
+	}
+
+	src, err := imp.readSource(path)
+	var classes map[string]*pyClass
+	if err == nil {
+		classes, err = parseClasses(src)
+	}
+	if err != nil {
+		imp.warn(ref.pkg, v.line, "failed to read %s: %v; %s dropped", filepath.ToSlash(path), err, key)
+		return nil
+	}
+
+	r := &schemaReader{classes: classes, visiting: make(map[string]bool)}
+	schema, err := r.schema(class, defaultName)
+	if err != nil {
+		imp.warn(ref.pkg, v.line, "%s=%s cannot be represented in the spec: %v; dropped", key, describe(v), err)
+		return nil
+	}
+	return schema
+}
+
+// localImport resolves a name imported into the agent.py of pkg from another
+// module of the package to that module's file and the name there.
+func (imp *importer) localImport(pkg string, v value) (string, string, bool) {
+	if v.kind != valueName {
+		return "", "", false
+	}
+	m := imp.modules[pkg]
+
+	var module, class string
+	if head, attr, dottedRef := strings.Cut(v.text, "."); dottedRef {
+		// schemas.Output for "from . import schemas".
+		ref, ok := m.imports[head]
+		if !ok || strings.Contains(attr, ".") {
+			return "", "", false
+		}
+		module, class = ref.module, attr
+		if ref.name != "" {
+			module = strings.TrimSuffix(ref.module, ".") + "." + ref.name
+		}
+	} else {
+		ref, ok := m.imports[v.text]
+		if !ok || ref.name == "" {
+			return "", "", false
+		}
+		module, class = ref.module, ref.name
+	}
+
+	switch {
+	case strings.HasPrefix(module, ".") && !strings.HasPrefix(module, ".."):
+		module = strings.TrimPrefix(module, ".")
+	case strings.HasPrefix(module, pkg+"."):
+		module = strings.TrimPrefix(module, pkg+".")
+	default:
+		return "", "", false
+	}
+	if module == "" {
+		return "", "", false
+	}
+	return filepath.Join(pkg, filepath.FromSlash(strings.ReplaceAll(module, ".", "/"))+".py"), class, true
+}
+
+// readSource reads a module of an agent package, recording it among the
+// files the project was read from.
+func (imp *importer) readSource(path string) (string, error) {
+	if src, ok := imp.sources[path]; ok {
+		return src, nil
+	}
+	data, err := os.ReadFile(filepath.Join(imp.dir, path))
+	if err != nil {
+		return "", err
+	}
+	imp.sources[path] = string(data)
+	imp.result.Files = append(imp.result.Files, generator.File{Path: path, Content: string(data)})
+	return string(data), nil
+}
+
+func (imp *importer) boolArg(ref agentRef, key string) bool {
+	v, ok := ref.call.kwarg(key)
+	if !ok {
+		return false
+	}
+	if v.kind != valueName || (v.text != "True" && v.text != "False") {
+		imp.warn(ref.pkg, v.line, "%s=%s is not True or False; using False", key, describe(v))
+		return false
+	}
+	return v.text == "True"
+}
+
+// toolsArg returns the names of the plain functions in tools=[...] and
+// whether ADK's load_memory tool is among them. Other built-in tools and tool
+// objects such as AgentTool(...) cannot be represented and are dropped.
+func (imp *importer) toolsArg(ref agentRef) ([]string, bool) {
+	v, ok := ref.call.kwarg("tools")
+	if !ok {
+		return nil, false
+	}
+	if v.kind != valueList {
+		imp.warn(ref.pkg, v.line, "tools=%s is not a list; dropped", describe(v))
+		return nil, false
+	}
+
+	var tools []string
+	memory := false
+	for _, item := range v.items {
+		if item.kind != valueName {
+			imp.warn(ref.pkg, item.line, "tool %s is not a plain function and will be dropped", describe(item))
+			continue
+		}
+		parts := strings.Split(item.text, ".")
+		name := parts[len(parts)-1]
+		if imported, ok := imp.modules[ref.pkg].imports[parts[0]]; ok && strings.HasPrefix(imported.module, "google.") {
+			if name == "load_memory" {
+				memory = true
+				continue
+			}
+			imp.warn(ref.pkg, item.line, "built-in tool %s is not represented in the spec and will be dropped", item.text)
+			continue
+		}
+		tools = append(tools, name)
+	}
+	return tools, memory
+}
+
+func (imp *importer) warnUnhandled(ref agentRef) {
+	for _, kw := range ref.call.kwargs {
+		if !handledKwargs[kw.name] {
+			imp.warn(ref.pkg, kw.value.line, "%s=%s is not represented in the spec and will be dropped", kw.name, describe(kw.value))
+		}
+	}
+	if len(ref.call.args) > 0 {
+		imp.warn(ref.pkg, ref.line, "positional arguments to %s are not supported and will be dropped", ref.call.fn)
+	}
+}
+
+func (imp *importer) warnUnused() {
+	for _, pkg := range imp.packageNames() {
+		if !imp.imported[pkg] {
+			imp.warn(pkg, 0, "package is not reachable from the root agent; skipped")
+		}
+	}
+}
+
+// detectSettings fills in the project options from the files next to the
+// agent packages.
+func (imp *importer) detectSettings(project *model.Project) {
+	exists := func(path string) bool {
+		_, err := os.Stat(filepath.Join(imp.dir, path))
+		return err == nil
+	}
+	read := func(path string) string {
+		data, _ := os.ReadFile(filepath.Join(imp.dir, path))
+		return string(data)
+	}
+
+	project.AddExample = exists("main.py")
+	project.AddReadme = exists("README.md")
+	project.AddTests = exists("tests")
+	project.AddEval = exists("eval")
+	project.AddDocker = exists("Dockerfile")
+
+	dependencies := read("requirements.txt")
+	if pyproject := read("pyproject.toml"); pyproject != "" {
+		dependencies = pyproject
+		project.Packaging = model.PackagingUV
+		if strings.Contains(pyproject, "[tool.poetry]") {
+			project.Packaging = model.PackagingPoetry
+		}
+	}
+
+	if version, ok := adkVersion(dependencies); ok {
+		project.ADKVersion = version.Name
+	}
+	if version, err := adk.Lookup(project.ADKVersion); err == nil && project.AddEval && !version.EvalSets {
+		imp.warn("eval", 0, "ADK %s has no EvalSet support; evaluation disabled", version.Name)
+		project.AddEval = false
+	}
+
+	env := read(".env.example") + read(".env")
+	if regexp.MustCompile(`(?mi)^\s*(export\s+)?GOOGLE_GENAI_USE_VERTEXAI\s*=\s*["']?(true|1)`).MatchString(env) {
+		project.Backend = model.BackendVertexAI
+	}
+
+	imp.detectServices(project, read("main.py"))
+}
+
+// detectServices reads the services a runner in main.py constructs, with the
+// defaults it falls back to when their environment variables are unset.
+func (imp *importer) detectServices(project *model.Project, mainPy string) {
+	services := &project.Services
+	envDefault := func(name string) string {
+		match := regexp.MustCompile(name + `",\s*"([^"]*)"`).FindStringSubmatch(mainPy)
+		if match == nil {
+			return ""
+		}
+		return match[1]
+	}
+
+	switch {
+	case strings.Contains(mainPy, "DatabaseSessionService("):
+		services.Session = model.SessionDatabase
+		if url := envDefault("DATABASE_URL"); url != model.DefaultDatabaseURL {
+			services.DatabaseURL = url
+		}
+	case strings.Contains(mainPy, "VertexAiSessionService("):
+		services.Session = model.SessionVertexAI
+	}
+
+	switch {
+	case strings.Contains(mainPy, "InMemoryMemoryService("):
+		services.Memory = model.MemoryInMemory
+	case strings.Contains(mainPy, "VertexAiRagMemoryService("):
+		services.Memory = model.MemoryVertexAIRAG
+		services.RAGCorpus = envDefault("RAG_CORPUS")
+	}
+
+	switch {
+	case strings.Contains(mainPy, "LocalArtifactService("):
+		services.Artifact = model.ArtifactLocal
+		if dir := envDefault("ARTIFACT_DIR"); dir != model.DefaultArtifactDir {
+			services.ArtifactDir = dir
+		}
+	case strings.Contains(mainPy, "GcsArtifactService("):
+		services.Artifact = model.ArtifactGCS
+		services.Bucket = envDefault("ARTIFACT_BUCKET")
+	}
+
+	if services.Memory != model.MemoryNone {
+		return
+	}
+	for _, agent := range project.Orchestrator.SubAgents {
+		if agent.Memory {
+			imp.warn("main.py", 0, "%s uses load_memory but no memory service was found; using in-memory", agent.Name)
+			services.Memory = model.MemoryInMemory
+			return
+		}
+	}
+}
+
+var adkPin = regexp.MustCompile(`google-adk(?:\[[^\]]*\])?\s*(?:=\s*\{[^}]*version\s*=\s*")?\s*[=>~^]*=?\s*v?(\d+(?:\.\d+)*)`)
+
+// adkVersion finds the google-adk version a dependency file pins and returns
+// the matrix entry covering it.
+func adkVersion(dependencies string) (adk.Version, bool) {
+	match := adkPin.FindStringSubmatch(dependencies)
+	if match == nil {
+		return adk.Version{}, false
+	}
+	// Ranges overlap, so the newest one the pin satisfies is the most
+	// specific.
+	versions := adk.Versions()
+	for i := len(versions) - 1; i >= 0; i-- {
+		if doctor.Satisfies(match[1], versions[i].Requirement) {
+			return versions[i], true
+		}
+	}
+	return adk.Version{}, false
+}
+
+func (imp *importer) warn(pkg string, line int, format string, args ...interface{}) {
+	path := "."
+	if pkg != "" {
+		path = imp.path(pkg)
+	}
+	imp.result.Warnings = append(imp.result.Warnings, Warning{Path: path, Line: line, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (imp *importer) path(pkg string) string {
+	if imp.modules[pkg] == nil {
+		return pkg
+	}
+	return pkg + "/agent.py"
+}
+
+func (imp *importer) packageNames() []string {
+	names := make([]string, 0, len(imp.modules))
+	for pkg := range imp.modules {
+		names = append(names, pkg)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var projectNameInvalid = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// projectName names the project after its directory, with the characters a
+// project name cannot hold replaced by hyphens.
+func projectName(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	name := strings.Trim(projectNameInvalid.ReplaceAllString(filepath.Base(abs), "-"), "-")
+	if name == "" {
+		return "project"
+	}
+	return name
+}
+
+func className(fn string) string {
+	parts := strings.Split(fn, ".")
+	return parts[len(parts)-1]
+}
+
+func describe(v value) string {
+	switch v.kind {
+	case valueString:
+		return fmt.Sprintf("%q", v.text)
+	case valueCall:
+		return v.text + "(...)"
+	case valueList:
+		return "[...]"
+	default:
+		return v.text
+	}
+}
@@ -0,0 +1,549 @@
+package importer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/doji-co/agent-builder/internal/generator"
+	"github.com/doji-co/agent-builder/internal/model"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for path, content := range files {
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestImport_GeneratedProject(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   model.OrchestrationPattern
+		packaging model.Packaging
+		services  model.Services
+	}{
+		{name: "sequential with requirements", pattern: model.PatternSequential, packaging: model.PackagingRequirements, services: model.DefaultServices()},
+		{name: "parallel with uv", pattern: model.PatternParallel, packaging: model.PackagingUV, services: model.DefaultServices()},
+		{name: "llm-coordinated with poetry", pattern: model.PatternLLMCoordinated, packaging: model.PackagingPoetry, services: model.DefaultServices()},
+		{name: "loop", pattern: model.PatternLoop, packaging: model.PackagingRequirements, services: model.DefaultServices()},
+		{
+			name:      "persistent services",
+			pattern:   model.PatternSequential,
+			packaging: model.PackagingRequirements,
+			services: model.Services{
+				Session:     model.SessionDatabase,
+				DatabaseURL: "postgresql://localhost/agents",
+				Memory:      model.MemoryInMemory,
+				Artifact:    model.ArtifactLocal,
+				ArtifactDir: "data/artifacts",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orch := model.NewOrchestrator("research_coordinator", tt.pattern, "Coordinates research", "gemini-2.5-pro")
+			researcher := model.NewAgent("researcher", model.AgentTypeLLM, "Research the topic", "research_data", "gemini-2.5-flash")
+			researcher.Description = "Finds sources on a topic"
+			researcher.PromptFile = true
+			instruction := "Write based on {research_data}"
+			if tt.pattern == model.PatternParallel {
+				// Parallel sub-agents cannot read each other's output.
+				instruction = "Write based on {research_data?}"
+			}
+			writer := model.NewAgent("writer", model.AgentTypeLLM, instruction, "draft", "gemini-2.5-flash")
+			if tt.pattern == model.PatternLLMCoordinated {
+				writer.DisallowTransferToPeers = true
+				writer.StaticInstruction = "You write for a general audience."
+				orch.GlobalInstruction = "Answer in {user:language?}."
+			}
+			researcher.Memory = tt.services.Memory != model.MemoryNone
+			orch.AddSubAgent(researcher)
+			orch.AddSubAgent(writer)
+
+			project := model.NewProject("demo", orch)
+			project.Packaging = tt.packaging
+			project.Services = tt.services
+			project.AddTests = false
+			if tt.pattern == model.PatternLLMCoordinated {
+				project.ADKVersion = "1.15"
+			}
+
+			files, err := generator.NewGenerator().RenderProject(project)
+			if err != nil {
+				t.Fatalf("RenderProject() error = %v", err)
+			}
+			dir := filepath.Join(t.TempDir(), "demo")
+			if err := generator.WriteFiles(dir, files); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := Import(dir)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+
+			got := result.Project
+			if got.Name != "demo" {
+				t.Errorf("Name = %v, want demo", got.Name)
+			}
+			if got.Packaging != tt.packaging {
+				t.Errorf("Packaging = %v, want %v", got.Packaging, tt.packaging)
+			}
+			if got.AddTests || !got.AddEval || !got.AddExample || !got.AddReadme {
+				t.Errorf("options = tests %v, eval %v, example %v, readme %v; want false, true, true, true",
+					got.AddTests, got.AddEval, got.AddExample, got.AddReadme)
+			}
+			if got.Services != tt.services {
+				t.Errorf("Services = %+v, want %+v", got.Services, tt.services)
+			}
+			if got.ADKVersion != project.ADKVersion {
+				t.Errorf("ADKVersion = %v, want %v", got.ADKVersion, project.ADKVersion)
+			}
+
+			o := got.Orchestrator
+			if o.Name != orch.Name || o.Pattern != orch.Pattern || o.Description != orch.Description || o.GlobalInstruction != orch.GlobalInstruction {
+				t.Errorf("Orchestrator = %+v, want %+v", o, orch)
+			}
+			if len(o.SubAgents) != 2 {
+				t.Fatalf("SubAgents = %d, want 2", len(o.SubAgents))
+			}
+			for i, want := range orch.SubAgents {
+				if !reflect.DeepEqual(o.SubAgents[i], want) {
+					t.Errorf("SubAgents[%d] = %+v, want %+v", i, *o.SubAgents[i], *want)
+				}
+			}
+
+			for _, w := range result.Warnings {
+				if !strings.Contains(w.Msg, "inherits its model") {
+					t.Errorf("unexpected warning: %s", w)
+				}
+			}
+		})
+	}
+}
+
+func TestImport_AgentSettings(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(agent *model.Agent)
+	}{
+		{
+			name: "output schema fields",
+			edit: func(agent *model.Agent) {
+				agent.OutputSchema = &model.Schema{Fields: []model.Field{
+					{Name: "summary", Type: model.FieldTypeString, Description: "One paragraph"},
+					{Name: "sources", Type: model.FieldTypeString, List: true},
+					{Name: "confidence", Type: model.FieldTypeNumber, Optional: true},
+					{Name: "tone", Type: model.FieldTypeString, Enum: []string{"neutral", "critical"}, Optional: true, Description: "How it reads"},
+				}}
+			},
+		},
+		{
+			name: "named input and output schemas",
+			edit: func(agent *model.Agent) {
+				agent.InputSchema = &model.Schema{Name: "Topic", Fields: []model.Field{{Name: "topic", Type: model.FieldTypeString}}}
+				agent.OutputSchema = &model.Schema{Name: "Findings", Fields: []model.Field{{Name: "count", Type: model.FieldTypeInteger}}}
+			},
+		},
+		{
+			name: "nested output schema",
+			edit: func(agent *model.Agent) {
+				agent.OutputSchema = &model.Schema{JSONSchema: map[string]interface{}{
+					"type":        "object",
+					"description": "Research results",
+					"required":    []interface{}{"sources"},
+					"properties": map[string]interface{}{
+						"sources": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"type":     "object",
+								"title":    "Source",
+								"required": []interface{}{"url"},
+								"properties": map[string]interface{}{
+									"url":  map[string]interface{}{"type": "string", "description": "Where it was found"},
+									"kind": map[string]interface{}{"type": "string", "enum": []interface{}{"paper", "web"}},
+								},
+							},
+						},
+						"meta": map[string]interface{}{
+							"type":       "object",
+							"properties": map[string]interface{}{"reviewed": map[string]interface{}{"type": "boolean"}},
+						},
+					},
+				}}
+			},
+		},
+		{
+			name: "generation config",
+			edit: func(agent *model.Agent) {
+				temperature, topP, topK, maxTokens, budget := 0.3, 0.95, 40, 2048, 1024
+				agent.GenerationConfig = &model.GenerationConfig{
+					Temperature:     &temperature,
+					TopP:            &topP,
+					TopK:            &topK,
+					MaxOutputTokens: &maxTokens,
+					StopSequences:   []string{"END", "\"quoted\""},
+					SafetySettings: []model.SafetySetting{
+						{Category: model.HarmCategoryHarassment, Threshold: model.HarmThresholdOnlyHigh},
+						{Category: model.HarmCategoryDangerousContent, Threshold: model.HarmThresholdOff},
+					},
+					ThinkingBudget: &budget,
+				}
+			},
+		},
+		{
+			name: "callback hooks",
+			edit: func(agent *model.Agent) {
+				agent.Tools = []string{"search"}
+				agent.Callbacks = []model.Callback{model.CallbackBeforeAgent, model.CallbackBeforeModel, model.CallbackBeforeTool, model.CallbackAfterTool}
+			},
+		},
+		{
+			name: "callback recipes",
+			edit: func(agent *model.Agent) {
+				agent.Callbacks = []model.Callback{
+					model.CallbackAfterAgent,
+					model.CallbackStateSnapshot,
+					model.CallbackRequestLogging,
+					model.CallbackInputBlocklist,
+					model.CallbackOutputLengthLimit,
+				}
+			},
+		},
+		{
+			name: "thinking budget only",
+			edit: func(agent *model.Agent) {
+				budget := -1
+				agent.GenerationConfig = &model.GenerationConfig{ThinkingBudget: &budget}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orch := model.NewOrchestrator("coordinator", model.PatternSequential, "", "gemini-2.5-flash")
+			researcher := model.NewAgent("researcher", model.AgentTypeLLM, "Research the topic", "research_data", "gemini-2.5-flash")
+			tt.edit(researcher)
+			orch.AddSubAgent(researcher)
+			project := model.NewProject("demo", orch)
+
+			files, err := generator.NewGenerator().RenderProject(project)
+			if err != nil {
+				t.Fatalf("RenderProject() error = %v", err)
+			}
+			dir := filepath.Join(t.TempDir(), "demo")
+			if err := generator.WriteFiles(dir, files); err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := Import(dir)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+			if got := result.Project.Orchestrator.SubAgents[0]; !reflect.DeepEqual(got, researcher) {
+				t.Errorf("SubAgents[0] = %+v, want %+v", *got, *researcher)
+			}
+			for _, w := range result.Warnings {
+				t.Errorf("unexpected warning: %s", w)
+			}
+		})
+	}
+}
+
+func TestImport_HandWrittenProject(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pipeline/__init__.py": "from . import agent\n",
+		"pipeline/agent.py": `from google.adk.agents import Agent, LlmAgent, SequentialAgent
+from google.adk.tools import google_search
+
+from critic import agent as critic_module
+from .custom import Reviewer
+from .tools import lookup
+
+PROMPT = "unused"
+
+drafter = LlmAgent(
+    name="drafter",
+    model="gemini-2.5-flash",
+    instruction=(
+        "Draft an answer. "
+        'Cite "sources".'
+    ),
+    tools=[google_search, lookup, AgentTool(agent=x)],
+    output_key="draft",
+)
+
+polisher = Agent(
+    name="polisher",
+    model=MODEL,
+    instruction=f"Polish {PROMPT}",
+)
+
+inner = SequentialAgent(name="inner", sub_agents=[drafter])
+
+root_agent = SequentialAgent(
+    name="pipeline",
+    sub_agents=[drafter, polisher, critic_module.agent, Reviewer(name="reviewer"), inner, missing],
+    output_schema=Result,
+)
+`,
+		"critic/__init__.py": "from . import agent\n",
+		"critic/agent.py": `from google.adk.agents import LlmAgent
+from .schemas import Verdict
+
+agent = LlmAgent(name="critic", model="gemini-2.5-pro", instruction="""Critique
+the draft.""", description="Finds problems", disallow_transfer_to_peers=True, output_schema=Verdict,
+    generate_content_config=types.GenerateContentConfig(temperature=0.2, response_mime_type="application/json"), before_model_callback=[audit])
+`,
+		"critic/schemas.py": `from pydantic import BaseModel
+
+
+class Verdict(BaseModel):
+    scores: dict[str, int]
+`,
+		"orphan/agent.py": "from google.adk.agents import LlmAgent\n\nagent = LlmAgent(name=\"orphan\", instruction=\"x\")\n",
+	})
+
+	result, err := Import(dir)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	orch := result.Project.Orchestrator
+	if orch.Name != "pipeline" || orch.Pattern != model.PatternSequential {
+		t.Errorf("Orchestrator = %s (%s), want pipeline (sequential)", orch.Name, orch.Pattern)
+	}
+
+	temperature := 0.2
+	want := []model.Agent{
+		{Name: "drafter", Type: model.AgentTypeLLM, Instruction: `Draft an answer. Cite "sources".`, OutputKey: "draft", Model: "gemini-2.5-flash", Tools: []string{"lookup"}},
+		{Name: "polisher", Type: model.AgentTypeLLM, Instruction: "Polish {PROMPT}", Model: "gemini-2.5-flash"},
+		{Name: "critic", Type: model.AgentTypeLLM, Description: "Finds problems", Instruction: "Critique\nthe draft.", Model: "gemini-2.5-pro", GenerationConfig: &model.GenerationConfig{Temperature: &temperature}},
+		{Name: "reviewer", Type: model.AgentTypeCustom, Model: "gemini-2.5-flash"},
+	}
+	if len(orch.SubAgents) != len(want) {
+		t.Fatalf("SubAgents = %d, want %d", len(orch.SubAgents), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(*orch.SubAgents[i], want[i]) {
+			t.Errorf("SubAgents[%d] = %+v, want %+v", i, *orch.SubAgents[i], want[i])
+		}
+	}
+
+	var warnings []string
+	for _, w := range result.Warnings {
+		warnings = append(warnings, w.String())
+	}
+	all := strings.Join(warnings, "\n")
+
+	expectedWarnings := []string{
+		"pipeline/agent.py:17: built-in tool google_search is not represented in the spec",
+		"pipeline/agent.py:17: tool AgentTool(...) is not a plain function",
+		`pipeline/agent.py:23: model=MODEL is not a string literal; using "gemini-2.5-flash"`,
+		"pipeline/agent.py:24: instruction: f-string literals cannot be evaluated statically",
+		"critic/agent.py:4: disallow_transfer_to_peers of critic only applies under an llm-coordinated orchestrator",
+		"critic/agent.py:5: output_schema=Verdict cannot be represented in the spec: class Verdict field scores: annotation dict [ str , int ]: dict[...] is not supported; dropped",
+		"reviewer is a custom Reviewer",
+		"nested SequentialAgent inner is not supported; skipped",
+		"cannot resolve sub-agent missing; skipped",
+		`critic/agent.py:6: generate_content_config: response_mime_type="application/json" is not represented in the spec and will be dropped`,
+		"critic/agent.py:6: before_model_callback=[...] is not a function and will be dropped",
+		"pipeline/agent.py:32: output_schema of the orchestrator is not represented in the spec and will be dropped",
+		"orphan/agent.py: package is not reachable from the root agent; skipped",
+		"sub-agent polisher: placeholder {PROMPT} is not the outputKey of an agent that runs earlier",
+	}
+	for _, expected := range expectedWarnings {
+		if !strings.Contains(all, expected) {
+			t.Errorf("Import() warnings missing %q; got:\n%s", expected, all)
+		}
+	}
+}
+
+func TestImport_CoordinatorInstruction(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"router/__init__.py": "from . import agent\n",
+		"router/agent.py": `from google.adk.agents import LlmAgent
+
+billing = LlmAgent(name="billing", model="gemini-2.5-flash", instruction="Answer billing questions", description="Invoices and refunds")
+support = LlmAgent(name="support", model="gemini-2.5-flash", instruction="Help", disallow_transfer_to_parent=True)
+
+root_agent = LlmAgent(
+    name="router",
+    model="gemini-2.5-flash",
+    instruction="Send money questions to billing, everything else to support.",
+    sub_agents=[billing, support],
+)
+`,
+	})
+
+	result, err := Import(dir)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	agents := result.Project.Orchestrator.SubAgents
+	if agents[0].Description != "Invoices and refunds" {
+		t.Errorf("Description = %q, want %q", agents[0].Description, "Invoices and refunds")
+	}
+	if !agents[1].DisallowTransferToParent {
+		t.Error("DisallowTransferToParent = false, want true")
+	}
+
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0].String(), "router/agent.py:9: instruction of the orchestrator is generated") {
+		t.Errorf("Import() warnings = %v, want one about the replaced instruction", result.Warnings)
+	}
+}
+
+func TestImport_PromptFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"prompts/billing_v2.md": "Answer billing questions.\nQuote the invoice number.\n",
+		"router/__init__.py":    "from . import agent\n",
+		"router/agent.py": `from pathlib import Path
+
+from google.adk.agents import LlmAgent, SequentialAgent
+
+
+def load_prompt(filename: str) -> str:
+    return (Path(__file__).resolve().parent.parent / "prompts" / filename).read_text(encoding="utf-8").strip()
+
+
+billing = LlmAgent(name="billing", model="gemini-2.5-flash", instruction=load_prompt("billing_v2.md"))
+support = LlmAgent(name="support", model="gemini-2.5-flash", instruction=load_prompt("missing.md"))
+
+root_agent = SequentialAgent(name="router", sub_agents=[billing, support])
+`,
+	})
+
+	result, err := Import(dir)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	billing := result.Project.Orchestrator.SubAgents[0]
+	if !billing.PromptFile || billing.Instruction != "Answer billing questions.\nQuote the invoice number." {
+		t.Errorf("billing = PromptFile %v, Instruction %q; want the prompt file contents", billing.PromptFile, billing.Instruction)
+	}
+	if support := result.Project.Orchestrator.SubAgents[1]; support.PromptFile {
+		t.Error("support PromptFile = true, want false for a missing file")
+	}
+
+	var paths []string
+	for _, file := range result.Files {
+		paths = append(paths, filepath.ToSlash(file.Path))
+	}
+	if !strings.Contains(strings.Join(paths, " "), "prompts/billing_v2.md") {
+		t.Errorf("Files = %v, want the prompt file", paths)
+	}
+
+	var warnings []string
+	for _, w := range result.Warnings {
+		warnings = append(warnings, w.String())
+	}
+	all := strings.Join(warnings, "\n")
+	for _, expected := range []string{
+		"router/agent.py:10: prompt file prompts/billing_v2.md will be generated as prompts/billing.md",
+		"router/agent.py:11: failed to read prompt file prompts/missing.md",
+	} {
+		if !strings.Contains(all, expected) {
+			t.Errorf("Import() warnings missing %q; got:\n%s", expected, all)
+		}
+	}
+}
+
+func TestImport_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		wantErr string
+	}{
+		{
+			name:    "no agent packages",
+			files:   map[string]string{"main.py": "print()\n"},
+			wantErr: "no <package>/agent.py found",
+		},
+		{
+			name:    "no root agent",
+			files:   map[string]string{"a/agent.py": "agent = LlmAgent(name=\"a\")\n"},
+			wantErr: "no root_agent defined",
+		},
+		{
+			name:    "root agent without sub-agents",
+			files:   map[string]string{"a/agent.py": "root_agent = LlmAgent(name=\"a\", instruction=\"x\")\n"},
+			wantErr: "root agent root_agent has no sub_agents",
+		},
+		{
+			name:    "syntax error",
+			files:   map[string]string{"a/agent.py": "root_agent = LlmAgent(\n"},
+			wantErr: "failed to parse a/agent.py",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+
+			_, err := Import(dir)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Import() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestADKVersion(t *testing.T) {
+	tests := []struct {
+		name         string
+		dependencies string
+		want         string
+		wantOK       bool
+	}{
+		{name: "requirements range", dependencies: "google-adk>=1.2.0,<2.0.0\n", want: "1.0", wantOK: true},
+		{name: "static instruction range", dependencies: "google-adk>=1.15.0,<2.0.0\n", want: "1.15", wantOK: true},
+		{name: "pre-1.0 pin", dependencies: "google-adk[eval]==0.5.0\n", want: "0.5", wantOK: true},
+		{name: "poetry table", dependencies: `google-adk = { version = ">=1.0.0,<2.0.0", extras = ["eval"] }`, want: "1.0", wantOK: true},
+		{name: "not pinned", dependencies: "requests\n", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := adkVersion(tt.dependencies)
+			if ok != tt.wantOK {
+				t.Fatalf("adkVersion() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got.Name != tt.want {
+				t.Errorf("adkVersion() = %v, want %v", got.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectName(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{dir: "/work/research-assistant", want: "research-assistant"},
+		{dir: "/work/my.agents v2", want: "my-agents-v2"},
+		{dir: "/work/...", want: "project"},
+	}
+
+	for _, tt := range tests {
+		if got := projectName(tt.dir); got != tt.want {
+			t.Errorf("projectName(%q) = %v, want %v", tt.dir, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package importer
+
+import (
+	"strings"
+
+	"github.com/doji-co/agent-builder/internal/pysyntax"
+)
+
+type valueKind int
+
+const (
+	valueOther valueKind = iota
+	valueString
+	valueName
+	valueNumber
+	valueList
+	valueCall
+)
+
+// value is a Python expression reduced to what an agent definition needs:
+// literals, names, lists and calls. Anything else is kept as source text.
+type value struct {
+	kind  valueKind
+	text  string
+	err   error
+	items []value
+	call  *call
+	line  int
+}
+
+type call struct {
+	fn     string
+	args   []value
+	kwargs []kwarg
+}
+
+type kwarg struct {
+	name  string
+	value value
+}
+
+func (c *call) kwarg(name string) (value, bool) {
+	for _, kw := range c.kwargs {
+		if kw.name == name {
+			return kw.value, true
+		}
+	}
+	return value{}, false
+}
+
+type importRef struct {
+	module string
+	name   string
+}
+
+type assignment struct {
+	target string
+	value  value
+	line   int
+}
+
+type module struct {
+	imports map[string]importRef
+	assigns []assignment
+}
+
+func (m *module) lookup(name string) (value, bool) {
+	// The last assignment wins, as it would when the module runs.
+	for i := len(m.assigns) - 1; i >= 0; i-- {
+		if m.assigns[i].target == name {
+			return m.assigns[i].value, true
+		}
+	}
+	return value{}, false
+}
+
+// parseModule collects the imports and simple "name = expression" assignments
+// of a Python module without executing it.
+func parseModule(src string) (*module, error) {
+	lines, err := logicalLines(src)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &module{imports: make(map[string]importRef)}
+	for _, line := range lines {
+		m.parseLine(line)
+	}
+	return m, nil
+}
+
+// logicalLines tokenizes src and splits it into its non-empty logical lines.
+func logicalLines(src string) ([][]pysyntax.Token, error) {
+	tokens, err := pysyntax.Tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var lines [][]pysyntax.Token
+	var line []pysyntax.Token
+	for _, tok := range tokens {
+		if tok.Kind != pysyntax.TokenNewline && tok.Kind != pysyntax.TokenEOF {
+			line = append(line, tok)
+			continue
+		}
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+		line = nil
+	}
+	return lines, nil
+}
+
+func (m *module) parseLine(line []pysyntax.Token) {
+	first := line[0]
+	if first.Kind != pysyntax.TokenName {
+		return
+	}
+
+	switch {
+	case first.Text == "from":
+		m.parseFrom(line[1:])
+	case first.Text == "import":
+		for _, part := range splitTokens(line[1:], ",") {
+			module, alias := dotted(part), aliasOf(part)
+			if module == "" {
+				continue
+			}
+			if alias == "" {
+				alias = strings.SplitN(module, ".", 2)[0]
+				module = alias
+			}
+			m.imports[alias] = importRef{module: module}
+		}
+	case len(line) > 2 && line[1].Text == "=":
+		p := &parser{tokens: line[2:]}
+		v := p.parseExpr()
+		if !p.done() {
+			v = value{kind: valueOther, text: joinTokens(line[2:]), line: line[2].Line}
+		}
+		m.assigns = append(m.assigns, assignment{target: first.Text, value: v, line: first.Line})
+	}
+}
+
+func (m *module) parseFrom(tokens []pysyntax.Token) {
+	var i int
+	for i < len(tokens) && tokens[i].Text != "import" {
+		i++
+	}
+	if i == len(tokens) {
+		return
+	}
+	from := joinTokens(tokens[:i])
+	from = strings.ReplaceAll(from, " ", "")
+
+	names := tokens[i+1:]
+	if len(names) > 0 && names[0].Text == "(" {
+		names = names[1 : len(names)-1]
+	}
+	for _, part := range splitTokens(names, ",") {
+		name, alias := dotted(part), aliasOf(part)
+		if name == "" {
+			continue
+		}
+		if alias == "" {
+			alias = name
+		}
+		m.imports[alias] = importRef{module: from, name: name}
+	}
+}
+
+func dotted(tokens []pysyntax.Token) string {
+	var b strings.Builder
+	for _, tok := range tokens {
+		if tok.Text == "as" {
+			break
+		}
+		b.WriteString(tok.Text)
+	}
+	return b.String()
+}
+
+func aliasOf(tokens []pysyntax.Token) string {
+	for i, tok := range tokens {
+		if tok.Text == "as" && i+1 < len(tokens) {
+			return tokens[i+1].Text
+		}
+	}
+	return ""
+}
+
+func splitTokens(tokens []pysyntax.Token, sep string) [][]pysyntax.Token {
+	var parts [][]pysyntax.Token
+	start := 0
+	for i, tok := range tokens {
+		if tok.Text == sep {
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, tokens[start:])
+}
+
+func joinTokens(tokens []pysyntax.Token) string {
+	texts := make([]string, len(tokens))
+	for i, tok := range tokens {
+		texts[i] = tok.Text
+	}
+	return strings.Join(texts, " ")
+}
+
+type parser struct {
+	tokens []pysyntax.Token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() pysyntax.Token {
+	if p.done() {
+		return pysyntax.Token{Kind: pysyntax.TokenEOF}
+	}
+	return p.tokens[p.pos]
+}
+
+// atBoundary reports whether the parser is at the end of an expression
+// inside a list, call or assignment.
+func (p *parser) atBoundary() bool {
+	switch p.peek().Text {
+	case ",", ")", "]", "}":
+		return true
+	}
+	return p.done()
+}
+
+func (p *parser) parseExpr() value {
+	start := p.pos
+	v := p.parsePrimary()
+	if p.atBoundary() {
+		return v
+	}
+	// Operators, attribute access on calls, subscripts and the like: keep the
+	// source text of the whole expression.
+	p.skipExpr()
+	return value{kind: valueOther, text: joinTokens(p.tokens[start:p.pos]), line: v.line}
+}
+
+func (p *parser) parsePrimary() value {
+	tok := p.peek()
+	switch {
+	case tok.Kind == pysyntax.TokenString:
+		var b strings.Builder
+		var err error
+		for p.peek().Kind == pysyntax.TokenString {
+			s, unquoteErr := pysyntax.Unquote(p.peek().Text)
+			if unquoteErr != nil {
+				if err == nil {
+					err = unquoteErr
+				}
+				s = literalBody(p.peek().Text)
+			}
+			b.WriteString(s)
+			p.pos++
+		}
+		return value{kind: valueString, text: b.String(), err: err, line: tok.Line}
+	case tok.Kind == pysyntax.TokenNumber:
+		p.pos++
+		return value{kind: valueNumber, text: tok.Text, line: tok.Line}
+	case tok.Text == "-" && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].Kind == pysyntax.TokenNumber:
+		p.pos += 2
+		return value{kind: valueNumber, text: "-" + p.tokens[p.pos-1].Text, line: tok.Line}
+	case tok.Kind == pysyntax.TokenName:
+		name := tok.Text
+		p.pos++
+		for p.peek().Text == "." && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].Kind == pysyntax.TokenName {
+			name += "." + p.tokens[p.pos+1].Text
+			p.pos += 2
+		}
+		if p.peek().Text == "(" {
+			return value{kind: valueCall, text: name, call: p.parseCall(name), line: tok.Line}
+		}
+		return value{kind: valueName, text: name, line: tok.Line}
+	case tok.Text == "[" || tok.Text == "(":
+		closer := map[string]string{"[": "]", "(": ")"}[tok.Text]
+		p.pos++
+		var items []value
+		tuple := tok.Text == "["
+		for !p.done() && p.peek().Text != closer {
+			items = append(items, p.parseExpr())
+			if p.peek().Text == "," {
+				tuple = true
+				p.pos++
+			}
+		}
+		p.pos++
+		if !tuple && len(items) == 1 {
+			return items[0]
+		}
+		return value{kind: valueList, items: items, line: tok.Line}
+	default:
+		start := p.pos
+		p.skipExpr()
+		return value{kind: valueOther, text: joinTokens(p.tokens[start:p.pos]), line: tok.Line}
+	}
+}
+
+// literalBody strips the prefix and quotes from a string literal without
+// interpreting it.
+func literalBody(lit string) string {
+	body := lit[strings.IndexAny(lit, `'"`):]
+	quote := body[:1]
+	if len(body) >= 6 && strings.HasPrefix(body, strings.Repeat(quote, 3)) {
+		quote = strings.Repeat(quote, 3)
+	}
+	return body[len(quote) : len(body)-len(quote)]
+}
+
+func (p *parser) parseCall(fn string) *call {
+	c := &call{fn: fn}
+	p.pos++ // (
+	for !p.done() && p.peek().Text != ")" {
+		tok := p.peek()
+		if tok.Kind == pysyntax.TokenName && p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].Text == "=" {
+			p.pos += 2
+			c.kwargs = append(c.kwargs, kwarg{name: tok.Text, value: p.parseExpr()})
+		} else {
+			c.args = append(c.args, p.parseExpr())
+		}
+		if p.peek().Text == "," {
+			p.pos++
+		}
+	}
+	p.pos++ // )
+	return c
+}
+
+// skipExpr advances past the rest of an expression, stopping at a comma or
+// closing bracket that is not nested inside it.
+func (p *parser) skipExpr() {
+	depth := 0
+	for !p.done() {
+		switch p.peek().Text {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			if depth == 0 {
+				return
+			}
+			depth--
+		case ",":
+			if depth == 0 {
+				return
+			}
+		}
+		p.pos++
+	}
+}
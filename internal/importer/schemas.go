@@ -0,0 +1,312 @@
+package importer
+
+import (
+	"fmt"
+
+	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/naming"
+	"github.com/doji-co/agent-builder/internal/pysyntax"
+)
+
+// pyClass is a class definition read from a schemas module: its docstring and
+// its annotated fields. Methods and other statements in its body are ignored.
+type pyClass struct {
+	name        string
+	description string
+	fields      []pyClassField
+}
+
+type pyClassField struct {
+	name       string
+	annotation []pysyntax.Token
+	// value is the default after "=", with kind valueOther and no text when
+	// the field has none.
+	value value
+}
+
+// pyType is a field annotation: a builtin type, list[...], Literal[...] or
+// the name of another class.
+type pyType struct {
+	name string
+	enum []string
+	item *pyType
+}
+
+var pythonFieldTypes = map[string]model.FieldType{
+	"str":   model.FieldTypeString,
+	"int":   model.FieldTypeInteger,
+	"float": model.FieldTypeNumber,
+	"bool":  model.FieldTypeBoolean,
+}
+
+// parseClasses collects the classes of a module. The tokenizer drops
+// indentation, so an annotated line belongs to the class above it; this holds
+// for the Pydantic models agent-builder generates and for most hand-written
+// ones.
+func parseClasses(src string) (map[string]*pyClass, error) {
+	lines, err := logicalLines(src)
+	if err != nil {
+		return nil, err
+	}
+
+	classes := make(map[string]*pyClass)
+	var current *pyClass
+	for _, line := range lines {
+		switch {
+		case line[0].Text == "class" && len(line) > 1:
+			current = &pyClass{name: line[1].Text}
+			classes[current.name] = current
+		case current == nil:
+		case len(line) == 1 && line[0].Kind == pysyntax.TokenString:
+			if current.description == "" && len(current.fields) == 0 {
+				current.description, _ = pysyntax.Unquote(line[0].Text)
+			}
+		case line[0].Kind == pysyntax.TokenName && len(line) > 2 && line[1].Text == ":":
+			field := pyClassField{name: line[0].Text, annotation: line[2:]}
+			for i, tok := range line {
+				if tok.Text == "=" {
+					field.annotation = line[2:i]
+					p := &parser{tokens: line[i+1:]}
+					field.value = p.parseExpr()
+					break
+				}
+			}
+			current.fields = append(current.fields, field)
+		}
+	}
+	return classes, nil
+}
+
+// parseAnnotation reads a field annotation and whether it allows None.
+func parseAnnotation(tokens []pysyntax.Token) (pyType, bool, error) {
+	optional := false
+	if n := len(tokens); n > 2 && tokens[n-2].Text == "|" && tokens[n-1].Text == "None" {
+		tokens, optional = tokens[:n-2], true
+	}
+
+	p := &parser{tokens: tokens}
+	t, err := p.parseType()
+	if err == nil && !p.done() {
+		err = fmt.Errorf("unexpected %q", p.peek().Text)
+	}
+	if err != nil {
+		return pyType{}, false, fmt.Errorf("annotation %s: %w", joinTokens(tokens), err)
+	}
+	if t.name == "Optional" {
+		return *t.item, true, nil
+	}
+	return t, optional, nil
+}
+
+func (p *parser) parseType() (pyType, error) {
+	tok := p.peek()
+	if tok.Kind != pysyntax.TokenName {
+		return pyType{}, fmt.Errorf("expected a type, found %q", tok.Text)
+	}
+	p.pos++
+	t := pyType{name: tok.Text}
+	if p.peek().Text != "[" {
+		return t, nil
+	}
+	p.pos++
+
+	switch t.name {
+	case "Literal":
+		for p.peek().Kind == pysyntax.TokenString {
+			s, err := pysyntax.Unquote(p.peek().Text)
+			if err != nil {
+				return pyType{}, err
+			}
+			t.enum = append(t.enum, s)
+			p.pos++
+			if p.peek().Text == "," {
+				p.pos++
+			}
+		}
+		if len(t.enum) == 0 {
+			return pyType{}, fmt.Errorf("only Literal of strings is supported")
+		}
+	case "list", "List", "Optional":
+		item, err := p.parseType()
+		if err != nil {
+			return pyType{}, err
+		}
+		if t.name == "List" {
+			t.name = "list"
+		}
+		t.item = &item
+	default:
+		return pyType{}, fmt.Errorf("%s[...] is not supported", t.name)
+	}
+
+	if p.peek().Text != "]" {
+		return pyType{}, fmt.Errorf("expected ], found %q", p.peek().Text)
+	}
+	p.pos++
+	return t, nil
+}
+
+// schemaReader converts the Pydantic models of a schemas module back to the
+// spec's schemas: flat models to fields, nested ones to a JSON Schema.
+type schemaReader struct {
+	classes  map[string]*pyClass
+	visiting map[string]bool
+}
+
+// schema converts the model named className. defaultName is the class name
+// the generator would pick for the schema, which the spec then leaves out.
+func (r *schemaReader) schema(className, defaultName string) (*model.Schema, error) {
+	class, ok := r.classes[className]
+	if !ok {
+		return nil, fmt.Errorf("class %s not found", className)
+	}
+
+	s := &model.Schema{}
+	if className != defaultName {
+		s.Name = className
+	}
+	if fields, ok, err := r.flatFields(class); err != nil {
+		return nil, err
+	} else if ok {
+		s.Fields = fields
+		return s, nil
+	}
+
+	var err error
+	s.JSONSchema, err = r.object(class, "")
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// flatFields returns the fields of a class that needs no nested models, or
+// false when it does.
+func (r *schemaReader) flatFields(class *pyClass) ([]model.Field, bool, error) {
+	if class.description != "" {
+		return nil, false, nil
+	}
+
+	var fields []model.Field
+	for _, f := range class.fields {
+		t, optional, description, err := fieldInfo(f)
+		if err != nil {
+			return nil, false, fmt.Errorf("class %s field %s: %w", class.name, f.name, err)
+		}
+		field := model.Field{Name: f.name, Description: description, Optional: optional}
+		if t.name == "list" {
+			t, field.List = *t.item, true
+		}
+		switch fieldType, ok := pythonFieldTypes[t.name]; {
+		case ok:
+			field.Type = fieldType
+		case t.name == "Literal":
+			field.Type, field.Enum = model.FieldTypeString, t.enum
+		default:
+			return nil, false, nil
+		}
+		fields = append(fields, field)
+	}
+	return fields, true, nil
+}
+
+// object converts a class to an object schema. className is the name the
+// generator derives for a nested class, which a title overrides; it is empty
+// for the top-level class.
+func (r *schemaReader) object(class *pyClass, className string) (map[string]interface{}, error) {
+	if r.visiting[class.name] {
+		return nil, fmt.Errorf("class %s refers to itself", class.name)
+	}
+	r.visiting[class.name] = true
+	defer delete(r.visiting, class.name)
+
+	if className == "" {
+		className = class.name
+	}
+	properties := make(map[string]interface{})
+	var required []interface{}
+	for _, f := range class.fields {
+		t, optional, description, err := fieldInfo(f)
+		if err != nil {
+			return nil, fmt.Errorf("class %s field %s: %w", class.name, f.name, err)
+		}
+		node, err := r.jsonType(t, className+naming.PascalCase(f.name))
+		if err != nil {
+			return nil, fmt.Errorf("class %s field %s: %w", class.name, f.name, err)
+		}
+		if description != "" {
+			node["description"] = description
+		}
+		properties[f.name] = node
+		if !optional {
+			required = append(required, f.name)
+		}
+	}
+
+	node := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		node["required"] = required
+	}
+	if class.description != "" {
+		node["description"] = class.description
+	}
+	if className != class.name {
+		node["title"] = class.name
+	}
+	return node, nil
+}
+
+func (r *schemaReader) jsonType(t pyType, className string) (map[string]interface{}, error) {
+	switch t.name {
+	case "Literal":
+		enum := make([]interface{}, len(t.enum))
+		for i, v := range t.enum {
+			enum[i] = v
+		}
+		return map[string]interface{}{"type": "string", "enum": enum}, nil
+	case "list":
+		items, err := r.jsonType(*t.item, className+"Item")
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+	}
+	if fieldType, ok := pythonFieldTypes[t.name]; ok {
+		return map[string]interface{}{"type": string(fieldType)}, nil
+	}
+	class, ok := r.classes[t.name]
+	if !ok {
+		return nil, fmt.Errorf("type %s is not a builtin or a class of the module", t.name)
+	}
+	return r.object(class, className)
+}
+
+// fieldInfo reads a field's type, whether it is optional and its description
+// from Field(description=...).
+func fieldInfo(f pyClassField) (pyType, bool, string, error) {
+	t, optional, err := parseAnnotation(f.annotation)
+	if err != nil {
+		return pyType{}, false, "", err
+	}
+
+	description := ""
+	switch v := f.value; {
+	case v.kind == valueOther && v.text == "", v.kind == valueName && v.text == "None":
+	case v.kind == valueCall && className(v.call.fn) == "Field":
+		if len(v.call.args) > 0 {
+			return pyType{}, false, "", fmt.Errorf("positional arguments to Field are not supported")
+		}
+		for _, kw := range v.call.kwargs {
+			switch {
+			case kw.name == "description" && kw.value.kind == valueString:
+				description = kw.value.text
+			case kw.name == "default" && kw.value.kind == valueName && kw.value.text == "None":
+			default:
+				return pyType{}, false, "", fmt.Errorf("Field(%s=%s) is not supported", kw.name, describe(kw.value))
+			}
+		}
+	default:
+		return pyType{}, false, "", fmt.Errorf("default %s is not supported", describe(v))
+	}
+	return t, optional, description, nil
+}
@@ -1,6 +1,9 @@
 package model
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 type AgentType string
 
@@ -9,12 +12,56 @@ const (
 	AgentTypeCustom AgentType = "custom"
 )
 
+var AgentTypes = []AgentType{AgentTypeLLM, AgentTypeCustom}
+
+// DefaultModel is the model new agents get.
+const DefaultModel = "gemini-2.5-flash"
+
+// AvailableModels are the models the wizard offers. Specs may name any other
+// model ADK accepts.
+var AvailableModels = []string{
+	"gemini-2.5-flash",
+	"gemini-2.5-pro",
+	"gemini-2.5-flash-lite",
+}
+
 type Agent struct {
-	Name        string
-	Type        AgentType
-	Instruction string
-	OutputKey   string
-	Model       string
+	Name        string    `yaml:"name"`
+	Type        AgentType `yaml:"type"`
+	Description string    `yaml:"description,omitempty"`
+	Instruction string    `yaml:"instruction,omitempty"`
+	OutputKey   string    `yaml:"outputKey,omitempty"`
+	Model       string    `yaml:"model,omitempty"`
+	Examples    []Example `yaml:"examples,omitempty"`
+
+	// PromptFile writes the instruction to prompts/<agent>.md, which the
+	// generated agent reads at import time, instead of inlining it.
+	PromptFile bool `yaml:"promptFile,omitempty"`
+	// StaticInstruction is sent verbatim, without placeholders, ahead of
+	// the instruction so that it can be cached across requests.
+	StaticInstruction string `yaml:"staticInstruction,omitempty"`
+
+	InputSchema  *Schema  `yaml:"inputSchema,omitempty"`
+	OutputSchema *Schema  `yaml:"outputSchema,omitempty"`
+	Tools        []string `yaml:"tools,omitempty"`
+	// Memory gives the agent ADK's load_memory tool to search past sessions.
+	Memory bool `yaml:"memory,omitempty"`
+
+	GenerationConfig *GenerationConfig `yaml:"generationConfig,omitempty"`
+	Callbacks        []Callback        `yaml:"callbacks,omitempty"`
+
+	// DisallowTransferToParent and DisallowTransferToPeers stop an agent
+	// under an llm-coordinated orchestrator from handing the conversation
+	// back to the coordinator or to its sibling agents.
+	DisallowTransferToParent bool `yaml:"disallowTransferToParent,omitempty"`
+	DisallowTransferToPeers  bool `yaml:"disallowTransferToPeers,omitempty"`
+}
+
+// Example is a prompt and the answer expected for it, used to seed the
+// project's evaluation set.
+type Example struct {
+	Prompt   string `yaml:"prompt"`
+	Expected string `yaml:"expected"`
 }
 
 func NewAgent(name string, agentType AgentType, instruction, outputKey, model string) *Agent {
@@ -35,6 +82,61 @@ func (a *Agent) Validate() error {
 	if a.Type == AgentTypeLLM && a.Instruction == "" {
 		return errors.New("instruction is required for LLM agents")
 	}
+	if a.Type == AgentTypeLLM && a.Model == "" {
+		return errors.New("model is required for LLM agents")
+	}
+	if a.OutputKey != "" {
+		if err := ValidateStateKey(a.OutputKey); err != nil {
+			return fmt.Errorf("output key %w", err)
+		}
+	}
+
+	if a.Type != AgentTypeLLM && (a.DisallowTransferToParent || a.DisallowTransferToPeers) {
+		return errors.New("transfer controls only apply to LLM agents")
+	}
+	if a.Type != AgentTypeLLM && a.Memory {
+		return errors.New("memory only applies to LLM agents")
+	}
+	if a.Type != AgentTypeLLM && (a.PromptFile || a.StaticInstruction != "") {
+		return errors.New("prompt files and static instructions only apply to LLM agents")
+	}
+
+	if a.InputSchema != nil {
+		if err := a.InputSchema.Validate(); err != nil {
+			return fmt.Errorf("input schema: %w", err)
+		}
+	}
+	if a.OutputSchema != nil {
+		if err := a.OutputSchema.Validate(); err != nil {
+			return fmt.Errorf("output schema: %w", err)
+		}
+		// ADK only lets an agent with an output schema reply; it cannot
+		// call tools.
+		if len(a.Tools) > 0 {
+			return errors.New("agents with an output schema cannot use tools; remove the tools or the output schema")
+		}
+		if a.Memory {
+			return errors.New("agents with an output schema cannot use memory, which is a tool; remove memory or the output schema")
+		}
+	}
+
+	if a.GenerationConfig != nil {
+		if err := a.GenerationConfig.Validate(); err != nil {
+			return fmt.Errorf("generation config: %w", err)
+		}
+	}
+
+	seen := make(map[string]bool, len(a.Tools))
+	for _, tool := range a.Tools {
+		if seen[tool] {
+			return fmt.Errorf("tool %q is listed twice", tool)
+		}
+		seen[tool] = true
+	}
+
+	if err := a.validateCallbacks(); err != nil {
+		return err
+	}
 
 	return nil
 }
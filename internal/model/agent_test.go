@@ -111,6 +111,39 @@ func TestAgent_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "instruction is required for LLM agents",
 		},
+		{
+			name: "llm agent without a model returns error",
+			agent: &Agent{
+				Name:        "Researcher",
+				Type:        AgentTypeLLM,
+				Instruction: "Research",
+			},
+			wantErr: true,
+			errMsg:  "model is required for LLM agents",
+		},
+		{
+			name: "output key that is not a state name returns error",
+			agent: &Agent{
+				Name:        "Researcher",
+				Type:        AgentTypeLLM,
+				Instruction: "Research",
+				OutputKey:   `notes", x="`,
+				Model:       "gemini-2.0-flash",
+			},
+			wantErr: true,
+			errMsg:  `output key "notes\", x=\"" must be letters, digits and underscores, not starting with a digit, with an optional app:, user: or temp: prefix`,
+		},
+		{
+			name: "prefixed output key",
+			agent: &Agent{
+				Name:        "Researcher",
+				Type:        AgentTypeLLM,
+				Instruction: "Research",
+				OutputKey:   "user:notes",
+				Model:       "gemini-2.0-flash",
+			},
+			wantErr: false,
+		},
 		{
 			name: "custom agent can have empty instruction",
 			agent: &Agent{
@@ -120,6 +153,88 @@ func TestAgent_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "output schema with tools returns error",
+			agent: &Agent{
+				Name:         "Researcher",
+				Type:         AgentTypeLLM,
+				Instruction:  "Research",
+				Model:        "gemini-2.0-flash",
+				OutputSchema: &Schema{Fields: []Field{{Name: "summary", Type: FieldTypeString}}},
+				Tools:        []string{"search_web"},
+			},
+			wantErr: true,
+			errMsg:  "agents with an output schema cannot use tools; remove the tools or the output schema",
+		},
+		{
+			name: "input schema with tools is allowed",
+			agent: &Agent{
+				Name:        "Researcher",
+				Type:        AgentTypeLLM,
+				Instruction: "Research",
+				Model:       "gemini-2.0-flash",
+				InputSchema: &Schema{Fields: []Field{{Name: "topic", Type: FieldTypeString}}},
+				Tools:       []string{"search_web"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid output schema returns error",
+			agent: &Agent{
+				Name:         "Researcher",
+				Type:         AgentTypeLLM,
+				Instruction:  "Research",
+				Model:        "gemini-2.0-flash",
+				OutputSchema: &Schema{},
+			},
+			wantErr: true,
+			errMsg:  "output schema: fields or jsonSchema is required",
+		},
+		{
+			name: "duplicate tool returns error",
+			agent: &Agent{
+				Name:        "Researcher",
+				Type:        AgentTypeLLM,
+				Instruction: "Research",
+				Model:       "gemini-2.0-flash",
+				Tools:       []string{"search_web", "search_web"},
+			},
+			wantErr: true,
+			errMsg:  `tool "search_web" is listed twice`,
+		},
+		{
+			name: "memory with output schema returns error",
+			agent: &Agent{
+				Name:         "Researcher",
+				Type:         AgentTypeLLM,
+				Instruction:  "Research",
+				Model:        "gemini-2.0-flash",
+				OutputSchema: &Schema{Fields: []Field{{Name: "summary", Type: FieldTypeString}}},
+				Memory:       true,
+			},
+			wantErr: true,
+			errMsg:  "agents with an output schema cannot use memory, which is a tool; remove memory or the output schema",
+		},
+		{
+			name: "custom agent with transfer controls returns error",
+			agent: &Agent{
+				Name:                    "CustomAgent",
+				Type:                    AgentTypeCustom,
+				DisallowTransferToPeers: true,
+			},
+			wantErr: true,
+			errMsg:  "transfer controls only apply to LLM agents",
+		},
+		{
+			name: "custom agent with a prompt file returns error",
+			agent: &Agent{
+				Name:       "CustomAgent",
+				Type:       AgentTypeCustom,
+				PromptFile: true,
+			},
+			wantErr: true,
+			errMsg:  "prompt files and static instructions only apply to LLM agents",
+		},
 	}
 
 	for _, tt := range tests {
@@ -137,3 +252,22 @@ func TestAgent_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestAgentTypes(t *testing.T) {
+	types := AgentTypes
+
+	if len(types) != 2 {
+		t.Errorf("Expected 2 agent types, got %d", len(types))
+	}
+
+	expectedTypes := []AgentType{
+		AgentTypeLLM,
+		AgentTypeCustom,
+	}
+
+	for i, agentType := range expectedTypes {
+		if types[i] != agentType {
+			t.Errorf("AgentType %d = %v, want %v", i, types[i], agentType)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Callback is either an ADK callback hook, which is generated as a typed stub
+// to fill in, or a built-in recipe, which is generated ready to use inside
+// the hook it runs in.
+type Callback string
+
+const (
+	CallbackBeforeAgent Callback = "before-agent"
+	CallbackAfterAgent  Callback = "after-agent"
+	CallbackBeforeModel Callback = "before-model"
+	CallbackAfterModel  Callback = "after-model"
+	CallbackBeforeTool  Callback = "before-tool"
+	CallbackAfterTool   Callback = "after-tool"
+
+	// CallbackRequestLogging logs every model request.
+	CallbackRequestLogging Callback = "request-logging"
+	// CallbackInputBlocklist refuses requests containing blocked terms.
+	CallbackInputBlocklist Callback = "input-blocklist"
+	// CallbackOutputLengthLimit truncates long model replies.
+	CallbackOutputLengthLimit Callback = "output-length-limit"
+	// CallbackStateSnapshot logs the session state after the agent runs.
+	CallbackStateSnapshot Callback = "state-snapshot"
+)
+
+// CallbackHooks lists the hooks in the order ADK runs them.
+var CallbackHooks = []Callback{
+	CallbackBeforeAgent,
+	CallbackAfterAgent,
+	CallbackBeforeModel,
+	CallbackAfterModel,
+	CallbackBeforeTool,
+	CallbackAfterTool,
+}
+
+// CallbackRecipes lists the built-in recipes.
+var CallbackRecipes = []Callback{
+	CallbackRequestLogging,
+	CallbackInputBlocklist,
+	CallbackOutputLengthLimit,
+	CallbackStateSnapshot,
+}
+
+// Hook returns the hook a callback runs in, or "" for an unknown callback.
+func (c Callback) Hook() Callback {
+	switch c {
+	case CallbackRequestLogging, CallbackInputBlocklist:
+		return CallbackBeforeModel
+	case CallbackOutputLengthLimit:
+		return CallbackAfterModel
+	case CallbackStateSnapshot:
+		return CallbackAfterAgent
+	}
+	for _, hook := range CallbackHooks {
+		if c == hook {
+			return c
+		}
+	}
+	return ""
+}
+
+func (c Callback) IsRecipe() bool {
+	return c.Hook() != "" && c.Hook() != c
+}
+
+// HasCallback reports whether the agent opts into the named hook or recipe.
+func (a *Agent) HasCallback(name string) bool {
+	for _, callback := range a.Callbacks {
+		if string(callback) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// UsesCallbackHook reports whether any of the agent's callbacks runs in the
+// named hook.
+func (a *Agent) UsesCallbackHook(hook string) bool {
+	for _, callback := range a.Callbacks {
+		if string(callback.Hook()) == hook {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCallbackRecipes reports whether the agent uses any built-in recipe.
+func (a *Agent) HasCallbackRecipes() bool {
+	for _, callback := range a.Callbacks {
+		if callback.IsRecipe() {
+			return true
+		}
+	}
+	return false
+}
+
+// CallbackHooks returns the hooks the agent's callbacks run in, in the order
+// ADK runs them.
+func (a *Agent) CallbackHooks() []Callback {
+	var hooks []Callback
+	for _, hook := range CallbackHooks {
+		if a.UsesCallbackHook(string(hook)) {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks
+}
+
+func (a *Agent) validateCallbacks() error {
+	seen := make(map[Callback]bool, len(a.Callbacks))
+	for _, callback := range a.Callbacks {
+		if callback.Hook() == "" {
+			return fmt.Errorf("unknown callback %q", callback)
+		}
+		if seen[callback] {
+			return fmt.Errorf("callback %s is listed twice", callback)
+		}
+		seen[callback] = true
+	}
+
+	// load_memory is a tool the callbacks see like any other.
+	if (seen[CallbackBeforeTool] || seen[CallbackAfterTool]) && len(a.Tools) == 0 && !a.Memory {
+		return errors.New("tool callbacks need at least one tool")
+	}
+	if seen[CallbackOutputLengthLimit] && a.OutputSchema != nil {
+		return errors.New("output-length-limit would truncate the JSON an output schema requires; remove one of them")
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCallback_Hook(t *testing.T) {
+	tests := []struct {
+		callback Callback
+		want     Callback
+	}{
+		{CallbackBeforeTool, CallbackBeforeTool},
+		{CallbackRequestLogging, CallbackBeforeModel},
+		{CallbackInputBlocklist, CallbackBeforeModel},
+		{CallbackOutputLengthLimit, CallbackAfterModel},
+		{CallbackStateSnapshot, CallbackAfterAgent},
+		{Callback("on-error"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.callback), func(t *testing.T) {
+			if got := tt.callback.Hook(); got != tt.want {
+				t.Errorf("Hook() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgent_CallbackHooks(t *testing.T) {
+	agent := NewAgent("Writer", AgentTypeLLM, "Write", "draft", "gemini-2.5-flash")
+	agent.Callbacks = []Callback{CallbackOutputLengthLimit, CallbackBeforeAgent, CallbackRequestLogging, CallbackBeforeModel}
+
+	want := []Callback{CallbackBeforeAgent, CallbackBeforeModel, CallbackAfterModel}
+	if got := agent.CallbackHooks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CallbackHooks() = %v, want %v", got, want)
+	}
+	if !agent.HasCallbackRecipes() {
+		t.Error("HasCallbackRecipes() = false, want true")
+	}
+	if agent.UsesCallbackHook("after-agent") {
+		t.Error("UsesCallbackHook(after-agent) = true, want false")
+	}
+}
+
+func TestAgent_ValidateCallbacks(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(agent *Agent)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "hooks and recipes",
+			setup: func(agent *Agent) {
+				agent.Callbacks = []Callback{CallbackBeforeModel, CallbackRequestLogging, CallbackStateSnapshot}
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown callback",
+			setup: func(agent *Agent) {
+				agent.Callbacks = []Callback{"on-error"}
+			},
+			wantErr: true,
+			errMsg:  `unknown callback "on-error"`,
+		},
+		{
+			name: "duplicate callback",
+			setup: func(agent *Agent) {
+				agent.Callbacks = []Callback{CallbackInputBlocklist, CallbackInputBlocklist}
+			},
+			wantErr: true,
+			errMsg:  "callback input-blocklist is listed twice",
+		},
+		{
+			name: "tool callback without tools",
+			setup: func(agent *Agent) {
+				agent.Callbacks = []Callback{CallbackAfterTool}
+			},
+			wantErr: true,
+			errMsg:  "tool callbacks need at least one tool",
+		},
+		{
+			name: "tool callback with tools",
+			setup: func(agent *Agent) {
+				agent.Tools = []string{"search"}
+				agent.Callbacks = []Callback{CallbackBeforeTool, CallbackAfterTool}
+			},
+			wantErr: false,
+		},
+		{
+			name: "tool callback with load_memory",
+			setup: func(agent *Agent) {
+				agent.Memory = true
+				agent.Callbacks = []Callback{CallbackBeforeTool}
+			},
+			wantErr: false,
+		},
+		{
+			name: "length limit with output schema",
+			setup: func(agent *Agent) {
+				agent.OutputSchema = &Schema{Fields: []Field{{Name: "summary", Type: FieldTypeString}}}
+				agent.Callbacks = []Callback{CallbackOutputLengthLimit}
+			},
+			wantErr: true,
+			errMsg:  "output-length-limit would truncate the JSON an output schema requires; remove one of them",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := NewAgent("Writer", AgentTypeLLM, "Write", "draft", "gemini-2.5-flash")
+			tt.setup(agent)
+
+			err := agent.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && err != nil && err.Error() != tt.errMsg {
+				t.Errorf("Validate() error message = %v, want %v", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
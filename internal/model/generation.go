@@ -0,0 +1,203 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxStopSequences is the most stop sequences Gemini accepts.
+const maxStopSequences = 5
+
+type HarmCategory string
+
+const (
+	HarmCategoryHarassment       HarmCategory = "harassment"
+	HarmCategoryHateSpeech       HarmCategory = "hate-speech"
+	HarmCategorySexuallyExplicit HarmCategory = "sexually-explicit"
+	HarmCategoryDangerousContent HarmCategory = "dangerous-content"
+	HarmCategoryCivicIntegrity   HarmCategory = "civic-integrity"
+)
+
+// HarmCategories lists the categories a safety setting can apply to.
+var HarmCategories = []HarmCategory{
+	HarmCategoryHarassment,
+	HarmCategoryHateSpeech,
+	HarmCategorySexuallyExplicit,
+	HarmCategoryDangerousContent,
+	HarmCategoryCivicIntegrity,
+}
+
+// Python returns the google.genai types.HarmCategory member.
+func (c HarmCategory) Python() string {
+	switch c {
+	case HarmCategoryHarassment:
+		return "HARM_CATEGORY_HARASSMENT"
+	case HarmCategoryHateSpeech:
+		return "HARM_CATEGORY_HATE_SPEECH"
+	case HarmCategorySexuallyExplicit:
+		return "HARM_CATEGORY_SEXUALLY_EXPLICIT"
+	case HarmCategoryDangerousContent:
+		return "HARM_CATEGORY_DANGEROUS_CONTENT"
+	case HarmCategoryCivicIntegrity:
+		return "HARM_CATEGORY_CIVIC_INTEGRITY"
+	default:
+		return ""
+	}
+}
+
+type HarmThreshold string
+
+const (
+	HarmThresholdLowAndAbove    HarmThreshold = "low-and-above"
+	HarmThresholdMediumAndAbove HarmThreshold = "medium-and-above"
+	HarmThresholdOnlyHigh       HarmThreshold = "only-high"
+	HarmThresholdNone           HarmThreshold = "none"
+	HarmThresholdOff            HarmThreshold = "off"
+)
+
+// HarmThresholds lists the thresholds a safety setting can block at, from
+// strictest to most permissive.
+var HarmThresholds = []HarmThreshold{
+	HarmThresholdLowAndAbove,
+	HarmThresholdMediumAndAbove,
+	HarmThresholdOnlyHigh,
+	HarmThresholdNone,
+	HarmThresholdOff,
+}
+
+// Python returns the google.genai types.HarmBlockThreshold member.
+func (t HarmThreshold) Python() string {
+	switch t {
+	case HarmThresholdLowAndAbove:
+		return "BLOCK_LOW_AND_ABOVE"
+	case HarmThresholdMediumAndAbove:
+		return "BLOCK_MEDIUM_AND_ABOVE"
+	case HarmThresholdOnlyHigh:
+		return "BLOCK_ONLY_HIGH"
+	case HarmThresholdNone:
+		return "BLOCK_NONE"
+	case HarmThresholdOff:
+		return "OFF"
+	default:
+		return ""
+	}
+}
+
+type SafetySetting struct {
+	Category  HarmCategory  `yaml:"category"`
+	Threshold HarmThreshold `yaml:"threshold"`
+}
+
+// GenerationConfig tunes how the model generates replies. Unset fields keep
+// the model's defaults.
+type GenerationConfig struct {
+	Temperature     *float64        `yaml:"temperature,omitempty"`
+	TopP            *float64        `yaml:"topP,omitempty"`
+	TopK            *int            `yaml:"topK,omitempty"`
+	MaxOutputTokens *int            `yaml:"maxOutputTokens,omitempty"`
+	StopSequences   []string        `yaml:"stopSequences,omitempty"`
+	SafetySettings  []SafetySetting `yaml:"safetySettings,omitempty"`
+	ThinkingBudget  *int            `yaml:"thinkingBudget,omitempty"`
+}
+
+// Merge returns the settings of c with those set in override taking
+// precedence. Safety settings are merged by category. Either may be nil.
+func (c *GenerationConfig) Merge(override *GenerationConfig) *GenerationConfig {
+	if c == nil {
+		return override
+	}
+	if override == nil {
+		return c
+	}
+
+	merged := *c
+	if override.Temperature != nil {
+		merged.Temperature = override.Temperature
+	}
+	if override.TopP != nil {
+		merged.TopP = override.TopP
+	}
+	if override.TopK != nil {
+		merged.TopK = override.TopK
+	}
+	if override.MaxOutputTokens != nil {
+		merged.MaxOutputTokens = override.MaxOutputTokens
+	}
+	if override.StopSequences != nil {
+		merged.StopSequences = override.StopSequences
+	}
+	if override.ThinkingBudget != nil {
+		merged.ThinkingBudget = override.ThinkingBudget
+	}
+
+	if override.SafetySettings != nil {
+		settings := make(map[HarmCategory]SafetySetting, len(override.SafetySettings))
+		for _, setting := range override.SafetySettings {
+			settings[setting.Category] = setting
+		}
+
+		merged.SafetySettings = nil
+		for _, setting := range c.SafetySettings {
+			if replacement, ok := settings[setting.Category]; ok {
+				setting = replacement
+				delete(settings, setting.Category)
+			}
+			merged.SafetySettings = append(merged.SafetySettings, setting)
+		}
+		for _, setting := range override.SafetySettings {
+			if _, ok := settings[setting.Category]; ok {
+				merged.SafetySettings = append(merged.SafetySettings, setting)
+			}
+		}
+	}
+	return &merged
+}
+
+// HasContentSettings reports whether any setting other than the thinking
+// budget is set; those render as generate_content_config, while the thinking
+// budget goes to the agent's planner.
+func (c *GenerationConfig) HasContentSettings() bool {
+	return c != nil && (c.Temperature != nil || c.TopP != nil || c.TopK != nil ||
+		c.MaxOutputTokens != nil || len(c.StopSequences) > 0 || len(c.SafetySettings) > 0)
+}
+
+func (c *GenerationConfig) Validate() error {
+	if c.Temperature != nil && (*c.Temperature < 0 || *c.Temperature > 2) {
+		return errors.New("temperature must be between 0 and 2")
+	}
+	if c.TopP != nil && (*c.TopP < 0 || *c.TopP > 1) {
+		return errors.New("topP must be between 0 and 1")
+	}
+	if c.TopK != nil && *c.TopK < 1 {
+		return errors.New("topK must be at least 1")
+	}
+	if c.MaxOutputTokens != nil && *c.MaxOutputTokens < 1 {
+		return errors.New("maxOutputTokens must be at least 1")
+	}
+	if len(c.StopSequences) > maxStopSequences {
+		return fmt.Errorf("at most %d stop sequences are allowed", maxStopSequences)
+	}
+	for _, stop := range c.StopSequences {
+		if stop == "" {
+			return errors.New("stop sequences cannot be empty")
+		}
+	}
+	if c.ThinkingBudget != nil && *c.ThinkingBudget < -1 {
+		return errors.New("thinkingBudget must be -1 (dynamic), 0 (off) or a number of tokens")
+	}
+
+	seen := make(map[HarmCategory]bool)
+	for _, setting := range c.SafetySettings {
+		if setting.Category.Python() == "" {
+			return fmt.Errorf("unknown safety category %q", setting.Category)
+		}
+		if setting.Threshold.Python() == "" {
+			return fmt.Errorf("unknown safety threshold %q for %s", setting.Threshold, setting.Category)
+		}
+		if seen[setting.Category] {
+			return fmt.Errorf("safety category %s is set twice", setting.Category)
+		}
+		seen[setting.Category] = true
+	}
+	return nil
+}
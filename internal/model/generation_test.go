@@ -0,0 +1,145 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func float(v float64) *float64 { return &v }
+
+func integer(v int) *int { return &v }
+
+func TestGenerationConfig_Merge(t *testing.T) {
+	defaults := &GenerationConfig{
+		Temperature:   float(0.2),
+		TopK:          integer(40),
+		StopSequences: []string{"END"},
+		SafetySettings: []SafetySetting{
+			{Category: HarmCategoryHarassment, Threshold: HarmThresholdOnlyHigh},
+			{Category: HarmCategoryHateSpeech, Threshold: HarmThresholdOnlyHigh},
+		},
+	}
+	override := &GenerationConfig{
+		Temperature: float(1),
+		SafetySettings: []SafetySetting{
+			{Category: HarmCategoryDangerousContent, Threshold: HarmThresholdLowAndAbove},
+			{Category: HarmCategoryHarassment, Threshold: HarmThresholdNone},
+		},
+	}
+
+	got := defaults.Merge(override)
+	want := &GenerationConfig{
+		Temperature:   float(1),
+		TopK:          integer(40),
+		StopSequences: []string{"END"},
+		SafetySettings: []SafetySetting{
+			{Category: HarmCategoryHarassment, Threshold: HarmThresholdNone},
+			{Category: HarmCategoryHateSpeech, Threshold: HarmThresholdOnlyHigh},
+			{Category: HarmCategoryDangerousContent, Threshold: HarmThresholdLowAndAbove},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge() = %+v, want %+v", got, want)
+	}
+
+	if *defaults.Temperature != 0.2 || len(defaults.SafetySettings) != 2 {
+		t.Error("Merge() should not modify the defaults")
+	}
+
+	var none *GenerationConfig
+	if got := none.Merge(override); got != override {
+		t.Errorf("nil.Merge() = %+v, want the override", got)
+	}
+	if got := defaults.Merge(nil); got != defaults {
+		t.Errorf("Merge(nil) = %+v, want the defaults", got)
+	}
+}
+
+func TestGenerationConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  GenerationConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "valid",
+			config: GenerationConfig{
+				Temperature:     float(0),
+				TopP:            float(0.95),
+				TopK:            integer(40),
+				MaxOutputTokens: integer(1024),
+				StopSequences:   []string{"END"},
+				ThinkingBudget:  integer(-1),
+				SafetySettings:  []SafetySetting{{Category: HarmCategoryHarassment, Threshold: HarmThresholdOff}},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "temperature out of range",
+			config:  GenerationConfig{Temperature: float(2.5)},
+			wantErr: true,
+			errMsg:  "temperature must be between 0 and 2",
+		},
+		{
+			name:    "topP out of range",
+			config:  GenerationConfig{TopP: float(1.5)},
+			wantErr: true,
+			errMsg:  "topP must be between 0 and 1",
+		},
+		{
+			name:    "zero max output tokens",
+			config:  GenerationConfig{MaxOutputTokens: integer(0)},
+			wantErr: true,
+			errMsg:  "maxOutputTokens must be at least 1",
+		},
+		{
+			name:    "too many stop sequences",
+			config:  GenerationConfig{StopSequences: []string{"a", "b", "c", "d", "e", "f"}},
+			wantErr: true,
+			errMsg:  "at most 5 stop sequences are allowed",
+		},
+		{
+			name:    "negative thinking budget",
+			config:  GenerationConfig{ThinkingBudget: integer(-2)},
+			wantErr: true,
+			errMsg:  "thinkingBudget must be -1 (dynamic), 0 (off) or a number of tokens",
+		},
+		{
+			name:    "unknown safety category",
+			config:  GenerationConfig{SafetySettings: []SafetySetting{{Category: "violence", Threshold: HarmThresholdNone}}},
+			wantErr: true,
+			errMsg:  `unknown safety category "violence"`,
+		},
+		{
+			name:    "unknown safety threshold",
+			config:  GenerationConfig{SafetySettings: []SafetySetting{{Category: HarmCategoryHarassment, Threshold: "some"}}},
+			wantErr: true,
+			errMsg:  `unknown safety threshold "some" for harassment`,
+		},
+		{
+			name: "duplicate safety category",
+			config: GenerationConfig{SafetySettings: []SafetySetting{
+				{Category: HarmCategoryHarassment, Threshold: HarmThresholdNone},
+				{Category: HarmCategoryHarassment, Threshold: HarmThresholdOff},
+			}},
+			wantErr: true,
+			errMsg:  "safety category harassment is set twice",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr && err.Error() != tt.errMsg {
+				t.Errorf("Validate() error message = %v, want %v", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
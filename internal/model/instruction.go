@@ -0,0 +1,103 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Placeholder is a {key} or {artifact.name} reference in an instruction,
+// which ADK replaces with a session state value or the text of an artifact
+// before sending the instruction to the model.
+type Placeholder struct {
+	Key      string
+	Artifact bool
+	// Optional placeholders end in "?" and render as "" when the key is
+	// missing instead of failing the run.
+	Optional bool
+}
+
+func (p Placeholder) String() string {
+	key := p.Key
+	if p.Artifact {
+		key = "artifact." + key
+	}
+	if p.Optional {
+		key += "?"
+	}
+	return "{" + key + "}"
+}
+
+// placeholderPattern and stateNamePattern mirror ADK's instruction
+// templating: braces around anything that is not itself a brace, and state
+// names that are identifiers with an optional app:, user: or temp: prefix.
+var (
+	placeholderPattern = regexp.MustCompile(`\{+[^{}]*\}+`)
+	stateNamePattern   = regexp.MustCompile(`^((app|user|temp):)?[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// ValidateStateKey checks that key is a name placeholders can read back: an
+// identifier with an optional app:, user: or temp: prefix.
+func ValidateStateKey(key string) error {
+	if !stateNamePattern.MatchString(key) {
+		return fmt.Errorf("%q must be letters, digits and underscores, not starting with a digit, with an optional app:, user: or temp: prefix", key)
+	}
+	return nil
+}
+
+// Placeholders lists the placeholders in instruction in order. Braces around
+// text that is not a state name, such as a JSON example, are left alone by
+// ADK and are not returned.
+func Placeholders(instruction string) []Placeholder {
+	var placeholders []Placeholder
+	for _, match := range placeholderPattern.FindAllString(instruction, -1) {
+		name := strings.TrimSpace(strings.TrimRight(strings.TrimLeft(match, "{"), "}"))
+		p := Placeholder{}
+		name, p.Optional = strings.CutSuffix(name, "?")
+
+		if artifact, ok := strings.CutPrefix(name, "artifact."); ok {
+			p.Key, p.Artifact = artifact, true
+		} else if stateNamePattern.MatchString(name) {
+			p.Key = name
+		} else {
+			continue
+		}
+		placeholders = append(placeholders, p)
+	}
+	return placeholders
+}
+
+// CheckPlaceholders returns an error for the first placeholder in
+// instruction that ADK cannot resolve at all: an artifact placeholder without
+// a name.
+func CheckPlaceholders(instruction string) error {
+	for _, p := range Placeholders(instruction) {
+		if p.Artifact && p.Key == "" {
+			return fmt.Errorf("placeholder %s names no artifact", p)
+		}
+	}
+	return nil
+}
+
+// UnknownPlaceholders returns the required state placeholders in instruction
+// that are not among the known keys. Artifacts are saved at run time, and
+// app:, user: and temp: keys are written by tools, callbacks or earlier
+// sessions, so neither is returned; a tool or callback may write a plain key
+// as well, which is why an unknown key is worth a warning and not an error.
+func UnknownPlaceholders(instruction string, known []string) []Placeholder {
+	var unknown []Placeholder
+	for _, p := range Placeholders(instruction) {
+		if p.Artifact || p.Optional || strings.Contains(p.Key, ":") || slices.Contains(known, p.Key) {
+			continue
+		}
+		unknown = append(unknown, p)
+	}
+	return unknown
+}
+
+// UnknownPlaceholderWarning explains an unknown placeholder and how to settle
+// it.
+func UnknownPlaceholderWarning(p Placeholder) string {
+	return fmt.Sprintf("placeholder %s is not the outputKey of an agent that runs earlier; unless a tool or callback writes it first, make it one, or write {%s?} if it may be missing", p, p.Key)
+}
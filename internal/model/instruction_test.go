@@ -0,0 +1,97 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlaceholders(t *testing.T) {
+	tests := []struct {
+		name        string
+		instruction string
+		want        []Placeholder
+	}{
+		{
+			name:        "no placeholders",
+			instruction: "Research the topic",
+			want:        nil,
+		},
+		{
+			name:        "state and artifact placeholders",
+			instruction: "Summarize {findings} using { artifact.notes }.",
+			want: []Placeholder{
+				{Key: "findings"},
+				{Key: "notes", Artifact: true},
+			},
+		},
+		{
+			name:        "optional and prefixed keys",
+			instruction: "Reply in {user:language?} and mind {temp:draft}. {artifact.style?}",
+			want: []Placeholder{
+				{Key: "user:language", Optional: true},
+				{Key: "temp:draft"},
+				{Key: "style", Artifact: true, Optional: true},
+			},
+		},
+		{
+			name:        "braces that are not state names",
+			instruction: `Answer as {"score": 1} or {not a key} or {other:prefix}`,
+			want:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Placeholders(tt.instruction)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Placeholders() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlaceholder_String(t *testing.T) {
+	tests := []struct {
+		placeholder Placeholder
+		want        string
+	}{
+		{Placeholder{Key: "findings"}, "{findings}"},
+		{Placeholder{Key: "notes", Artifact: true, Optional: true}, "{artifact.notes?}"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.placeholder.String(); got != tt.want {
+			t.Errorf("String() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestUnknownPlaceholders(t *testing.T) {
+	tests := []struct {
+		name        string
+		instruction string
+		known       []string
+		want        []Placeholder
+	}{
+		{
+			name:        "known and unknown keys",
+			instruction: "Compare {findings} with {notes}",
+			known:       []string{"findings"},
+			want:        []Placeholder{{Key: "notes"}},
+		},
+		{
+			name:        "optional, prefixed and artifact placeholders",
+			instruction: "Use {draft?}, {user:language}, {app:tone}, {temp:scratch} and {artifact.brief}",
+			want:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnknownPlaceholders(tt.instruction, tt.known)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnknownPlaceholders() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
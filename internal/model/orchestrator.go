@@ -3,6 +3,7 @@ package model
 import (
 	"errors"
 	"fmt"
+	"slices"
 )
 
 type OrchestrationPattern string
@@ -14,6 +15,8 @@ const (
 	PatternLoop           OrchestrationPattern = "loop"
 )
 
+var OrchestrationPatterns = []OrchestrationPattern{PatternSequential, PatternParallel, PatternLLMCoordinated, PatternLoop}
+
 func (p OrchestrationPattern) String() string {
 	switch p {
 	case PatternSequential:
@@ -45,11 +48,19 @@ func (p OrchestrationPattern) Description() string {
 }
 
 type Orchestrator struct {
-	Name        string
-	Pattern     OrchestrationPattern
-	Description string
-	Model       string
-	SubAgents   []*Agent
+	Name        string               `yaml:"name"`
+	Pattern     OrchestrationPattern `yaml:"pattern"`
+	Description string               `yaml:"description,omitempty"`
+	Model       string               `yaml:"model,omitempty"`
+	SubAgents   []*Agent             `yaml:"subAgents"`
+
+	// GlobalInstruction is prepended to the instruction of every agent in
+	// the tree. ADK only reads it from an LLM root agent.
+	GlobalInstruction string `yaml:"globalInstruction,omitempty"`
+
+	// GenerationConfig applies to the orchestrator itself when it calls a
+	// model, and is the default every sub-agent inherits and can override.
+	GenerationConfig *GenerationConfig `yaml:"generationConfig,omitempty"`
 }
 
 func NewOrchestrator(name string, pattern OrchestrationPattern, description, model string) *Orchestrator {
@@ -75,11 +86,74 @@ func (o *Orchestrator) Validate() error {
 		return errors.New("orchestrator must have at least one sub-agent")
 	}
 
+	if o.GenerationConfig != nil {
+		if err := o.GenerationConfig.Validate(); err != nil {
+			return fmt.Errorf("orchestrator generation config: %w", err)
+		}
+	}
+
+	if o.GlobalInstruction != "" {
+		if o.Pattern != PatternLLMCoordinated {
+			return errors.New("global instruction only applies to llm-coordinated orchestrators")
+		}
+		if err := CheckPlaceholders(o.GlobalInstruction); err != nil {
+			return fmt.Errorf("global instruction: %w", err)
+		}
+	}
+
 	for _, agent := range o.SubAgents {
 		if err := agent.Validate(); err != nil {
 			return fmt.Errorf("sub-agent validation failed: %w", err)
 		}
+		if o.Pattern != PatternLLMCoordinated && (agent.DisallowTransferToParent || agent.DisallowTransferToPeers) {
+			return fmt.Errorf("sub-agent %s: transfer controls only apply to llm-coordinated orchestrators", agent.Name)
+		}
+		if err := CheckPlaceholders(agent.Instruction); err != nil {
+			return fmt.Errorf("sub-agent %s: %w", agent.Name, err)
+		}
 	}
 
 	return nil
 }
+
+// PlaceholderWarnings lists the placeholders in the instructions that no
+// agent puts in session state before they are read. They are not errors: a
+// tool or callback may write them.
+func (o *Orchestrator) PlaceholderWarnings() []string {
+	var warnings []string
+	// The coordinator reads the global instruction on the first turn, before
+	// any sub-agent has written to state.
+	for _, p := range UnknownPlaceholders(o.GlobalInstruction, nil) {
+		warnings = append(warnings, "global instruction: "+UnknownPlaceholderWarning(p))
+	}
+	for i, agent := range o.SubAgents {
+		for _, p := range UnknownPlaceholders(agent.Instruction, o.StateKeysBefore(i)) {
+			warnings = append(warnings, fmt.Sprintf("sub-agent %s: %s", agent.Name, UnknownPlaceholderWarning(p)))
+		}
+	}
+	return warnings
+}
+
+// StateKeysBefore returns the output keys that can be in session state when
+// the i-th sub-agent runs: those of the agents before it in a sequence or
+// loop, none in a parallel group, and any sub-agent's under a coordinator,
+// which may have transferred to it already. i may be len(o.SubAgents) for an
+// agent about to be added.
+func (o *Orchestrator) StateKeysBefore(i int) []string {
+	var writers []*Agent
+	switch o.Pattern {
+	case PatternParallel:
+	case PatternLLMCoordinated:
+		writers = o.SubAgents
+	default:
+		writers = o.SubAgents[:i]
+	}
+
+	var keys []string
+	for _, agent := range writers {
+		if agent.OutputKey != "" && !slices.Contains(keys, agent.OutputKey) {
+			keys = append(keys, agent.OutputKey)
+		}
+	}
+	return keys
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -131,6 +132,91 @@ func TestOrchestrator_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "sub-agent validation failed: name cannot be empty",
 		},
+		{
+			name: "transfer controls under llm-coordinated orchestrator",
+			setup: func() *Orchestrator {
+				orch := NewOrchestrator("Coordinator", PatternLLMCoordinated, "Test", "gemini-2.0-flash")
+				agent := NewAgent("Agent1", AgentTypeLLM, "Task", "result", "gemini-2.0-flash")
+				agent.DisallowTransferToParent = true
+				orch.AddSubAgent(agent)
+				return orch
+			},
+			wantErr: false,
+		},
+		{
+			name: "transfer controls under workflow orchestrator returns error",
+			setup: func() *Orchestrator {
+				orch := NewOrchestrator("Coordinator", PatternSequential, "Test", "gemini-2.0-flash")
+				agent := NewAgent("Agent1", AgentTypeLLM, "Task", "result", "gemini-2.0-flash")
+				agent.DisallowTransferToPeers = true
+				orch.AddSubAgent(agent)
+				return orch
+			},
+			wantErr: true,
+			errMsg:  "sub-agent Agent1: transfer controls only apply to llm-coordinated orchestrators",
+		},
+		{
+			name: "placeholder written by an earlier agent",
+			setup: func() *Orchestrator {
+				orch := NewOrchestrator("Coordinator", PatternSequential, "Test", "gemini-2.0-flash")
+				orch.AddSubAgent(NewAgent("Researcher", AgentTypeLLM, "Research", "findings", "gemini-2.0-flash"))
+				orch.AddSubAgent(NewAgent("Writer", AgentTypeLLM, "Write up {findings} and {artifact.notes}", "", "gemini-2.0-flash"))
+				return orch
+			},
+			wantErr: false,
+		},
+		{
+			name: "placeholder written by a later agent",
+			setup: func() *Orchestrator {
+				orch := NewOrchestrator("Coordinator", PatternLoop, "Test", "gemini-2.0-flash")
+				orch.AddSubAgent(NewAgent("Writer", AgentTypeLLM, "Revise using {feedback}", "draft", "gemini-2.0-flash"))
+				orch.AddSubAgent(NewAgent("Critic", AgentTypeLLM, "Review {draft}", "feedback", "gemini-2.0-flash"))
+				return orch
+			},
+			wantErr: false,
+		},
+		{
+			name: "artifact placeholder without a name returns error",
+			setup: func() *Orchestrator {
+				orch := NewOrchestrator("Coordinator", PatternSequential, "Test", "gemini-2.0-flash")
+				orch.AddSubAgent(NewAgent("Writer", AgentTypeLLM, "Read {artifact.}", "", "gemini-2.0-flash"))
+				return orch
+			},
+			wantErr: true,
+			errMsg:  "sub-agent Writer: placeholder {artifact.} names no artifact",
+		},
+		{
+			name: "optional placeholder of a parallel sibling",
+			setup: func() *Orchestrator {
+				orch := NewOrchestrator("Coordinator", PatternParallel, "Test", "gemini-2.0-flash")
+				orch.AddSubAgent(NewAgent("Researcher", AgentTypeLLM, "Research", "findings", "gemini-2.0-flash"))
+				orch.AddSubAgent(NewAgent("Writer", AgentTypeLLM, "Write up {findings?}", "", "gemini-2.0-flash"))
+				return orch
+			},
+			wantErr: false,
+		},
+		{
+			name: "coordinator sub-agents read any output key",
+			setup: func() *Orchestrator {
+				orch := NewOrchestrator("Coordinator", PatternLLMCoordinated, "Test", "gemini-2.0-flash")
+				orch.AddSubAgent(NewAgent("Writer", AgentTypeLLM, "Revise using {feedback}", "draft", "gemini-2.0-flash"))
+				orch.AddSubAgent(NewAgent("Critic", AgentTypeLLM, "Review {draft}", "feedback", "gemini-2.0-flash"))
+				orch.GlobalInstruction = "Answer in {user:language?}."
+				return orch
+			},
+			wantErr: false,
+		},
+		{
+			name: "global instruction under workflow orchestrator returns error",
+			setup: func() *Orchestrator {
+				orch := NewOrchestrator("Coordinator", PatternSequential, "Test", "gemini-2.0-flash")
+				orch.AddSubAgent(NewAgent("Agent1", AgentTypeLLM, "Task", "result", "gemini-2.0-flash"))
+				orch.GlobalInstruction = "Be concise."
+				return orch
+			},
+			wantErr: true,
+			errMsg:  "global instruction only applies to llm-coordinated orchestrators",
+		},
 	}
 
 	for _, tt := range tests {
@@ -150,6 +236,84 @@ func TestOrchestrator_Validate(t *testing.T) {
 	}
 }
 
+func TestOrchestrator_PlaceholderWarnings(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func() *Orchestrator
+		want  []string
+	}{
+		{
+			name: "keys written earlier, optional and prefixed",
+			setup: func() *Orchestrator {
+				orch := NewOrchestrator("Coordinator", PatternSequential, "Test", "gemini-2.0-flash")
+				orch.AddSubAgent(NewAgent("Researcher", AgentTypeLLM, "Research in {user:language}", "findings", "gemini-2.0-flash"))
+				orch.AddSubAgent(NewAgent("Writer", AgentTypeLLM, "Write up {findings} and {notes?}", "", "gemini-2.0-flash"))
+				return orch
+			},
+			want: nil,
+		},
+		{
+			name: "key written by a later agent",
+			setup: func() *Orchestrator {
+				orch := NewOrchestrator("Coordinator", PatternLoop, "Test", "gemini-2.0-flash")
+				orch.AddSubAgent(NewAgent("Writer", AgentTypeLLM, "Revise using {feedback}", "draft", "gemini-2.0-flash"))
+				orch.AddSubAgent(NewAgent("Critic", AgentTypeLLM, "Review {draft}", "feedback", "gemini-2.0-flash"))
+				return orch
+			},
+			want: []string{
+				"sub-agent Writer: placeholder {feedback} is not the outputKey of an agent that runs earlier; unless a tool or callback writes it first, make it one, or write {feedback?} if it may be missing",
+			},
+		},
+		{
+			name: "global instruction reads a sub-agent's key",
+			setup: func() *Orchestrator {
+				orch := NewOrchestrator("Coordinator", PatternLLMCoordinated, "Test", "gemini-2.0-flash")
+				orch.AddSubAgent(NewAgent("Agent1", AgentTypeLLM, "Task", "result", "gemini-2.0-flash"))
+				orch.GlobalInstruction = "Build on {result}."
+				return orch
+			},
+			want: []string{
+				"global instruction: placeholder {result} is not the outputKey of an agent that runs earlier; unless a tool or callback writes it first, make it one, or write {result?} if it may be missing",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.setup().PlaceholderWarnings()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PlaceholderWarnings() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrchestrator_StateKeysBefore(t *testing.T) {
+	tests := []struct {
+		pattern OrchestrationPattern
+		want    []string
+	}{
+		{pattern: PatternSequential, want: []string{"findings"}},
+		{pattern: PatternLoop, want: []string{"findings"}},
+		{pattern: PatternParallel, want: nil},
+		{pattern: PatternLLMCoordinated, want: []string{"findings", "draft"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.pattern), func(t *testing.T) {
+			orch := NewOrchestrator("Coordinator", tt.pattern, "Test", "gemini-2.0-flash")
+			orch.AddSubAgent(NewAgent("Researcher", AgentTypeLLM, "Research", "findings", "gemini-2.0-flash"))
+			orch.AddSubAgent(NewAgent("Writer", AgentTypeLLM, "Write", "draft", "gemini-2.0-flash"))
+			orch.AddSubAgent(NewAgent("Editor", AgentTypeLLM, "Edit", "draft", "gemini-2.0-flash"))
+
+			got := orch.StateKeysBefore(1)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("StateKeysBefore(1) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestOrchestrationPattern_String(t *testing.T) {
 	tests := []struct {
 		pattern OrchestrationPattern
@@ -189,3 +353,24 @@ func TestOrchestrationPattern_Description(t *testing.T) {
 		})
 	}
 }
+
+func TestOrchestrationPatterns(t *testing.T) {
+	patterns := OrchestrationPatterns
+
+	if len(patterns) != 4 {
+		t.Errorf("Expected 4 patterns, got %d", len(patterns))
+	}
+
+	expectedPatterns := []OrchestrationPattern{
+		PatternSequential,
+		PatternParallel,
+		PatternLLMCoordinated,
+		PatternLoop,
+	}
+
+	for i, pattern := range expectedPatterns {
+		if patterns[i] != pattern {
+			t.Errorf("Pattern %d = %v, want %v", i, patterns[i], pattern)
+		}
+	}
+}
@@ -3,32 +3,163 @@ package model
 import (
 	"errors"
 	"fmt"
+	"regexp"
+
+	"github.com/doji-co/agent-builder/internal/adk"
+)
+
+type Packaging string
+
+const (
+	PackagingRequirements Packaging = "requirements"
+	PackagingUV           Packaging = "uv"
+	PackagingPoetry       Packaging = "poetry"
 )
 
+var Packagings = []Packaging{PackagingRequirements, PackagingUV, PackagingPoetry}
+
+func (p Packaging) String() string {
+	switch p {
+	case PackagingRequirements:
+		return "requirements.txt"
+	case PackagingUV:
+		return "uv"
+	case PackagingPoetry:
+		return "Poetry"
+	default:
+		return string(p)
+	}
+}
+
+func (p Packaging) Description() string {
+	switch p {
+	case PackagingRequirements:
+		return "pip with requirements.txt and requirements-dev.txt"
+	case PackagingUV:
+		return "PEP 621 pyproject.toml managed with uv"
+	case PackagingPoetry:
+		return "pyproject.toml managed with Poetry"
+	default:
+		return ""
+	}
+}
+
+func (p Packaging) InstallCommand() string {
+	switch p {
+	case PackagingUV:
+		return "uv sync"
+	case PackagingPoetry:
+		return "poetry install"
+	default:
+		return "pip install -r requirements-dev.txt"
+	}
+}
+
+// RunPrefix is prepended to a command to run it inside the project's
+// environment, e.g. "uv run pytest".
+func (p Packaging) RunPrefix() string {
+	switch p {
+	case PackagingUV:
+		return "uv run "
+	case PackagingPoetry:
+		return "poetry run "
+	default:
+		return ""
+	}
+}
+
+type Backend string
+
+const (
+	BackendAIStudio Backend = "ai-studio"
+	BackendVertexAI Backend = "vertex-ai"
+)
+
+var Backends = []Backend{BackendAIStudio, BackendVertexAI}
+
+func (b Backend) String() string {
+	switch b {
+	case BackendAIStudio:
+		return "Google AI Studio"
+	case BackendVertexAI:
+		return "Vertex AI"
+	default:
+		return string(b)
+	}
+}
+
+func (b Backend) Description() string {
+	switch b {
+	case BackendAIStudio:
+		return "Gemini API with an API key"
+	case BackendVertexAI:
+		return "Google Cloud project with application default credentials"
+	default:
+		return ""
+	}
+}
+
+// EnvVars lists the environment variables the backend needs besides
+// GOOGLE_GENAI_USE_VERTEXAI.
+func (b Backend) EnvVars() []string {
+	switch b {
+	case BackendVertexAI:
+		return []string{"GOOGLE_CLOUD_PROJECT", "GOOGLE_CLOUD_LOCATION"}
+	default:
+		return []string{"GOOGLE_API_KEY"}
+	}
+}
+
 type Project struct {
-	Name          string
-	Orchestrator  *Orchestrator
-	OutputDir     string
-	AddExample    bool
-	AddReadme     bool
-	AddDocker     bool
+	Name         string        `yaml:"name"`
+	ADKVersion   string        `yaml:"adkVersion"`
+	Backend      Backend       `yaml:"backend"`
+	Services     Services      `yaml:"services"`
+	Packaging    Packaging     `yaml:"packaging"`
+	AddExample   bool          `yaml:"addExample"`
+	AddReadme    bool          `yaml:"addReadme"`
+	AddDocker    bool          `yaml:"addDocker"`
+	AddEval      bool          `yaml:"addEval"`
+	AddTests     bool          `yaml:"addTests"`
+	OutputDir    string        `yaml:"-"`
+	Orchestrator *Orchestrator `yaml:"orchestrator"`
 }
 
 func NewProject(name string, orchestrator *Orchestrator) *Project {
 	return &Project{
-		Name:          name,
-		Orchestrator:  orchestrator,
-		OutputDir:     fmt.Sprintf("./%s", name),
-		AddExample:    true,
-		AddReadme:     true,
-		AddDocker:     false,
+		Name:         name,
+		Orchestrator: orchestrator,
+		OutputDir:    fmt.Sprintf("./%s", name),
+		AddExample:   true,
+		AddReadme:    true,
+		AddDocker:    false,
+		AddEval:      true,
+		AddTests:     true,
+		Packaging:    PackagingRequirements,
+		ADKVersion:   adk.Default,
+		Backend:      BackendAIStudio,
+		Services:     DefaultServices(),
 	}
 }
 
-func (p *Project) Validate() error {
-	if p.Name == "" {
+var projectNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// ValidateProjectName checks that name can be used as the project's directory
+// and package name: no path separators, no "..", nothing to quote.
+func ValidateProjectName(name string) error {
+	if name == "" {
 		return errors.New("project name cannot be empty")
 	}
+	if !projectNameRegex.MatchString(name) {
+		return errors.New("project name must contain only letters, numbers, hyphens, and underscores")
+	}
+	return nil
+}
+
+func (p *Project) Validate() error {
+	if err := ValidateProjectName(p.Name); err != nil {
+		return err
+	}
 
 	if p.Orchestrator == nil {
 		return errors.New("orchestrator cannot be nil")
@@ -38,5 +169,43 @@ func (p *Project) Validate() error {
 		return fmt.Errorf("orchestrator validation failed: %w", err)
 	}
 
+	switch p.Packaging {
+	case PackagingRequirements, PackagingUV, PackagingPoetry:
+	default:
+		return fmt.Errorf("unknown packaging %q", p.Packaging)
+	}
+
+	switch p.Backend {
+	case BackendAIStudio, BackendVertexAI:
+	default:
+		return fmt.Errorf("unknown backend %q", p.Backend)
+	}
+
+	if err := p.Services.Validate(p.Backend); err != nil {
+		return fmt.Errorf("services: %w", err)
+	}
+	if p.Services.Memory == MemoryNone {
+		for _, agent := range p.Orchestrator.SubAgents {
+			if agent.Memory {
+				return fmt.Errorf("agent %s uses memory, but no memory service is configured", agent.Name)
+			}
+		}
+	}
+
+	version, err := adk.Lookup(p.ADKVersion)
+	if err != nil {
+		return err
+	}
+	if p.AddEval && !version.EvalSets {
+		return fmt.Errorf("ADK %s does not support the generated evaluation set; target ADK 1.0 or later or disable evaluation", version.Name)
+	}
+	if !version.StaticInstruction {
+		for _, agent := range p.Orchestrator.SubAgents {
+			if agent.StaticInstruction != "" {
+				return fmt.Errorf("ADK %s does not support the static instruction of %s; target ADK 1.15 or later", version.Name, agent.Name)
+			}
+		}
+	}
+
 	return nil
 }
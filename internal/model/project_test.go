@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -19,6 +20,12 @@ func TestNewProject(t *testing.T) {
 	if project.OutputDir == "" {
 		t.Error("OutputDir should have default value")
 	}
+	if project.Packaging != PackagingRequirements {
+		t.Errorf("Packaging = %v, want %v", project.Packaging, PackagingRequirements)
+	}
+	if project.ADKVersion != "1.0" {
+		t.Errorf("ADKVersion = %v, want 1.0", project.ADKVersion)
+	}
 }
 
 func TestProject_Validate(t *testing.T) {
@@ -47,6 +54,16 @@ func TestProject_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "project name cannot be empty",
 		},
+		{
+			name: "name with a path returns error",
+			setup: func() *Project {
+				orch := NewOrchestrator("Coordinator", PatternSequential, "Test", "gemini-2.0-flash")
+				orch.AddSubAgent(NewAgent("Agent1", AgentTypeLLM, "Task", "result", "gemini-2.0-flash"))
+				return NewProject("../escaped", orch)
+			},
+			wantErr: true,
+			errMsg:  "project name must contain only letters, numbers, hyphens, and underscores",
+		},
 		{
 			name: "nil orchestrator returns error",
 			setup: func() *Project {
@@ -55,6 +72,55 @@ func TestProject_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "orchestrator cannot be nil",
 		},
+		{
+			name: "unsupported ADK version returns error",
+			setup: func() *Project {
+				orch := NewOrchestrator("Coordinator", PatternSequential, "Test", "gemini-2.0-flash")
+				orch.AddSubAgent(NewAgent("Agent1", AgentTypeLLM, "Task", "result", "gemini-2.0-flash"))
+				project := NewProject("my-project", orch)
+				project.ADKVersion = "0.1"
+				return project
+			},
+			wantErr: true,
+			errMsg:  `unsupported ADK version "0.1" (supported: 0.5, 1.0, 1.15)`,
+		},
+		{
+			name: "evaluation set on pre-1.0 ADK returns error",
+			setup: func() *Project {
+				orch := NewOrchestrator("Coordinator", PatternSequential, "Test", "gemini-2.0-flash")
+				orch.AddSubAgent(NewAgent("Agent1", AgentTypeLLM, "Task", "result", "gemini-2.0-flash"))
+				project := NewProject("my-project", orch)
+				project.ADKVersion = "0.5"
+				return project
+			},
+			wantErr: true,
+			errMsg:  "ADK 0.5 does not support the generated evaluation set; target ADK 1.0 or later or disable evaluation",
+		},
+		{
+			name: "static instruction before ADK 1.15 returns error",
+			setup: func() *Project {
+				orch := NewOrchestrator("Coordinator", PatternSequential, "Test", "gemini-2.0-flash")
+				agent := NewAgent("Agent1", AgentTypeLLM, "Task", "result", "gemini-2.0-flash")
+				agent.StaticInstruction = "You are a careful analyst."
+				orch.AddSubAgent(agent)
+				return NewProject("my-project", orch)
+			},
+			wantErr: true,
+			errMsg:  "ADK 1.0 does not support the static instruction of Agent1; target ADK 1.15 or later",
+		},
+		{
+			name: "static instruction on ADK 1.15",
+			setup: func() *Project {
+				orch := NewOrchestrator("Coordinator", PatternSequential, "Test", "gemini-2.0-flash")
+				agent := NewAgent("Agent1", AgentTypeLLM, "Task", "result", "gemini-2.0-flash")
+				agent.StaticInstruction = "You are a careful analyst."
+				orch.AddSubAgent(agent)
+				project := NewProject("my-project", orch)
+				project.ADKVersion = "1.15"
+				return project
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid orchestrator returns error",
 			setup: func() *Project {
@@ -82,3 +148,136 @@ func TestProject_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestProject_Validate_Packaging(t *testing.T) {
+	tests := []struct {
+		name      string
+		packaging Packaging
+		wantErr   bool
+	}{
+		{name: "requirements", packaging: PackagingRequirements, wantErr: false},
+		{name: "uv", packaging: PackagingUV, wantErr: false},
+		{name: "poetry", packaging: PackagingPoetry, wantErr: false},
+		{name: "unknown", packaging: "pipenv", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orch := NewOrchestrator("Coordinator", PatternSequential, "Test", "gemini-2.0-flash")
+			orch.AddSubAgent(NewAgent("Agent1", AgentTypeLLM, "Task", "result", "gemini-2.0-flash"))
+			project := NewProject("my-project", orch)
+			project.Packaging = tt.packaging
+
+			err := project.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPackaging_Commands(t *testing.T) {
+	tests := []struct {
+		packaging   Packaging
+		wantInstall string
+		wantRun     string
+	}{
+		{PackagingRequirements, "pip install -r requirements-dev.txt", ""},
+		{PackagingUV, "uv sync", "uv run "},
+		{PackagingPoetry, "poetry install", "poetry run "},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.packaging), func(t *testing.T) {
+			if got := tt.packaging.InstallCommand(); got != tt.wantInstall {
+				t.Errorf("InstallCommand() = %v, want %v", got, tt.wantInstall)
+			}
+			if got := tt.packaging.RunPrefix(); got != tt.wantRun {
+				t.Errorf("RunPrefix() = %v, want %v", got, tt.wantRun)
+			}
+		})
+	}
+}
+
+func TestProject_Validate_Backend(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend Backend
+		wantErr bool
+	}{
+		{name: "ai studio", backend: BackendAIStudio, wantErr: false},
+		{name: "vertex ai", backend: BackendVertexAI, wantErr: false},
+		{name: "unknown", backend: "bedrock", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orch := NewOrchestrator("Coordinator", PatternSequential, "Test", "gemini-2.0-flash")
+			orch.AddSubAgent(NewAgent("Agent1", AgentTypeLLM, "Task", "result", "gemini-2.0-flash"))
+			project := NewProject("my-project", orch)
+			project.Backend = tt.backend
+
+			err := project.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBackend_EnvVars(t *testing.T) {
+	tests := []struct {
+		backend Backend
+		want    string
+	}{
+		{BackendAIStudio, "GOOGLE_API_KEY"},
+		{BackendVertexAI, "GOOGLE_CLOUD_PROJECT,GOOGLE_CLOUD_LOCATION"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.backend), func(t *testing.T) {
+			if got := strings.Join(tt.backend.EnvVars(), ","); got != tt.want {
+				t.Errorf("EnvVars() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackagings(t *testing.T) {
+	packagings := Packagings
+
+	expected := []Packaging{
+		PackagingRequirements,
+		PackagingUV,
+		PackagingPoetry,
+	}
+
+	if len(packagings) != len(expected) {
+		t.Fatalf("Expected %d packagings, got %d", len(expected), len(packagings))
+	}
+
+	for i, packaging := range expected {
+		if packagings[i] != packaging {
+			t.Errorf("Packaging %d = %v, want %v", i, packagings[i], packaging)
+		}
+	}
+}
+
+func TestBackends(t *testing.T) {
+	backends := Backends
+
+	expected := []Backend{
+		BackendAIStudio,
+		BackendVertexAI,
+	}
+
+	if len(backends) != len(expected) {
+		t.Fatalf("Expected %d backends, got %d", len(expected), len(backends))
+	}
+
+	for i, backend := range expected {
+		if backends[i] != backend {
+			t.Errorf("Backend %d = %v, want %v", i, backends[i], backend)
+		}
+	}
+}
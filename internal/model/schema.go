@@ -0,0 +1,73 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+type FieldType string
+
+const (
+	FieldTypeString  FieldType = "string"
+	FieldTypeInteger FieldType = "integer"
+	FieldTypeNumber  FieldType = "number"
+	FieldTypeBoolean FieldType = "boolean"
+)
+
+// FieldTypes lists the types a schema field can have.
+var FieldTypes = []FieldType{FieldTypeString, FieldTypeInteger, FieldTypeNumber, FieldTypeBoolean}
+
+// Schema describes the structured data an agent accepts or returns, either as
+// a flat list of fields or as a JSON Schema object for nested data. It is
+// generated as a Pydantic model in the agent's schemas.py.
+type Schema struct {
+	Name       string                 `yaml:"name,omitempty"`
+	Fields     []Field                `yaml:"fields,omitempty"`
+	JSONSchema map[string]interface{} `yaml:"jsonSchema,omitempty"`
+}
+
+type Field struct {
+	Name        string    `yaml:"name"`
+	Type        FieldType `yaml:"type"`
+	Description string    `yaml:"description,omitempty"`
+	List        bool      `yaml:"list,omitempty"`
+	Optional    bool      `yaml:"optional,omitempty"`
+	Enum        []string  `yaml:"enum,omitempty"`
+}
+
+func (s *Schema) Validate() error {
+	if len(s.Fields) > 0 && s.JSONSchema != nil {
+		return errors.New("set either fields or jsonSchema, not both")
+	}
+	if len(s.Fields) == 0 && s.JSONSchema == nil {
+		return errors.New("fields or jsonSchema is required")
+	}
+
+	seen := make(map[string]bool, len(s.Fields))
+	for _, field := range s.Fields {
+		if field.Name == "" {
+			return errors.New("field name cannot be empty")
+		}
+		if seen[field.Name] {
+			return fmt.Errorf("field %q is declared twice", field.Name)
+		}
+		seen[field.Name] = true
+
+		if !field.Type.valid() {
+			return fmt.Errorf("field %q has unknown type %q (want string, integer, number or boolean)", field.Name, field.Type)
+		}
+		if len(field.Enum) > 0 && field.Type != FieldTypeString {
+			return fmt.Errorf("field %q: enum is only supported for string fields", field.Name)
+		}
+	}
+	return nil
+}
+
+func (t FieldType) valid() bool {
+	for _, known := range FieldTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
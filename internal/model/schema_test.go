@@ -0,0 +1,78 @@
+package model
+
+import "testing"
+
+func TestSchema_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  Schema
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "fields",
+			schema: Schema{Fields: []Field{
+				{Name: "summary", Type: FieldTypeString},
+				{Name: "sources", Type: FieldTypeString, List: true},
+				{Name: "tone", Type: FieldTypeString, Enum: []string{"formal", "casual"}},
+			}},
+			wantErr: false,
+		},
+		{
+			name:    "json schema",
+			schema:  Schema{JSONSchema: map[string]interface{}{"type": "object"}},
+			wantErr: false,
+		},
+		{
+			name:    "empty schema returns error",
+			schema:  Schema{},
+			wantErr: true,
+			errMsg:  "fields or jsonSchema is required",
+		},
+		{
+			name: "fields and json schema returns error",
+			schema: Schema{
+				Fields:     []Field{{Name: "summary", Type: FieldTypeString}},
+				JSONSchema: map[string]interface{}{"type": "object"},
+			},
+			wantErr: true,
+			errMsg:  "set either fields or jsonSchema, not both",
+		},
+		{
+			name:    "unknown field type returns error",
+			schema:  Schema{Fields: []Field{{Name: "when", Type: "date"}}},
+			wantErr: true,
+			errMsg:  `field "when" has unknown type "date" (want string, integer, number or boolean)`,
+		},
+		{
+			name: "duplicate field returns error",
+			schema: Schema{Fields: []Field{
+				{Name: "summary", Type: FieldTypeString},
+				{Name: "summary", Type: FieldTypeInteger},
+			}},
+			wantErr: true,
+			errMsg:  `field "summary" is declared twice`,
+		},
+		{
+			name:    "enum on a number returns error",
+			schema:  Schema{Fields: []Field{{Name: "score", Type: FieldTypeNumber, Enum: []string{"1"}}}},
+			wantErr: true,
+			errMsg:  `field "score": enum is only supported for string fields`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.Validate()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr && err.Error() != tt.errMsg {
+				t.Errorf("Validate() error message = %v, want %v", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
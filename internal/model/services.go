@@ -0,0 +1,229 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DefaultDatabaseURL keeps sessions in a SQLite file next to main.py, which
+// needs no server or network access.
+const DefaultDatabaseURL = "sqlite:///./sessions.db"
+
+// DefaultArtifactDir is where the local artifact service stores files.
+const DefaultArtifactDir = "artifacts"
+
+type SessionService string
+
+const (
+	SessionInMemory SessionService = "in-memory"
+	SessionDatabase SessionService = "database"
+	SessionVertexAI SessionService = "vertex-ai"
+)
+
+var SessionServices = []SessionService{SessionInMemory, SessionDatabase, SessionVertexAI}
+
+func (s SessionService) String() string {
+	switch s {
+	case SessionInMemory:
+		return "In-memory"
+	case SessionDatabase:
+		return "Database"
+	case SessionVertexAI:
+		return "Vertex AI"
+	default:
+		return string(s)
+	}
+}
+
+func (s SessionService) Description() string {
+	switch s {
+	case SessionInMemory:
+		return "lost when the process exits"
+	case SessionDatabase:
+		return "SQLite file or any SQLAlchemy database URL"
+	case SessionVertexAI:
+		return "managed by a Vertex AI Agent Engine"
+	default:
+		return ""
+	}
+}
+
+// NeedsVertexAI reports whether the service is addressed through the Vertex
+// AI project and location the backend configures.
+func (s SessionService) NeedsVertexAI() bool {
+	return s == SessionVertexAI
+}
+
+type MemoryService string
+
+const (
+	MemoryNone        MemoryService = "none"
+	MemoryInMemory    MemoryService = "in-memory"
+	MemoryVertexAIRAG MemoryService = "vertex-ai-rag"
+)
+
+var MemoryServices = []MemoryService{MemoryNone, MemoryInMemory, MemoryVertexAIRAG}
+
+func (m MemoryService) String() string {
+	switch m {
+	case MemoryNone:
+		return "None"
+	case MemoryInMemory:
+		return "In-memory"
+	case MemoryVertexAIRAG:
+		return "Vertex AI RAG"
+	default:
+		return string(m)
+	}
+}
+
+func (m MemoryService) Description() string {
+	switch m {
+	case MemoryNone:
+		return "agents only see the current session"
+	case MemoryInMemory:
+		return "keyword search over past sessions, lost when the process exits"
+	case MemoryVertexAIRAG:
+		return "past sessions indexed in a Vertex AI RAG corpus"
+	default:
+		return ""
+	}
+}
+
+// NeedsVertexAI reports whether the service is addressed through the Vertex
+// AI project and location the backend configures.
+func (m MemoryService) NeedsVertexAI() bool {
+	return m == MemoryVertexAIRAG
+}
+
+type ArtifactService string
+
+const (
+	ArtifactInMemory ArtifactService = "in-memory"
+	ArtifactLocal    ArtifactService = "local"
+	ArtifactGCS      ArtifactService = "gcs"
+)
+
+var ArtifactServices = []ArtifactService{ArtifactInMemory, ArtifactLocal, ArtifactGCS}
+
+func (a ArtifactService) String() string {
+	switch a {
+	case ArtifactInMemory:
+		return "In-memory"
+	case ArtifactLocal:
+		return "Local filesystem"
+	case ArtifactGCS:
+		return "Google Cloud Storage"
+	default:
+		return string(a)
+	}
+}
+
+func (a ArtifactService) Description() string {
+	switch a {
+	case ArtifactInMemory:
+		return "lost when the process exits"
+	case ArtifactLocal:
+		return "files in a directory next to main.py"
+	case ArtifactGCS:
+		return "objects in a GCS bucket"
+	default:
+		return ""
+	}
+}
+
+// Services selects where the generated runner keeps sessions, memory and
+// artifacts. Values left empty in DatabaseURL, RAGCorpus, ArtifactDir and
+// Bucket are read from the environment or fall back to the defaults above.
+type Services struct {
+	Session     SessionService  `yaml:"session"`
+	DatabaseURL string          `yaml:"databaseUrl,omitempty"`
+	Memory      MemoryService   `yaml:"memory"`
+	RAGCorpus   string          `yaml:"ragCorpus,omitempty"`
+	Artifact    ArtifactService `yaml:"artifact"`
+	ArtifactDir string          `yaml:"artifactDir,omitempty"`
+	Bucket      string          `yaml:"bucket,omitempty"`
+}
+
+func DefaultServices() Services {
+	return Services{
+		Session:  SessionInMemory,
+		Memory:   MemoryNone,
+		Artifact: ArtifactInMemory,
+	}
+}
+
+// DatabaseURLOrDefault returns the configured database URL or the SQLite
+// default.
+func (s Services) DatabaseURLOrDefault() string {
+	if s.DatabaseURL != "" {
+		return s.DatabaseURL
+	}
+	return DefaultDatabaseURL
+}
+
+// ArtifactDirOrDefault returns the configured artifact directory or the
+// default.
+func (s Services) ArtifactDirOrDefault() string {
+	if s.ArtifactDir != "" {
+		return s.ArtifactDir
+	}
+	return DefaultArtifactDir
+}
+
+// EnvVars lists the environment variables main.py reads without a default
+// for the selected services.
+func (s Services) EnvVars() []string {
+	var keys []string
+	if s.Session == SessionVertexAI {
+		keys = append(keys, "AGENT_ENGINE_ID")
+	}
+	if s.Memory == MemoryVertexAIRAG && s.RAGCorpus == "" {
+		keys = append(keys, "RAG_CORPUS")
+	}
+	if s.Artifact == ArtifactGCS && s.Bucket == "" {
+		keys = append(keys, "ARTIFACT_BUCKET")
+	}
+	return keys
+}
+
+func (s Services) Validate(backend Backend) error {
+	switch s.Session {
+	case SessionInMemory, SessionDatabase, SessionVertexAI:
+	default:
+		return fmt.Errorf("unknown session service %q", s.Session)
+	}
+	switch s.Memory {
+	case MemoryNone, MemoryInMemory, MemoryVertexAIRAG:
+	default:
+		return fmt.Errorf("unknown memory service %q", s.Memory)
+	}
+	switch s.Artifact {
+	case ArtifactInMemory, ArtifactLocal, ArtifactGCS:
+	default:
+		return fmt.Errorf("unknown artifact service %q", s.Artifact)
+	}
+
+	if s.DatabaseURL != "" && s.Session != SessionDatabase {
+		return errors.New("databaseUrl is only used by the database session service")
+	}
+	if s.RAGCorpus != "" && s.Memory != MemoryVertexAIRAG {
+		return errors.New("ragCorpus is only used by the vertex-ai-rag memory service")
+	}
+	if s.ArtifactDir != "" && s.Artifact != ArtifactLocal {
+		return errors.New("artifactDir is only used by the local artifact service")
+	}
+	if s.Bucket != "" && s.Artifact != ArtifactGCS {
+		return errors.New("bucket is only used by the gcs artifact service")
+	}
+
+	if backend != BackendVertexAI {
+		if s.Session.NeedsVertexAI() {
+			return fmt.Errorf("the %s session service needs the vertex-ai backend", string(s.Session))
+		}
+		if s.Memory.NeedsVertexAI() {
+			return fmt.Errorf("the %s memory service needs the vertex-ai backend", string(s.Memory))
+		}
+	}
+	return nil
+}
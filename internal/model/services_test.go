@@ -0,0 +1,155 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestServices_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		services Services
+		backend  Backend
+		wantErr  bool
+		errMsg   string
+	}{
+		{
+			name:     "defaults",
+			services: DefaultServices(),
+			backend:  BackendAIStudio,
+			wantErr:  false,
+		},
+		{
+			name:     "sqlite sessions, memory and local artifacts work without Google Cloud",
+			services: Services{Session: SessionDatabase, Memory: MemoryInMemory, Artifact: ArtifactLocal, ArtifactDir: "data"},
+			backend:  BackendAIStudio,
+			wantErr:  false,
+		},
+		{
+			name:     "vertex services on vertex ai",
+			services: Services{Session: SessionVertexAI, Memory: MemoryVertexAIRAG, Artifact: ArtifactGCS, Bucket: "agents"},
+			backend:  BackendVertexAI,
+			wantErr:  false,
+		},
+		{
+			name:     "unknown session service",
+			services: Services{Session: "redis", Memory: MemoryNone, Artifact: ArtifactInMemory},
+			backend:  BackendAIStudio,
+			wantErr:  true,
+			errMsg:   `unknown session service "redis"`,
+		},
+		{
+			name:     "unknown artifact service",
+			services: Services{Session: SessionInMemory, Memory: MemoryNone, Artifact: "s3"},
+			backend:  BackendAIStudio,
+			wantErr:  true,
+			errMsg:   `unknown artifact service "s3"`,
+		},
+		{
+			name:     "database url without database sessions",
+			services: Services{Session: SessionInMemory, DatabaseURL: "sqlite:///x.db", Memory: MemoryNone, Artifact: ArtifactInMemory},
+			backend:  BackendAIStudio,
+			wantErr:  true,
+			errMsg:   "databaseUrl is only used by the database session service",
+		},
+		{
+			name:     "vertex sessions on ai studio",
+			services: Services{Session: SessionVertexAI, Memory: MemoryNone, Artifact: ArtifactInMemory},
+			backend:  BackendAIStudio,
+			wantErr:  true,
+			errMsg:   "the vertex-ai session service needs the vertex-ai backend",
+		},
+		{
+			name:     "rag memory on ai studio",
+			services: Services{Session: SessionInMemory, Memory: MemoryVertexAIRAG, Artifact: ArtifactInMemory},
+			backend:  BackendAIStudio,
+			wantErr:  true,
+			errMsg:   "the vertex-ai-rag memory service needs the vertex-ai backend",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.services.Validate(tt.backend)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && err != nil && err.Error() != tt.errMsg {
+				t.Errorf("Validate() error message = %v, want %v", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestServices_Defaults(t *testing.T) {
+	services := DefaultServices()
+	if got := services.DatabaseURLOrDefault(); got != "sqlite:///./sessions.db" {
+		t.Errorf("DatabaseURLOrDefault() = %v, want sqlite:///./sessions.db", got)
+	}
+	services.ArtifactDir = "data"
+	if got := services.ArtifactDirOrDefault(); got != "data" {
+		t.Errorf("ArtifactDirOrDefault() = %v, want data", got)
+	}
+}
+
+func TestServices_EnvVars(t *testing.T) {
+	tests := []struct {
+		name     string
+		services Services
+		want     []string
+	}{
+		{
+			name:     "defaults",
+			services: DefaultServices(),
+			want:     nil,
+		},
+		{
+			name:     "vertex ai services without values",
+			services: Services{Session: SessionVertexAI, Memory: MemoryVertexAIRAG, Artifact: ArtifactGCS},
+			want:     []string{"AGENT_ENGINE_ID", "RAG_CORPUS", "ARTIFACT_BUCKET"},
+		},
+		{
+			name:     "corpus and bucket in the spec",
+			services: Services{Session: SessionDatabase, Memory: MemoryVertexAIRAG, RAGCorpus: "projects/p/locations/l/ragCorpora/c", Artifact: ArtifactGCS, Bucket: "my-bucket"},
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.services.EnvVars(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EnvVars() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProject_Validate_MemoryAgents(t *testing.T) {
+	orch := NewOrchestrator("Coordinator", PatternSequential, "Test", "gemini-2.0-flash")
+	agent := NewAgent("Agent1", AgentTypeLLM, "Task", "result", "gemini-2.0-flash")
+	agent.Memory = true
+	orch.AddSubAgent(agent)
+	project := NewProject("my-project", orch)
+
+	want := "agent Agent1 uses memory, but no memory service is configured"
+	if err := project.Validate(); err == nil || err.Error() != want {
+		t.Errorf("Validate() error = %v, want %v", err, want)
+	}
+
+	project.Services.Memory = MemoryInMemory
+	if err := project.Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestServiceLists(t *testing.T) {
+	if got := SessionServices; len(got) != 3 || got[0] != SessionInMemory {
+		t.Errorf("SessionServices = %v, want in-memory first of 3", got)
+	}
+	if got := MemoryServices; len(got) != 3 || got[0] != MemoryNone {
+		t.Errorf("MemoryServices = %v, want none first of 3", got)
+	}
+	if got := ArtifactServices; len(got) != 3 || got[0] != ArtifactInMemory {
+		t.Errorf("ArtifactServices = %v, want in-memory first of 3", got)
+	}
+}
@@ -0,0 +1,138 @@
+package naming
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// specialLetters covers Latin letters that do not decompose into an ASCII
+// base letter plus combining marks.
+var specialLetters = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "AE", 'œ': "oe", 'Œ': "OE",
+	'ø': "o", 'Ø': "O", 'đ': "d", 'Đ': "D", 'ð': "d", 'Ð': "D",
+	'ł': "l", 'Ł': "L", 'þ': "th", 'Þ': "TH", 'ı': "i",
+}
+
+// Transliterate folds accented Latin letters to ASCII (é → e, ß → ss) and
+// returns an error for any letter or digit it cannot represent.
+func Transliterate(s string) (string, error) {
+	ascii, bad := fold(s)
+	if bad != 0 {
+		return "", fmt.Errorf("%q contains %q, which has no ASCII equivalent", s, bad)
+	}
+	return ascii, nil
+}
+
+// fold is Transliterate without the error: letters and digits that cannot be
+// represented become separators, and the first of them is returned.
+func fold(s string) (string, rune) {
+	var b strings.Builder
+	var bad rune
+	for _, r := range norm.NFD.String(s) {
+		switch {
+		case r < unicode.MaxASCII:
+			b.WriteRune(r)
+		case unicode.Is(unicode.Mn, r):
+			// Combining accent left over from decomposition.
+		case specialLetters[r] != "":
+			b.WriteString(specialLetters[r])
+		default:
+			if bad == 0 && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				bad = r
+			}
+			b.WriteRune(' ')
+		}
+	}
+	return b.String(), bad
+}
+
+// Validate reports whether s can be converted to an identifier.
+func Validate(s string) error {
+	if _, err := Transliterate(s); err != nil {
+		return err
+	}
+	if len(Words(s)) == 0 {
+		return fmt.Errorf("%q contains no letters or digits", s)
+	}
+	return nil
+}
+
+// Words splits s into lowercase words at separators, case changes and the end
+// of acronyms, so "MyLLMAgent" becomes my, llm, agent. Digits stay attached to
+// the word before them. Characters that cannot be transliterated are treated
+// as separators; use Validate to reject them instead.
+func Words(s string) []string {
+	ascii, _ := fold(s)
+
+	var words []string
+	var current []rune
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, strings.ToLower(string(current)))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(ascii)
+	for i, r := range runes {
+		if !isAlnum(r) {
+			flush()
+			continue
+		}
+
+		if i > 0 && len(current) > 0 && isUpper(r) {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && isLower(runes[i+1])
+			if isLower(prev) || isDigit(prev) || (isUpper(prev) && nextIsLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+
+	return words
+}
+
+// SnakeCase converts s to a Python identifier style name: "HTTPFetcher"
+// becomes "http_fetcher".
+func SnakeCase(s string) string {
+	return strings.Join(Words(s), "_")
+}
+
+// KebabCase converts s to a package or directory style name: "HTTPFetcher"
+// becomes "http-fetcher".
+func KebabCase(s string) string {
+	return strings.Join(Words(s), "-")
+}
+
+// PascalCase converts s to a Python class style name: "http-fetcher" becomes
+// "HttpFetcher".
+func PascalCase(s string) string {
+	var b strings.Builder
+	for _, word := range Words(s) {
+		b.WriteString(strings.ToUpper(word[:1]))
+		b.WriteString(word[1:])
+	}
+	return b.String()
+}
+
+func isAlnum(r rune) bool {
+	return isUpper(r) || isLower(r) || isDigit(r)
+}
+
+func isUpper(r rune) bool {
+	return r >= 'A' && r <= 'Z'
+}
+
+func isLower(r rune) bool {
+	return r >= 'a' && r <= 'z'
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -0,0 +1,113 @@
+package naming
+
+import "testing"
+
+var conversionTests = []struct {
+	input  string
+	snake  string
+	kebab  string
+	pascal string
+}{
+	{"Researcher", "researcher", "researcher", "Researcher"},
+	{"ResearchCoordinator", "research_coordinator", "research-coordinator", "ResearchCoordinator"},
+	{"HTTPFetcher", "http_fetcher", "http-fetcher", "HttpFetcher"},
+	{"MyLLMAgent", "my_llm_agent", "my-llm-agent", "MyLlmAgent"},
+	{"APICoordinator", "api_coordinator", "api-coordinator", "ApiCoordinator"},
+	{"grafana-agent", "grafana_agent", "grafana-agent", "GrafanaAgent"},
+	{"Data-Fetcher", "data_fetcher", "data-fetcher", "DataFetcher"},
+	{"data_fetcher", "data_fetcher", "data-fetcher", "DataFetcher"},
+	{"My_Agent", "my_agent", "my-agent", "MyAgent"},
+	{"Agent1", "agent1", "agent1", "Agent1"},
+	{"V2Agent", "v2_agent", "v2-agent", "V2Agent"},
+	{"camelCase", "camel_case", "camel-case", "CamelCase"},
+	{"ALLCAPS", "allcaps", "allcaps", "Allcaps"},
+	{"__weird--name__", "weird_name", "weird-name", "WeirdName"},
+	{"CaféAgent", "cafe_agent", "cafe-agent", "CafeAgent"},
+	{"Straße", "strasse", "strasse", "Strasse"},
+	{"Ørsted-Søker", "orsted_soker", "orsted-soker", "OrstedSoker"},
+}
+
+func TestSnakeCase(t *testing.T) {
+	for _, tt := range conversionTests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := SnakeCase(tt.input); got != tt.snake {
+				t.Errorf("SnakeCase(%q) = %v, want %v", tt.input, got, tt.snake)
+			}
+		})
+	}
+}
+
+func TestKebabCase(t *testing.T) {
+	for _, tt := range conversionTests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := KebabCase(tt.input); got != tt.kebab {
+				t.Errorf("KebabCase(%q) = %v, want %v", tt.input, got, tt.kebab)
+			}
+		})
+	}
+}
+
+func TestPascalCase(t *testing.T) {
+	for _, tt := range conversionTests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := PascalCase(tt.input); got != tt.pascal {
+				t.Errorf("PascalCase(%q) = %v, want %v", tt.input, got, tt.pascal)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "ascii name",
+			input:   "Researcher",
+			wantErr: false,
+		},
+		{
+			name:    "accented latin is transliterated",
+			input:   "Résumé-Writer",
+			wantErr: false,
+		},
+		{
+			name:    "cjk is rejected",
+			input:   "研究者",
+			wantErr: true,
+		},
+		{
+			name:    "cyrillic is rejected",
+			input:   "Агент",
+			wantErr: true,
+		},
+		{
+			name:    "separators only",
+			input:   "--__",
+			wantErr: true,
+		},
+		{
+			name:    "empty",
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWords_UnrepresentableCharactersSeparate(t *testing.T) {
+	got := SnakeCase("Agent研究Writer")
+	if got != "agent_writer" {
+		t.Errorf("SnakeCase() = %v, want agent_writer", got)
+	}
+}
@@ -1,37 +1,38 @@
 package prompt
 
 import (
+	"errors"
 	"fmt"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/ui"
 )
 
-type Interactive struct{}
+type Interactive struct {
+	prompter Prompter
+}
 
-func NewInteractive() *Interactive {
-	return &Interactive{}
+func NewInteractive(prompter Prompter) *Interactive {
+	return &Interactive{prompter: prompter}
 }
 
 func (i *Interactive) PromptProjectType() (string, error) {
-	fmt.Println("\n💡 What would you like to create?")
-	fmt.Println("   • Starter Project: Complete multi-agent system with orchestrator and sub-agents")
-	fmt.Println("     Use this when starting a new ADK project from scratch")
-	fmt.Println()
-	fmt.Println("   • Single Agent: Just one agent folder to add to an existing project")
-	fmt.Println("     Use this when you want to add a new sub-agent to a project you already have")
-	fmt.Println()
-
-	var selection string
-	prompt := &survey.Select{
+	ui.Println("\n💡 What would you like to create?")
+	ui.Println("   • Starter Project: Complete multi-agent system with orchestrator and sub-agents")
+	ui.Println("     Use this when starting a new ADK project from scratch")
+	ui.Println()
+	ui.Println("   • Single Agent: Just one agent folder to add to an existing project")
+	ui.Println("     Use this when you want to add a new sub-agent to a project you already have")
+	ui.Println()
+
+	selection, err := i.prompter.Select(Question{
 		Message: "What would you like to create?",
 		Options: []string{
 			"Starter project (orchestrator + sub-agents)",
 			"Single agent (add to existing project)",
 		},
 		Help: "Choose based on whether you're starting fresh or extending an existing project",
-	}
-	err := survey.AskOne(prompt, &selection)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -43,47 +44,38 @@ func (i *Interactive) PromptProjectType() (string, error) {
 }
 
 func (i *Interactive) PromptProjectName() (string, error) {
-	fmt.Println("\n💡 What is a project?")
-	fmt.Println("   A project is a complete multi-agent system. It will contain all your agents")
-	fmt.Println("   and their configuration. Use kebab-case (my-project) or snake_case (my_project).")
-	fmt.Println()
-
-	var name string
-	prompt := &survey.Input{
-		Message: "Project name?",
-		Help:    "Example: research-assistant, data-processor, content-generator",
-	}
-	err := survey.AskOne(prompt, &name, survey.WithValidator(func(val interface{}) error {
-		if str, ok := val.(string); ok {
-			return ValidateProjectName(str)
-		}
-		return fmt.Errorf("invalid input type")
-	}))
-	return name, err
+	ui.Println("\n💡 What is a project?")
+	ui.Println("   A project is a complete multi-agent system. It will contain all your agents")
+	ui.Println("   and their configuration. Use kebab-case (my-project) or snake_case (my_project).")
+	ui.Println()
+
+	return i.prompter.Input(Question{
+		Message:  "Project name?",
+		Help:     "Example: research-assistant, data-processor, content-generator",
+		Validate: func(name string) error { return ValidateProjectName(name) },
+	})
 }
 
 func (i *Interactive) PromptOrchestrationPattern() (model.OrchestrationPattern, error) {
-	fmt.Println("\n💡 What is an orchestration pattern?")
-	fmt.Println("   The pattern determines HOW your agents work together:")
-	fmt.Println("   • Sequential: Agents run one after another (like an assembly line)")
-	fmt.Println("   • Parallel: Agents run at the same time (for independent tasks)")
-	fmt.Println("   • LLM-Coordinated: The orchestrator decides which agent to call")
-	fmt.Println("   • Loop: Agents repeat until a condition is met (for refinement)")
-	fmt.Println()
-
-	patterns := GetOrchestrationPatterns()
+	ui.Println("\n💡 What is an orchestration pattern?")
+	ui.Println("   The pattern determines HOW your agents work together:")
+	ui.Println("   • Sequential: Agents run one after another (like an assembly line)")
+	ui.Println("   • Parallel: Agents run at the same time (for independent tasks)")
+	ui.Println("   • LLM-Coordinated: The orchestrator decides which agent to call")
+	ui.Println("   • Loop: Agents repeat until a condition is met (for refinement)")
+	ui.Println()
+
+	patterns := model.OrchestrationPatterns
 	options := make([]string, len(patterns))
 	for idx, p := range patterns {
 		options[idx] = fmt.Sprintf("%s (%s)", p.String(), p.Description())
 	}
 
-	var selection string
-	prompt := &survey.Select{
+	selection, err := i.prompter.Select(Question{
 		Message: "Choose orchestration pattern:",
 		Options: options,
 		Help:    "Most common: Sequential (for pipelines) or Parallel (for concurrent tasks)",
-	}
-	err := survey.AskOne(prompt, &selection)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -98,100 +90,78 @@ func (i *Interactive) PromptOrchestrationPattern() (model.OrchestrationPattern,
 }
 
 func (i *Interactive) PromptOrchestratorName() (string, error) {
-	fmt.Println("\n💡 What is an orchestrator?")
-	fmt.Println("   The orchestrator is the ROOT agent that manages all sub-agents.")
-	fmt.Println("   It coordinates when and how sub-agents execute their tasks.")
-	fmt.Println()
-	fmt.Println("   📝 Best practices:")
-	fmt.Println("   • Use descriptive names that indicate the system's purpose")
-	fmt.Println("   • Common patterns: [Purpose]Coordinator, [Domain]Orchestrator, [Task]Manager")
-	fmt.Println("   • Examples: ResearchCoordinator, DataPipelineOrchestrator, ContentManager")
-	fmt.Println()
-
-	var name string
-	prompt := &survey.Input{
-		Message: "Orchestrator name?",
-		Help:    "This will be the main agent that controls your system",
-	}
-	err := survey.AskOne(prompt, &name, survey.WithValidator(func(val interface{}) error {
-		if str, ok := val.(string); ok {
-			return ValidateAgentName(str)
-		}
-		return fmt.Errorf("invalid input type")
-	}))
-	return name, err
+	ui.Println("\n💡 What is an orchestrator?")
+	ui.Println("   The orchestrator is the ROOT agent that manages all sub-agents.")
+	ui.Println("   It coordinates when and how sub-agents execute their tasks.")
+	ui.Println()
+	ui.Println("   📝 Best practices:")
+	ui.Println("   • Use descriptive names that indicate the system's purpose")
+	ui.Println("   • Common patterns: [Purpose]Coordinator, [Domain]Orchestrator, [Task]Manager")
+	ui.Println("   • Examples: ResearchCoordinator, DataPipelineOrchestrator, ContentManager")
+	ui.Println()
+
+	return i.prompter.Input(Question{
+		Message:  "Orchestrator name?",
+		Help:     "This will be the main agent that controls your system",
+		Validate: func(name string) error { return ValidateAgentName(name) },
+	})
 }
 
 func (i *Interactive) PromptOrchestratorDescription() (string, error) {
-	var description string
-	prompt := &survey.Input{
+	return i.prompter.Input(Question{
 		Message: "Orchestrator description?",
-	}
-	err := survey.AskOne(prompt, &description)
-	return description, err
+	})
 }
 
 func (i *Interactive) PromptModel(defaultModel string) (string, error) {
-	fmt.Println("\n💡 What is a model?")
-	fmt.Println("   The model is the AI that powers the agent's intelligence.")
-	fmt.Println()
-	fmt.Println("   📊 Available models:")
-	fmt.Println("   • gemini-2.5-flash: Fast and efficient (recommended for most use cases)")
-	fmt.Println("   • gemini-2.5-pro: Most capable, best for complex reasoning")
-	fmt.Println("   • gemini-2.5-flash-lite: Fastest, best for simple tasks")
-	fmt.Println()
-
-	var selection string
-	prompt := &survey.Select{
+	ui.Println("\n💡 What is a model?")
+	ui.Println("   The model is the AI that powers the agent's intelligence.")
+	ui.Println()
+	ui.Println("   📊 Available models:")
+	ui.Println("   • gemini-2.5-flash: Fast and efficient (recommended for most use cases)")
+	ui.Println("   • gemini-2.5-pro: Most capable, best for complex reasoning")
+	ui.Println("   • gemini-2.5-flash-lite: Fastest, best for simple tasks")
+	ui.Println()
+
+	return i.prompter.Select(Question{
 		Message: "Choose model:",
-		Options: AvailableModels,
+		Options: model.AvailableModels,
 		Default: defaultModel,
 		Help:    "Start with gemini-2.5-flash and upgrade to pro if needed",
-	}
-	err := survey.AskOne(prompt, &selection)
-	return selection, err
+	})
 }
 
-func (i *Interactive) PromptAgentName(agentNumber int) (string, error) {
+func (i *Interactive) PromptAgentName(agentNumber int, taken []string) (string, error) {
 	if agentNumber == 1 {
-		fmt.Println("\n💡 What are sub-agents?")
-		fmt.Println("   Sub-agents are specialized agents that perform specific tasks.")
-		fmt.Println("   The orchestrator coordinates these agents to accomplish complex goals.")
-		fmt.Println()
-		fmt.Println("   📝 Naming best practices:")
-		fmt.Println("   • Use names that describe the agent's specific role")
-		fmt.Println("   • Examples: Researcher, Writer, Reviewer, DataFetcher, Analyzer")
-		fmt.Println("   • Can use kebab-case (data-processor) or PascalCase (DataProcessor)")
-		fmt.Println()
-	}
-
-	var name string
-	prompt := &survey.Input{
-		Message: fmt.Sprintf("Sub-agent #%d name?", agentNumber),
-		Help:    "What specific task will this agent perform?",
-	}
-	err := survey.AskOne(prompt, &name, survey.WithValidator(func(val interface{}) error {
-		if str, ok := val.(string); ok {
-			return ValidateAgentName(str)
-		}
-		return fmt.Errorf("invalid input type")
-	}))
-	return name, err
+		ui.Println("\n💡 What are sub-agents?")
+		ui.Println("   Sub-agents are specialized agents that perform specific tasks.")
+		ui.Println("   The orchestrator coordinates these agents to accomplish complex goals.")
+		ui.Println()
+		ui.Println("   📝 Naming best practices:")
+		ui.Println("   • Use names that describe the agent's specific role")
+		ui.Println("   • Examples: Researcher, Writer, Reviewer, DataFetcher, Analyzer")
+		ui.Println("   • Can use kebab-case (data-processor) or PascalCase (DataProcessor)")
+		ui.Println()
+	}
+
+	return i.prompter.Input(Question{
+		Message:  fmt.Sprintf("Sub-agent #%d name?", agentNumber),
+		Help:     "What specific task will this agent perform?",
+		Validate: func(name string) error { return ValidateAgentName(name, taken...) },
+	})
 }
 
 func (i *Interactive) PromptAgentType() (model.AgentType, error) {
-	types := GetAgentTypes()
+	types := model.AgentTypes
 	options := []string{
 		"LLM Agent (powered by language model)",
 		"Custom Agent (your own Python class)",
 	}
 
-	var selection string
-	prompt := &survey.Select{
+	selection, err := i.prompter.Select(Question{
 		Message: "Agent type:",
 		Options: options,
-	}
-	err := survey.AskOne(prompt, &selection)
+	})
 	if err != nil {
 		return "", err
 	}
@@ -202,63 +172,93 @@ func (i *Interactive) PromptAgentType() (model.AgentType, error) {
 	return types[1], nil
 }
 
-func (i *Interactive) PromptAgentInstruction(agentName string) (string, error) {
-	fmt.Println("\n💡 What is an instruction?")
-	fmt.Println("   The instruction tells the agent WHAT to do. Be specific and clear.")
-	fmt.Println("   The agent will use this as its main goal when processing tasks.")
-	fmt.Println()
-	fmt.Println("   📝 Examples:")
-	fmt.Println("   • 'Research the given topic and provide key findings'")
-	fmt.Println("   • 'Write a comprehensive article based on the research data'")
-	fmt.Println("   • 'Review the content for quality and suggest improvements'")
-	fmt.Println()
-
-	var instruction string
-	prompt := &survey.Input{
-		Message: fmt.Sprintf("Instruction for %s?", agentName),
-		Help:    "Be specific about what this agent should accomplish",
-	}
-	err := survey.AskOne(prompt, &instruction)
-	return instruction, err
+// PromptAgentInstruction asks for an instruction in the editor. With
+// warnKeys, stateKeys are all the keys agents write before this one runs, and
+// a placeholder outside them is pointed out once the editor closes.
+func (i *Interactive) PromptAgentInstruction(agentName string, stateKeys []string, warnKeys bool) (string, error) {
+	ui.Println("\n💡 What is an instruction?")
+	ui.Println("   The instruction tells the agent WHAT to do. Be specific and clear.")
+	ui.Println("   The agent will use this as its main goal when processing tasks.")
+	ui.Println()
+	ui.Println("   📝 Your editor ($VISUAL or $EDITOR) opens with a template listing the")
+	ui.Println("   state keys earlier agents write and example instructions.")
+	ui.Println()
+
+	instruction, err := i.prompter.Editor(Question{
+		Message:  fmt.Sprintf("Instruction for %s?", agentName),
+		Help:     "Be specific about what this agent should accomplish; lines starting with # are ignored",
+		Default:  InstructionTemplate(agentName, stateKeys),
+		FileName: "instruction*.md",
+		Validate: func(text string) error {
+			instruction := StripComments(text)
+			if instruction == "" {
+				return errors.New("instruction cannot be empty")
+			}
+			return model.CheckPlaceholders(instruction)
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	instruction = StripComments(instruction)
+	if warnKeys {
+		for _, p := range model.UnknownPlaceholders(instruction, stateKeys) {
+			ui.Printf("   ⚠️  %s\n", model.UnknownPlaceholderWarning(p))
+		}
+	}
+	return instruction, nil
+}
+
+func (i *Interactive) PromptAgentDescription(agentName string) (string, error) {
+	ui.Println("\n💡 What is a description?")
+	ui.Println("   The coordinator reads each sub-agent's description to decide who handles a request.")
+	ui.Println("   Say what kinds of requests this agent is the right choice for.")
+	ui.Println()
+	ui.Println("   📝 Examples:")
+	ui.Println("   • 'Questions about invoices, refunds and payment methods'")
+	ui.Println("   • 'Technical problems with logging in or using the app'")
+	ui.Println()
+
+	return i.prompter.Input(Question{
+		Message:  fmt.Sprintf("Description of %s?", agentName),
+		Help:     "The coordinator routes requests to sub-agents based on their descriptions",
+		Validate: required,
+	})
 }
 
 func (i *Interactive) PromptOutputKey() (string, error) {
-	fmt.Println("\n💡 What is an output key?")
-	fmt.Println("   The output key is WHERE the agent stores its result for other agents.")
-	fmt.Println("   Subsequent agents can reference this data using {output_key} in their instructions.")
-	fmt.Println()
-	fmt.Println("   📝 Best practices:")
-	fmt.Println("   • Use snake_case: research_data, processed_text, final_report")
-	fmt.Println("   • Be descriptive: what kind of data does this agent produce?")
-	fmt.Println("   • Examples: article_draft, analysis_results, review_feedback")
-	fmt.Println()
-
-	var key string
-	prompt := &survey.Input{
+	ui.Println("\n💡 What is an output key?")
+	ui.Println("   The output key is WHERE the agent stores its result for other agents.")
+	ui.Println("   Subsequent agents can reference this data using {output_key} in their instructions.")
+	ui.Println()
+	ui.Println("   📝 Best practices:")
+	ui.Println("   • Use snake_case: research_data, processed_text, final_report")
+	ui.Println("   • Be descriptive: what kind of data does this agent produce?")
+	ui.Println("   • Examples: article_draft, analysis_results, review_feedback")
+	ui.Println()
+
+	return i.prompter.Input(Question{
 		Message: "Output key?",
 		Help:    "Use snake_case to name where this agent's result will be stored",
-	}
-	err := survey.AskOne(prompt, &key)
-	return key, err
+		Validate: func(key string) error {
+			if key == "" {
+				return nil
+			}
+			return model.ValidateStateKey(key)
+		},
+	})
 }
 
 func (i *Interactive) PromptAddAnotherAgent() (bool, error) {
-	var add bool
-	prompt := &survey.Confirm{
-		Message: "Add another sub-agent?",
-		Default: true,
-	}
-	err := survey.AskOne(prompt, &add)
-	return add, err
+	return i.prompter.Confirm(Question{Message: "Add another sub-agent?"}, true)
 }
 
 func (i *Interactive) PromptOutputDirectory(defaultDir string) (string, error) {
-	var dir string
-	prompt := &survey.Input{
+	dir, err := i.prompter.Input(Question{
 		Message: "Output directory?",
 		Default: defaultDir,
-	}
-	err := survey.AskOne(prompt, &dir)
+	})
 	if dir == "" {
 		dir = defaultDir
 	}
@@ -266,21 +266,230 @@ func (i *Interactive) PromptOutputDirectory(defaultDir string) (string, error) {
 }
 
 func (i *Interactive) PromptAddExample() (bool, error) {
-	var add bool
-	prompt := &survey.Confirm{
-		Message: "Generate example usage?",
-		Default: true,
+	return i.prompter.Confirm(Question{Message: "Generate example usage?"}, true)
+}
+
+// PromptSaveSpec asks whether to keep the wizard's answers as a spec file and
+// returns its path, or "" if the user declines.
+func (i *Interactive) PromptSaveSpec(defaultPath string) (string, error) {
+	save, err := i.prompter.Confirm(Question{
+		Message: "Save your answers as a spec file?",
+		Help:    "Regenerate the same project later with: agent-builder create --spec <file>",
+	}, false)
+	if err != nil || !save {
+		return "", err
+	}
+
+	path, err := i.prompter.Input(Question{
+		Message: "Spec file?",
+		Default: defaultPath,
+	})
+	if path == "" {
+		path = defaultPath
 	}
-	err := survey.AskOne(prompt, &add)
-	return add, err
+	return path, err
 }
 
 func (i *Interactive) PromptAddDocker() (bool, error) {
-	var add bool
-	prompt := &survey.Confirm{
-		Message: "Add Docker support?",
-		Default: false,
+	return i.prompter.Confirm(Question{Message: "Add Docker support?"}, false)
+}
+
+func (i *Interactive) PromptEvalExamples(agentName string) ([]model.Example, error) {
+	add, err := i.prompter.Confirm(Question{
+		Message: fmt.Sprintf("Add example prompts to evaluate %s?", agentName),
+		Help:    "Examples seed the eval/ set that `adk eval` and pytest run against your agents",
+	}, false)
+	if err != nil || !add {
+		return nil, err
+	}
+
+	var examples []model.Example
+	for {
+		var example model.Example
+		if example.Prompt, err = i.prompter.Input(Question{
+			Message:  "Example prompt?",
+			Help:     "A message a user might send that this agent should handle",
+			Validate: required,
+		}); err != nil {
+			return nil, err
+		}
+		if example.Expected, err = i.prompter.Input(Question{
+			Message:  "Expected answer?",
+			Help:     "The response you would accept; evaluation compares the agent's answer against it",
+			Validate: required,
+		}); err != nil {
+			return nil, err
+		}
+		examples = append(examples, example)
+
+		more, err := i.prompter.Confirm(Question{Message: "Add another example?"}, false)
+		if err != nil {
+			return nil, err
+		}
+		if !more {
+			return examples, nil
+		}
+	}
+}
+
+func (i *Interactive) PromptAddEval() (bool, error) {
+	return i.prompter.Confirm(Question{Message: "Generate evaluation set (eval/)?"}, true)
+}
+
+func (i *Interactive) PromptAddTests() (bool, error) {
+	return i.prompter.Confirm(Question{
+		Message: "Generate pytest unit tests (tests/)?",
+		Help:    "Tests check agent wiring against a fake model, so they run offline in CI",
+	}, true)
+}
+
+func (i *Interactive) PromptPackaging() (model.Packaging, error) {
+	packagings := model.Packagings
+	options := make([]string, len(packagings))
+	for idx, p := range packagings {
+		options[idx] = fmt.Sprintf("%s (%s)", p.String(), p.Description())
+	}
+
+	selection, err := i.prompter.Select(Question{
+		Message: "Python packaging:",
+		Options: options,
+		Help:    "Every layout pins Python and the ADK version and includes test and lint dependencies",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for idx, opt := range options {
+		if opt == selection {
+			return packagings[idx], nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid selection")
+}
+
+func (i *Interactive) PromptBackend() (model.Backend, error) {
+	backends := model.Backends
+	options := make([]string, len(backends))
+	for idx, b := range backends {
+		options[idx] = fmt.Sprintf("%s (%s)", b.String(), b.Description())
+	}
+
+	selection, err := i.prompter.Select(Question{
+		Message: "Where will the agents call Gemini?",
+		Options: options,
+		Help:    "Decides which credentials .env.example asks for",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for idx, opt := range options {
+		if opt == selection {
+			return backends[idx], nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid selection")
+}
+
+func (i *Interactive) PromptSessionService(backend model.Backend) (model.SessionService, error) {
+	var services []model.SessionService
+	for _, s := range model.SessionServices {
+		if backend == model.BackendVertexAI || !s.NeedsVertexAI() {
+			services = append(services, s)
+		}
+	}
+	options := make([]string, len(services))
+	for idx, s := range services {
+		options[idx] = fmt.Sprintf("%s (%s)", s.String(), s.Description())
+	}
+
+	selection, err := i.prompter.Select(Question{
+		Message: "Where should main.py keep sessions?",
+		Options: options,
+		Help:    "A database keeps conversations across runs; SQLite needs no server",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for idx, opt := range options {
+		if opt == selection {
+			return services[idx], nil
+		}
 	}
-	err := survey.AskOne(prompt, &add)
-	return add, err
+
+	return "", fmt.Errorf("invalid selection")
+}
+
+func (i *Interactive) PromptDatabaseURL() (string, error) {
+	return i.prompter.Input(Question{
+		Message:  "Database URL?",
+		Default:  model.DefaultDatabaseURL,
+		Help:     "Any SQLAlchemy URL; DATABASE_URL in .env overrides it",
+		Validate: required,
+	})
+}
+
+func (i *Interactive) PromptMemoryService(backend model.Backend) (model.MemoryService, error) {
+	var services []model.MemoryService
+	for _, m := range model.MemoryServices {
+		if backend == model.BackendVertexAI || !m.NeedsVertexAI() {
+			services = append(services, m)
+		}
+	}
+	options := make([]string, len(services))
+	for idx, m := range services {
+		options[idx] = fmt.Sprintf("%s (%s)", m.String(), m.Description())
+	}
+
+	selection, err := i.prompter.Select(Question{
+		Message: "Memory across sessions?",
+		Options: options,
+		Help:    "Agents you pick next get the load_memory tool to search past sessions",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for idx, opt := range options {
+		if opt == selection {
+			return services[idx], nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid selection")
+}
+
+// PromptMemoryAgents asks which of the named agents get the load_memory tool.
+func (i *Interactive) PromptMemoryAgents(names []string) ([]string, error) {
+	return i.prompter.MultiSelect(Question{
+		Message: "Which agents should search memory?",
+		Options: names,
+	}, names)
+}
+
+func (i *Interactive) PromptArtifactService() (model.ArtifactService, error) {
+	services := model.ArtifactServices
+	options := make([]string, len(services))
+	for idx, a := range services {
+		options[idx] = fmt.Sprintf("%s (%s)", a.String(), a.Description())
+	}
+
+	selection, err := i.prompter.Select(Question{
+		Message: "Where should artifacts (files agents save) be stored?",
+		Options: options,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for idx, opt := range options {
+		if opt == selection {
+			return services[idx], nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid selection")
 }
@@ -2,56 +2,65 @@ package prompt
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
+	"strings"
 
+	"github.com/doji-co/agent-builder/internal/generator"
 	"github.com/doji-co/agent-builder/internal/model"
 )
 
-const DefaultModel = "gemini-2.5-flash"
-
-var AvailableModels = []string{
-	"gemini-2.5-flash",
-	"gemini-2.5-pro",
-	"gemini-2.5-flash-lite",
-}
-
-var (
-	projectNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
-	agentNameRegex   = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
-)
+var agentNameRegex = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
 
 func ValidateProjectName(name string) error {
-	if name == "" {
-		return errors.New("project name cannot be empty")
-	}
-	if !projectNameRegex.MatchString(name) {
-		return errors.New("project name must contain only letters, numbers, hyphens, and underscores")
-	}
-	return nil
+	return model.ValidateProjectName(name)
 }
 
-func ValidateAgentName(name string) error {
+func ValidateAgentName(name string, taken ...string) error {
 	if name == "" {
 		return errors.New("agent name cannot be empty")
 	}
 	if !agentNameRegex.MatchString(name) {
 		return errors.New("agent name must contain only letters, numbers, hyphens, and underscores")
 	}
-	return nil
+	return generator.CheckIdentifier(name, taken)
 }
 
-func GetOrchestrationPatterns() []model.OrchestrationPattern {
-	return []model.OrchestrationPattern{
-		model.PatternSequential,
-		model.PatternParallel,
-		model.PatternLLMCoordinated,
-		model.PatternLoop,
+// InstructionTemplate is the text the instruction editor opens with: comments
+// naming the agent, the state keys earlier agents write and a few example
+// instructions. Lines starting with "#" are removed by StripComments.
+func InstructionTemplate(agentName string, stateKeys []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n# Write the instruction for %s above. It tells the agent what to do;\n", agentName)
+	b.WriteString("# be specific and clear. It may span several paragraphs.\n")
+	b.WriteString("# Lines starting with # are ignored, and an empty instruction is rejected.\n")
+	b.WriteString("#\n")
+	if len(stateKeys) == 0 {
+		b.WriteString("# No session state keys from earlier agents are known.\n")
+	} else {
+		b.WriteString("# Earlier agents write these session state keys; {key} inserts a value:\n")
+		for _, key := range stateKeys {
+			fmt.Fprintf(&b, "#   {%s}\n", key)
+		}
 	}
+	b.WriteString("#\n")
+	b.WriteString("# Examples:\n")
+	b.WriteString("#   Research the given topic and provide key findings.\n")
+	b.WriteString("#   Write a comprehensive article based on the research data.\n")
+	b.WriteString("#   Review the content for quality and suggest improvements.\n")
+	return b.String()
 }
 
-func GetAgentTypes() []model.AgentType {
-	return []model.AgentType{
-		model.AgentTypeLLM,
-		model.AgentTypeCustom,
+// StripComments removes the comment lines of an edited template and the
+// blank lines around what is left. Byte order marks, which some editors move
+// along with the text, are dropped too.
+func StripComments(text string) string {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\ufeff", ""), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
 	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
 }
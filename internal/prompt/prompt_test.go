@@ -1,9 +1,8 @@
 package prompt
 
 import (
+	"strings"
 	"testing"
-
-	"github.com/doji-co/agent-builder/internal/model"
 )
 
 func TestValidateProjectName(t *testing.T) {
@@ -58,6 +57,7 @@ func TestValidateAgentName(t *testing.T) {
 	tests := []struct {
 		name    string
 		input   string
+		taken   []string
 		wantErr bool
 	}{
 		{
@@ -90,11 +90,37 @@ func TestValidateAgentName(t *testing.T) {
 			input:   "My Agent",
 			wantErr: true,
 		},
+		{
+			name:    "python keyword",
+			input:   "import",
+			wantErr: true,
+		},
+		{
+			name:    "leading digit",
+			input:   "1st-pass",
+			wantErr: true,
+		},
+		{
+			name:    "accented letters are transliterated",
+			input:   "Café-Agent",
+			wantErr: false,
+		},
+		{
+			name:    "letters without an ASCII equivalent",
+			input:   "研究者",
+			wantErr: true,
+		},
+		{
+			name:    "collides with taken name after conversion",
+			input:   "data_fetcher",
+			taken:   []string{"DataFetcher"},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ValidateAgentName(tt.input)
+			err := ValidateAgentName(tt.input, tt.taken...)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidateAgentName() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -102,42 +128,80 @@ func TestValidateAgentName(t *testing.T) {
 	}
 }
 
-func TestGetOrchestrationPatterns(t *testing.T) {
-	patterns := GetOrchestrationPatterns()
-
-	if len(patterns) != 4 {
-		t.Errorf("Expected 4 patterns, got %d", len(patterns))
-	}
-
-	expectedPatterns := []model.OrchestrationPattern{
-		model.PatternSequential,
-		model.PatternParallel,
-		model.PatternLLMCoordinated,
-		model.PatternLoop,
+func TestInstructionTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		stateKeys []string
+		expected  []string
+	}{
+		{
+			name:      "with state keys",
+			stateKeys: []string{"research_data", "outline"},
+			expected: []string{
+				"# Write the instruction for Writer above.",
+				"#   {research_data}\n#   {outline}\n",
+				"# Examples:",
+			},
+		},
+		{
+			name:     "without state keys",
+			expected: []string{"# No session state keys from earlier agents are known."},
+		},
 	}
 
-	for i, pattern := range expectedPatterns {
-		if patterns[i] != pattern {
-			t.Errorf("Pattern %d = %v, want %v", i, patterns[i], pattern)
-		}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InstructionTemplate("Writer", tt.stateKeys)
+			for _, s := range tt.expected {
+				if !strings.Contains(got, s) {
+					t.Errorf("InstructionTemplate() missing %q:\n%s", s, got)
+				}
+			}
+			if StripComments(got) != "" {
+				t.Errorf("StripComments(InstructionTemplate()) = %q, want empty", StripComments(got))
+			}
+		})
 	}
 }
 
-func TestGetAgentTypes(t *testing.T) {
-	types := GetAgentTypes()
-
-	if len(types) != 2 {
-		t.Errorf("Expected 2 agent types, got %d", len(types))
-	}
-
-	expectedTypes := []model.AgentType{
-		model.AgentTypeLLM,
-		model.AgentTypeCustom,
+func TestStripComments(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "multi-paragraph instruction",
+			text: "\nResearch the topic.\n\nCite {sources}.\n# Write the instruction above.\n#\n",
+			want: "Research the topic.\n\nCite {sources}.",
+		},
+		{
+			name: "windows line endings",
+			text: "# comment\r\nSummarize.\r\n",
+			want: "Summarize.",
+		},
+		{
+			name: "byte order mark",
+			text: "Summarize.\n\ufeff\n# comment\n",
+			want: "Summarize.",
+		},
+		{
+			name: "indented hash is kept",
+			text: "Format:\n  # Title\n",
+			want: "Format:\n  # Title",
+		},
+		{
+			name: "only comments",
+			text: "# one\n# two\n",
+			want: "",
+		},
 	}
 
-	for i, agentType := range expectedTypes {
-		if types[i] != agentType {
-			t.Errorf("AgentType %d = %v, want %v", i, types[i], agentType)
-		}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StripComments(tt.text); got != tt.want {
+				t.Errorf("StripComments() = %q, want %q", got, tt.want)
+			}
+		})
 	}
 }
@@ -0,0 +1,120 @@
+package prompt
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+)
+
+// Question is one question of the wizard. Fields a kind of question does not
+// use are ignored.
+type Question struct {
+	Message string
+	Help    string
+	// Default is the answer to an Input, Editor or Select left empty. The
+	// Editor opens with it below the cursor, as a template.
+	Default string
+	Options []string
+	// Validate rejects an Input or Editor answer; survey asks again, a
+	// scripted session fails.
+	Validate func(string) error
+	// FileName is the pattern of the Editor's temporary file, whose
+	// extension picks the editor's syntax highlighting.
+	FileName string
+}
+
+// Prompter asks the wizard's questions. Interactive decides what to ask;
+// the Prompter decides where the answers come from: a terminal (Survey), a
+// list of answers (Scripted) or another Prompter whose answers are kept
+// (Recorder).
+type Prompter interface {
+	Input(q Question) (string, error)
+	Editor(q Question) (string, error)
+	// Select returns the chosen option.
+	Select(q Question) (string, error)
+	MultiSelect(q Question, defaults []string) ([]string, error)
+	Confirm(q Question, def bool) (bool, error)
+}
+
+// required is survey.Required for string answers.
+func required(s string) error {
+	if strings.TrimSpace(s) == "" {
+		return errors.New("Value is required")
+	}
+	return nil
+}
+
+// Survey asks questions on the terminal.
+type Survey struct{}
+
+func (Survey) Input(q Question) (string, error) {
+	var answer string
+	err := survey.AskOne(&survey.Input{
+		Message: q.Message,
+		Help:    q.Help,
+		Default: q.Default,
+	}, &answer, validator(q))
+	return answer, err
+}
+
+func (Survey) Editor(q Question) (string, error) {
+	var answer string
+	err := survey.AskOne(&survey.Editor{
+		Message:       q.Message,
+		Help:          q.Help,
+		Default:       q.Default,
+		AppendDefault: true,
+		HideDefault:   true,
+		FileName:      q.FileName,
+	}, &answer, validator(q))
+	return answer, err
+}
+
+func (Survey) Select(q Question) (string, error) {
+	var answer string
+	prompt := &survey.Select{
+		Message: q.Message,
+		Help:    q.Help,
+		Options: q.Options,
+	}
+	if q.Default != "" {
+		prompt.Default = q.Default
+	}
+	err := survey.AskOne(prompt, &answer)
+	return answer, err
+}
+
+func (Survey) MultiSelect(q Question, defaults []string) ([]string, error) {
+	var answer []string
+	err := survey.AskOne(&survey.MultiSelect{
+		Message: q.Message,
+		Help:    q.Help,
+		Options: q.Options,
+		Default: defaults,
+	}, &answer)
+	return answer, err
+}
+
+func (Survey) Confirm(q Question, def bool) (bool, error) {
+	var answer bool
+	err := survey.AskOne(&survey.Confirm{
+		Message: q.Message,
+		Help:    q.Help,
+		Default: def,
+	}, &answer)
+	return answer, err
+}
+
+func validator(q Question) survey.AskOpt {
+	return survey.WithValidator(func(val interface{}) error {
+		if q.Validate == nil {
+			return nil
+		}
+		str, ok := val.(string)
+		if !ok {
+			return errors.New("invalid input type")
+		}
+		return q.Validate(str)
+	})
+}
@@ -0,0 +1,249 @@
+package prompt
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/doji-co/agent-builder/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+// Answer is one answer of a recorded or scripted session. Value is a string
+// for Input, Editor and Select questions, a list of strings for MultiSelect
+// and a bool for Confirm. Question, when set, must match the question asked,
+// which catches scripts that drift out of step with the wizard.
+type Answer struct {
+	Question string      `yaml:"question,omitempty"`
+	Value    interface{} `yaml:"answer"`
+}
+
+// LoadAnswers reads an answers file: a YAML list of question and answer
+// pairs, as written by SaveAnswers.
+func LoadAnswers(path string) ([]Answer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read answers: %w", err)
+	}
+	var answers []Answer
+	if err := yaml.Unmarshal(data, &answers); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return answers, nil
+}
+
+func SaveAnswers(path string, answers []Answer) error {
+	data, err := yaml.Marshal(answers)
+	if err != nil {
+		return fmt.Errorf("failed to encode answers: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write answers: %w", err)
+	}
+	return nil
+}
+
+// Scripted answers questions from a list, in order, and echoes each question
+// and answer the way survey leaves them on screen. An empty or missing value
+// takes the question's default; an answer the question would reject is an
+// error, since there is nobody to ask again.
+type Scripted struct {
+	answers []Answer
+	next    int
+}
+
+func NewScripted(answers []Answer) *Scripted {
+	return &Scripted{answers: answers}
+}
+
+// Remaining returns the number of answers not used yet.
+func (s *Scripted) Remaining() int {
+	return len(s.answers) - s.next
+}
+
+func (s *Scripted) take(q Question) (interface{}, error) {
+	if s.next == len(s.answers) {
+		return nil, fmt.Errorf("no answer to %q: the script has %d answers", q.Message, len(s.answers))
+	}
+	answer := s.answers[s.next]
+	s.next++
+	if answer.Question != "" && answer.Question != q.Message {
+		return nil, fmt.Errorf("answer %d is to %q, but the question is %q", s.next, answer.Question, q.Message)
+	}
+	return answer.Value, nil
+}
+
+func (s *Scripted) text(q Question) (string, error) {
+	value, err := s.take(q)
+	if err != nil {
+		return "", err
+	}
+	var answer string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		answer = v
+	case bool, int, float64:
+		answer = fmt.Sprint(v)
+	default:
+		return "", fmt.Errorf("answer %d to %q must be text", s.next, q.Message)
+	}
+	if answer == "" {
+		answer = q.Default
+	}
+	if q.Validate != nil {
+		if err := q.Validate(answer); err != nil {
+			return "", fmt.Errorf("answer %d to %q: %w", s.next, q.Message, err)
+		}
+	}
+	return answer, nil
+}
+
+func (s *Scripted) Input(q Question) (string, error) {
+	answer, err := s.text(q)
+	if err == nil {
+		ui.Printf("? %s %s\n", q.Message, answer)
+	}
+	return answer, err
+}
+
+func (s *Scripted) Editor(q Question) (string, error) {
+	answer, err := s.text(q)
+	if err == nil {
+		ui.Printf("? %s <Received>\n", q.Message)
+	}
+	return answer, err
+}
+
+// Select accepts an option or the start of one up to its parenthesized
+// description, so "Sequential" picks "Sequential (Sub-agents run one after
+// another)". Without an answer or a default it picks the first option, as
+// survey does.
+func (s *Scripted) Select(q Question) (string, error) {
+	answer, err := s.text(q)
+	if err != nil {
+		return "", err
+	}
+	if answer == "" && len(q.Options) > 0 {
+		answer = q.Options[0]
+	}
+	option, err := s.option(q, answer)
+	if err == nil {
+		ui.Printf("? %s %s\n", q.Message, option)
+	}
+	return option, err
+}
+
+func (s *Scripted) MultiSelect(q Question, defaults []string) ([]string, error) {
+	value, err := s.take(q)
+	if err != nil {
+		return nil, err
+	}
+	var items []string
+	switch v := value.(type) {
+	case nil:
+		items = defaults
+	case []string:
+		items = v
+	case []interface{}:
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+	default:
+		return nil, fmt.Errorf("answer %d to %q must be a list", s.next, q.Message)
+	}
+	selected := []string{}
+	for _, item := range items {
+		option, err := s.option(q, item)
+		if err != nil {
+			return nil, err
+		}
+		selected = append(selected, option)
+	}
+	ui.Printf("? %s %s\n", q.Message, strings.Join(selected, ", "))
+	return selected, nil
+}
+
+func (s *Scripted) Confirm(q Question, def bool) (bool, error) {
+	value, err := s.take(q)
+	if err != nil {
+		return false, err
+	}
+	answer := def
+	switch v := value.(type) {
+	case nil:
+	case bool:
+		answer = v
+	default:
+		return false, fmt.Errorf("answer %d to %q must be true or false", s.next, q.Message)
+	}
+	if answer {
+		ui.Printf("? %s Yes\n", q.Message)
+	} else {
+		ui.Printf("? %s No\n", q.Message)
+	}
+	return answer, nil
+}
+
+func (s *Scripted) option(q Question, answer string) (string, error) {
+	for _, option := range q.Options {
+		if option == answer || strings.HasPrefix(option, answer+" (") {
+			return option, nil
+		}
+	}
+	return "", fmt.Errorf("answer %d to %q: %q is not one of %s", s.next, q.Message, answer, strings.Join(q.Options, ", "))
+}
+
+// Recorder asks its Prompter and keeps the questions and answers, so that a
+// session can be saved with SaveAnswers and replayed with Scripted.
+type Recorder struct {
+	Prompter Prompter
+	Answers  []Answer
+}
+
+func (r *Recorder) record(q Question, value interface{}) {
+	r.Answers = append(r.Answers, Answer{Question: q.Message, Value: value})
+}
+
+func (r *Recorder) Input(q Question) (string, error) {
+	answer, err := r.Prompter.Input(q)
+	if err == nil {
+		r.record(q, answer)
+	}
+	return answer, err
+}
+
+// Editor records the edited text without the template's comment lines,
+// which the wizard drops anyway.
+func (r *Recorder) Editor(q Question) (string, error) {
+	answer, err := r.Prompter.Editor(q)
+	if err == nil {
+		r.record(q, StripComments(answer))
+	}
+	return answer, err
+}
+
+func (r *Recorder) Select(q Question) (string, error) {
+	answer, err := r.Prompter.Select(q)
+	if err == nil {
+		r.record(q, answer)
+	}
+	return answer, err
+}
+
+func (r *Recorder) MultiSelect(q Question, defaults []string) ([]string, error) {
+	answer, err := r.Prompter.MultiSelect(q, defaults)
+	if err == nil {
+		r.record(q, slices.Clone(answer))
+	}
+	return answer, err
+}
+
+func (r *Recorder) Confirm(q Question, def bool) (bool, error) {
+	answer, err := r.Prompter.Confirm(q, def)
+	if err == nil {
+		r.record(q, answer)
+	}
+	return answer, err
+}
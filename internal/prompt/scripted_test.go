@@ -0,0 +1,185 @@
+package prompt
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/doji-co/agent-builder/internal/model"
+)
+
+func TestScripted(t *testing.T) {
+	pattern := Question{Message: "Pattern?", Options: []string{"Sequential (one after another)", "Parallel (at the same time)"}}
+
+	tests := []struct {
+		name   string
+		answer interface{}
+		ask    func(p Prompter) (interface{}, error)
+		want   interface{}
+		errMsg string
+	}{
+		{
+			name:   "input",
+			answer: "my-project",
+			ask:    func(p Prompter) (interface{}, error) { return p.Input(Question{Message: "Name?"}) },
+			want:   "my-project",
+		},
+		{
+			name:   "input default",
+			answer: nil,
+			ask:    func(p Prompter) (interface{}, error) { return p.Input(Question{Message: "Dir?", Default: "./out"}) },
+			want:   "./out",
+		},
+		{
+			name:   "input number",
+			answer: 42,
+			ask:    func(p Prompter) (interface{}, error) { return p.Input(Question{Message: "Name?"}) },
+			want:   "42",
+		},
+		{
+			name:   "input rejected",
+			answer: "",
+			ask: func(p Prompter) (interface{}, error) {
+				return p.Input(Question{Message: "Name?", Validate: required})
+			},
+			errMsg: `answer 1 to "Name?": Value is required`,
+		},
+		{
+			name:   "input list",
+			answer: []interface{}{"a"},
+			ask:    func(p Prompter) (interface{}, error) { return p.Input(Question{Message: "Name?"}) },
+			errMsg: `answer 1 to "Name?" must be text`,
+		},
+		{
+			name:   "select full option",
+			answer: "Parallel (at the same time)",
+			ask:    func(p Prompter) (interface{}, error) { return p.Select(pattern) },
+			want:   "Parallel (at the same time)",
+		},
+		{
+			name:   "select option name",
+			answer: "Parallel",
+			ask:    func(p Prompter) (interface{}, error) { return p.Select(pattern) },
+			want:   "Parallel (at the same time)",
+		},
+		{
+			name:   "select first option by default",
+			answer: nil,
+			ask:    func(p Prompter) (interface{}, error) { return p.Select(pattern) },
+			want:   "Sequential (one after another)",
+		},
+		{
+			name:   "select unknown option",
+			answer: "Para",
+			ask:    func(p Prompter) (interface{}, error) { return p.Select(pattern) },
+			errMsg: `answer 1 to "Pattern?": "Para" is not one of`,
+		},
+		{
+			name:   "multi-select from file",
+			answer: []interface{}{"Parallel"},
+			ask:    func(p Prompter) (interface{}, error) { return p.MultiSelect(pattern, nil) },
+			want:   []string{"Parallel (at the same time)"},
+		},
+		{
+			name:   "multi-select none",
+			answer: []interface{}{},
+			ask:    func(p Prompter) (interface{}, error) { return p.MultiSelect(pattern, pattern.Options) },
+			want:   []string{},
+		},
+		{
+			name:   "multi-select defaults",
+			answer: nil,
+			ask:    func(p Prompter) (interface{}, error) { return p.MultiSelect(pattern, pattern.Options[1:]) },
+			want:   []string{"Parallel (at the same time)"},
+		},
+		{
+			name:   "confirm",
+			answer: true,
+			ask:    func(p Prompter) (interface{}, error) { return p.Confirm(Question{Message: "Docker?"}, false) },
+			want:   true,
+		},
+		{
+			name:   "confirm default",
+			answer: nil,
+			ask:    func(p Prompter) (interface{}, error) { return p.Confirm(Question{Message: "Docker?"}, true) },
+			want:   true,
+		},
+		{
+			name:   "confirm text",
+			answer: "yes",
+			ask:    func(p Prompter) (interface{}, error) { return p.Confirm(Question{Message: "Docker?"}, false) },
+			errMsg: `answer 1 to "Docker?" must be true or false`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.ask(NewScripted([]Answer{{Value: tt.answer}}))
+			if tt.errMsg != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.errMsg) {
+					t.Errorf("error = %v, want prefix %v", err, tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("answer = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecorder_Replay(t *testing.T) {
+	models := Question{Message: "Choose model:", Options: model.AvailableModels}
+	session := func(p Prompter) []interface{} {
+		name, _ := p.Input(Question{Message: "Project name?"})
+		instruction, _ := p.Editor(Question{Message: "Instruction?", Default: "\n# Write the instruction above."})
+		model, _ := p.Select(models)
+		agents, _ := p.MultiSelect(Question{Message: "Agents?", Options: []string{"A", "B"}}, nil)
+		docker, _ := p.Confirm(Question{Message: "Docker?"}, false)
+		return []interface{}{name, instruction, model, agents, docker}
+	}
+
+	recorder := &Recorder{Prompter: NewScripted([]Answer{
+		{Value: "my-project"},
+		{Value: "Line one.\n\nLine two.\n# Write the instruction above."},
+		{Value: "gemini-2.5-pro"},
+		{Value: []string{"B"}},
+		{Value: true},
+	})}
+	recorded := session(recorder)
+
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	if err := SaveAnswers(path, recorder.Answers); err != nil {
+		t.Fatalf("SaveAnswers() error = %v", err)
+	}
+	answers, err := LoadAnswers(path)
+	if err != nil {
+		t.Fatalf("LoadAnswers() error = %v", err)
+	}
+	if answers[0].Question != "Project name?" {
+		t.Errorf("Question = %v, want Project name?", answers[0].Question)
+	}
+	if answers[1].Value != "Line one.\n\nLine two." {
+		t.Errorf("recorded instruction = %q, want comments stripped", answers[1].Value)
+	}
+
+	replay := NewScripted(answers)
+	replayed := session(replay)
+	recorded[1] = StripComments(recorded[1].(string))
+	if !reflect.DeepEqual(replayed, recorded) {
+		t.Errorf("replay = %#v, want %#v", replayed, recorded)
+	}
+	if replay.Remaining() != 0 {
+		t.Errorf("Remaining() = %d, want 0", replay.Remaining())
+	}
+}
+
+func TestLoadAnswers_Errors(t *testing.T) {
+	if _, err := LoadAnswers(filepath.Join(t.TempDir(), "missing.yaml")); err == nil || !strings.Contains(err.Error(), "failed to read answers") {
+		t.Errorf("LoadAnswers() error = %v, want read error", err)
+	}
+}
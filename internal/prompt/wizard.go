@@ -0,0 +1,279 @@
+package prompt
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/doji-co/agent-builder/internal/adk"
+	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/ui"
+)
+
+// Project asks the questions of a starter project session, from the project
+// name to Docker support, and returns the project the answers describe. The
+// project is not validated.
+func (i *Interactive) Project(adkVersion adk.Version) (*model.Project, error) {
+	ui.Println("Let's create your multi-agent system.")
+
+	projectName, err := i.PromptProjectName()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project name: %w", err)
+	}
+
+	pattern, err := i.PromptOrchestrationPattern()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orchestration pattern: %w", err)
+	}
+
+	ui.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	ui.Println("📋 ORCHESTRATOR CONFIGURATION")
+	ui.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	orchName, err := i.PromptOrchestratorName()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orchestrator name: %w", err)
+	}
+
+	orchDescription, err := i.PromptOrchestratorDescription()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orchestrator description: %w", err)
+	}
+
+	orchModel, err := i.PromptModel(model.DefaultModel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get orchestrator model: %w", err)
+	}
+
+	orchestrator := model.NewOrchestrator(orchName, pattern, orchDescription, orchModel)
+
+	ui.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	ui.Println("🤖 SUB-AGENTS CONFIGURATION")
+	ui.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	agentNumber := 1
+	takenNames := []string{orchName}
+	for {
+		agentName, err := i.PromptAgentName(agentNumber, takenNames)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get agent name: %w", err)
+		}
+
+		agentType, err := i.PromptAgentType()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get agent type: %w", err)
+		}
+
+		var instruction string
+		if agentType == model.AgentTypeLLM {
+			// A coordinator's sub-agents can also read the keys of the
+			// agents added after them.
+			warnKeys := pattern != model.PatternLLMCoordinated
+			instruction, err = i.PromptAgentInstruction(agentName, orchestrator.StateKeysBefore(len(orchestrator.SubAgents)), warnKeys)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get agent instruction: %w", err)
+			}
+		}
+
+		var description string
+		if pattern == model.PatternLLMCoordinated {
+			description, err = i.PromptAgentDescription(agentName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get agent description: %w", err)
+			}
+		}
+
+		outputKey, err := i.PromptOutputKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get output key: %w", err)
+		}
+
+		agentModel, err := i.PromptModel(model.DefaultModel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get agent model: %w", err)
+		}
+
+		examples, err := i.PromptEvalExamples(agentName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get evaluation examples: %w", err)
+		}
+
+		agent := model.NewAgent(agentName, agentType, instruction, outputKey, agentModel)
+		agent.Description = description
+		agent.Examples = examples
+		orchestrator.AddSubAgent(agent)
+		takenNames = append(takenNames, agentName)
+
+		ui.Printf("\n✓ Sub-agent \"%s\" added to %s\n\n", agentName, orchName)
+
+		addMore, err := i.PromptAddAnotherAgent()
+		if err != nil {
+			return nil, fmt.Errorf("failed to prompt for another agent: %w", err)
+		}
+
+		if !addMore {
+			break
+		}
+
+		agentNumber++
+	}
+
+	ui.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	ui.Println("📦 PROJECT SETUP")
+	ui.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	project := model.NewProject(projectName, orchestrator)
+	project.ADKVersion = adkVersion.Name
+
+	ui.Println("\n💡 Project location:")
+	ui.Printf("   Your project will be created at: ./%s/\n", projectName)
+	ui.Println()
+
+	outputDir, err := i.PromptOutputDirectory(project.OutputDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get output directory: %w", err)
+	}
+	project.OutputDir = outputDir
+
+	backend, err := i.PromptBackend()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get model backend: %w", err)
+	}
+	project.Backend = backend
+
+	services, err := i.promptServices(project)
+	if err != nil {
+		return nil, err
+	}
+	project.Services = services
+
+	addExample, err := i.PromptAddExample()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prompt for example: %w", err)
+	}
+	project.AddExample = addExample
+
+	if adkVersion.EvalSets {
+		addEval, err := i.PromptAddEval()
+		if err != nil {
+			return nil, fmt.Errorf("failed to prompt for evaluation set: %w", err)
+		}
+		project.AddEval = addEval
+	} else {
+		ui.Printf("\n💡 Skipping evaluation set: ADK %s has no EvalSet support.\n", adkVersion.Name)
+		project.AddEval = false
+	}
+
+	packaging, err := i.PromptPackaging()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get packaging: %w", err)
+	}
+	project.Packaging = packaging
+
+	addTests, err := i.PromptAddTests()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prompt for tests: %w", err)
+	}
+	project.AddTests = addTests
+
+	addDocker, err := i.PromptAddDocker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to prompt for Docker: %w", err)
+	}
+	project.AddDocker = addDocker
+
+	return project, nil
+}
+
+// promptServices asks where sessions, memory and artifacts are kept, and
+// gives the agents picked for memory the load_memory tool.
+func (i *Interactive) promptServices(project *model.Project) (model.Services, error) {
+	services := model.DefaultServices()
+
+	session, err := i.PromptSessionService(project.Backend)
+	if err != nil {
+		return services, fmt.Errorf("failed to get session service: %w", err)
+	}
+	services.Session = session
+	if session == model.SessionDatabase {
+		url, err := i.PromptDatabaseURL()
+		if err != nil {
+			return services, fmt.Errorf("failed to get database URL: %w", err)
+		}
+		if url != model.DefaultDatabaseURL {
+			services.DatabaseURL = url
+		}
+	}
+
+	memory, err := i.PromptMemoryService(project.Backend)
+	if err != nil {
+		return services, fmt.Errorf("failed to get memory service: %w", err)
+	}
+	services.Memory = memory
+	if memory != model.MemoryNone {
+		// Agents with an output schema cannot call tools, load_memory included.
+		var names []string
+		for _, agent := range project.Orchestrator.SubAgents {
+			if agent.Type == model.AgentTypeLLM && agent.OutputSchema == nil {
+				names = append(names, agent.Name)
+			}
+		}
+		if len(names) > 0 {
+			selected, err := i.PromptMemoryAgents(names)
+			if err != nil {
+				return services, fmt.Errorf("failed to get memory agents: %w", err)
+			}
+			for _, agent := range project.Orchestrator.SubAgents {
+				agent.Memory = slices.Contains(selected, agent.Name)
+			}
+		}
+	}
+
+	artifact, err := i.PromptArtifactService()
+	if err != nil {
+		return services, fmt.Errorf("failed to get artifact service: %w", err)
+	}
+	services.Artifact = artifact
+
+	return services, nil
+}
+
+// Agent asks the questions of a single agent session and returns the agent
+// the answers describe.
+func (i *Interactive) Agent() (*model.Agent, error) {
+	ui.Println("Let's create a single agent to add to your project.")
+
+	ui.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	ui.Println("🤖 AGENT CONFIGURATION")
+	ui.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+	agentName, err := i.PromptAgentName(1, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent name: %w", err)
+	}
+
+	agentType, err := i.PromptAgentType()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent type: %w", err)
+	}
+
+	var instruction string
+	if agentType == model.AgentTypeLLM {
+		// The agent is generated on its own, so its state is unknown.
+		instruction, err = i.PromptAgentInstruction(agentName, nil, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get agent instruction: %w", err)
+		}
+	}
+
+	outputKey, err := i.PromptOutputKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get output key: %w", err)
+	}
+
+	agentModel, err := i.PromptModel(model.DefaultModel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get agent model: %w", err)
+	}
+
+	return model.NewAgent(agentName, agentType, instruction, outputKey, agentModel), nil
+}
@@ -0,0 +1,299 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/doji-co/agent-builder/internal/adk"
+	"github.com/doji-co/agent-builder/internal/model"
+)
+
+func TestInteractive_Project(t *testing.T) {
+	tests := []struct {
+		name       string
+		adkVersion string
+		answers    []Answer
+		check      func(t *testing.T, project *model.Project)
+	}{
+		{
+			name:       "sequential pipeline with defaults",
+			adkVersion: "1.0",
+			answers: []Answer{
+				{"Project name?", "research-assistant"},
+				{"Choose orchestration pattern:", "Sequential"},
+				{"Orchestrator name?", "ResearchCoordinator"},
+				{"Orchestrator description?", "Researches and writes"},
+				{"Choose model:", nil},
+				{"Sub-agent #1 name?", "Researcher"},
+				{"Agent type:", "LLM Agent"},
+				{"Instruction for Researcher?", "# template\nResearch the topic.\n"},
+				{"Output key?", "research_data"},
+				{"Choose model:", "gemini-2.5-pro"},
+				{"Add example prompts to evaluate Researcher?", false},
+				{"Add another sub-agent?", true},
+				{"Sub-agent #2 name?", "Writer"},
+				{"Agent type:", "LLM Agent"},
+				{"Instruction for Writer?", "Write an article from {research_data}."},
+				{"Output key?", "article"},
+				{"Choose model:", nil},
+				{"Add example prompts to evaluate Writer?", true},
+				{"Example prompt?", "Write about bees"},
+				{"Expected answer?", "An article about bees"},
+				{"Add another example?", false},
+				{"Add another sub-agent?", false},
+				{"Output directory?", nil},
+				{"Where will the agents call Gemini?", "Google AI Studio"},
+				{"Where should main.py keep sessions?", "In-memory"},
+				{"Memory across sessions?", "None"},
+				{"Where should artifacts (files agents save) be stored?", "In-memory"},
+				{"Generate example usage?", nil},
+				{"Generate evaluation set (eval/)?", nil},
+				{"Python packaging:", "uv"},
+				{"Generate pytest unit tests (tests/)?", nil},
+				{"Add Docker support?", nil},
+			},
+			check: func(t *testing.T, project *model.Project) {
+				if project.Name != "research-assistant" {
+					t.Errorf("Name = %v, want research-assistant", project.Name)
+				}
+				if project.OutputDir != "./research-assistant" {
+					t.Errorf("OutputDir = %v, want ./research-assistant", project.OutputDir)
+				}
+				orch := project.Orchestrator
+				if orch.Pattern != model.PatternSequential || orch.Model != model.DefaultModel {
+					t.Errorf("orchestrator = %v %v, want sequential %v", orch.Pattern, orch.Model, model.DefaultModel)
+				}
+				if len(orch.SubAgents) != 2 {
+					t.Fatalf("got %d sub-agents, want 2", len(orch.SubAgents))
+				}
+				researcher, writer := orch.SubAgents[0], orch.SubAgents[1]
+				if researcher.Instruction != "Research the topic." {
+					t.Errorf("Researcher instruction = %q, want comments stripped", researcher.Instruction)
+				}
+				if researcher.Model != "gemini-2.5-pro" || writer.Model != model.DefaultModel {
+					t.Errorf("models = %v, %v", researcher.Model, writer.Model)
+				}
+				if len(writer.Examples) != 1 || writer.Examples[0].Expected != "An article about bees" {
+					t.Errorf("Writer examples = %v", writer.Examples)
+				}
+				if !project.AddExample || !project.AddEval || !project.AddTests || project.AddDocker {
+					t.Errorf("options = example %v, eval %v, tests %v, docker %v",
+						project.AddExample, project.AddEval, project.AddTests, project.AddDocker)
+				}
+				if project.Packaging != model.PackagingUV {
+					t.Errorf("Packaging = %v, want uv", project.Packaging)
+				}
+				if project.ADKVersion != "1.0" {
+					t.Errorf("ADKVersion = %v, want 1.0", project.ADKVersion)
+				}
+			},
+		},
+		{
+			name:       "llm-coordinated asks descriptions and services",
+			adkVersion: "0.5",
+			answers: []Answer{
+				{"Project name?", "help-desk"},
+				{"Choose orchestration pattern:", "LLM-Coordinated"},
+				{"Orchestrator name?", "HelpDesk"},
+				{"Orchestrator description?", ""},
+				{"Choose model:", nil},
+				{"Sub-agent #1 name?", "Billing"},
+				{"Agent type:", "LLM Agent"},
+				{"Instruction for Billing?", "Answer billing questions."},
+				{"Description of Billing?", "Invoices and refunds"},
+				{"Output key?", ""},
+				{"Choose model:", nil},
+				{"Add example prompts to evaluate Billing?", nil},
+				{"Add another sub-agent?", true},
+				{"Sub-agent #2 name?", "Router"},
+				{"Agent type:", "Custom Agent"},
+				{"Description of Router?", "Everything else"},
+				{"Output key?", ""},
+				{"Choose model:", nil},
+				{"Add example prompts to evaluate Router?", nil},
+				{"Add another sub-agent?", false},
+				{"Output directory?", "out/help-desk"},
+				{"Where will the agents call Gemini?", "Vertex AI"},
+				{"Where should main.py keep sessions?", "Database"},
+				{"Database URL?", "postgresql://db/sessions"},
+				{"Memory across sessions?", "In-memory"},
+				{"Which agents should search memory?", []string{"Billing"}},
+				{"Where should artifacts (files agents save) be stored?", "Local filesystem"},
+				{"Generate example usage?", false},
+				{"Python packaging:", nil},
+				{"Generate pytest unit tests (tests/)?", false},
+				{"Add Docker support?", true},
+			},
+			check: func(t *testing.T, project *model.Project) {
+				orch := project.Orchestrator
+				if orch.Pattern != model.PatternLLMCoordinated {
+					t.Errorf("Pattern = %v, want llm-coordinated", orch.Pattern)
+				}
+				billing, router := orch.SubAgents[0], orch.SubAgents[1]
+				if billing.Description != "Invoices and refunds" || router.Description != "Everything else" {
+					t.Errorf("descriptions = %q, %q", billing.Description, router.Description)
+				}
+				if router.Type != model.AgentTypeCustom || router.Instruction != "" {
+					t.Errorf("Router = %v with instruction %q, want custom without one", router.Type, router.Instruction)
+				}
+				if !billing.Memory || router.Memory {
+					t.Errorf("memory = %v, %v, want only Billing", billing.Memory, router.Memory)
+				}
+				services := project.Services
+				if services.Session != model.SessionDatabase || services.DatabaseURL != "postgresql://db/sessions" {
+					t.Errorf("session = %v %v", services.Session, services.DatabaseURL)
+				}
+				if services.Memory != model.MemoryInMemory || services.Artifact != model.ArtifactLocal {
+					t.Errorf("memory = %v, artifact = %v", services.Memory, services.Artifact)
+				}
+				if project.Backend != model.BackendVertexAI || project.OutputDir != "out/help-desk" {
+					t.Errorf("backend = %v, output dir = %v", project.Backend, project.OutputDir)
+				}
+				if project.AddEval {
+					t.Error("AddEval should be false on ADK 0.5, which has no eval sets")
+				}
+				if project.Packaging != model.PackagingRequirements || !project.AddDocker {
+					t.Errorf("packaging = %v, docker = %v", project.Packaging, project.AddDocker)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := adk.Lookup(tt.adkVersion)
+			if err != nil {
+				t.Fatal(err)
+			}
+			scripted := NewScripted(tt.answers)
+
+			project, err := NewInteractive(scripted).Project(version)
+			if err != nil {
+				t.Fatalf("Project() error = %v", err)
+			}
+			if scripted.Remaining() != 0 {
+				t.Errorf("Remaining() = %d, want every answer used", scripted.Remaining())
+			}
+			if err := project.Validate(); err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+			tt.check(t, project)
+		})
+	}
+}
+
+func TestInteractive_Project_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		answers []Answer
+		errMsg  string
+	}{
+		{
+			name:    "invalid project name",
+			answers: []Answer{{"Project name?", "my project"}},
+			errMsg:  `failed to get project name: answer 1 to "Project name?": project name must contain only letters, numbers, hyphens, and underscores`,
+		},
+		{
+			name: "unknown option",
+			answers: []Answer{
+				{"Project name?", "my-project"},
+				{"Choose orchestration pattern:", "Round robin"},
+			},
+			errMsg: `failed to get orchestration pattern: answer 2 to "Choose orchestration pattern:": "Round robin" is not one of`,
+		},
+		{
+			name: "taken sub-agent name",
+			answers: []Answer{
+				{"Project name?", "my-project"},
+				{"Choose orchestration pattern:", "Parallel"},
+				{"Orchestrator name?", "Coordinator"},
+				{"Orchestrator description?", ""},
+				{"Choose model:", nil},
+				{"Sub-agent #1 name?", "Coordinator"},
+			},
+			errMsg: `failed to get agent name: answer 6 to "Sub-agent #1 name?"`,
+		},
+		{
+			name: "artifact placeholder without a name",
+			answers: []Answer{
+				{"Project name?", "my-project"},
+				{"Choose orchestration pattern:", "Sequential"},
+				{"Orchestrator name?", "Coordinator"},
+				{"Orchestrator description?", ""},
+				{"Choose model:", nil},
+				{"Sub-agent #1 name?", "Researcher"},
+				{"Agent type:", "LLM Agent"},
+				{"Instruction for Researcher?", "Research {artifact.}."},
+			},
+			errMsg: `failed to get agent instruction: answer 8 to "Instruction for Researcher?": placeholder {artifact.} names no artifact`,
+		},
+		{
+			name: "vertex ai session without the vertex ai backend",
+			answers: []Answer{
+				{"Project name?", "my-project"},
+				{"Choose orchestration pattern:", "Sequential"},
+				{"Orchestrator name?", "Coordinator"},
+				{"Orchestrator description?", ""},
+				{"Choose model:", nil},
+				{"Sub-agent #1 name?", "Researcher"},
+				{"Agent type:", "LLM Agent"},
+				{"Instruction for Researcher?", "Research the topic."},
+				{"Output key?", "research_data"},
+				{"Choose model:", nil},
+				{"Add example prompts to evaluate Researcher?", false},
+				{"Add another sub-agent?", false},
+				{"Output directory?", nil},
+				{"Where will the agents call Gemini?", "Google AI Studio"},
+				{"Where should main.py keep sessions?", "Vertex AI"},
+			},
+			errMsg: `failed to get session service: answer 15 to "Where should main.py keep sessions?": "Vertex AI" is not one of`,
+		},
+		{
+			name: "script out of step",
+			answers: []Answer{
+				{"Project name?", "my-project"},
+				{"Orchestrator name?", "Coordinator"},
+			},
+			errMsg: `failed to get orchestration pattern: answer 2 is to "Orchestrator name?", but the question is "Choose orchestration pattern:"`,
+		},
+		{
+			name:    "script runs out",
+			answers: []Answer{{"Project name?", "my-project"}},
+			errMsg:  `failed to get orchestration pattern: no answer to "Choose orchestration pattern:": the script has 1 answers`,
+		},
+	}
+
+	version, _ := adk.Lookup(adk.Default)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewInteractive(NewScripted(tt.answers)).Project(version)
+			if err == nil {
+				t.Fatal("Project() error = nil, want error")
+			}
+			if !strings.HasPrefix(err.Error(), tt.errMsg) {
+				t.Errorf("Project() error = %v, want prefix %v", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestInteractive_Agent(t *testing.T) {
+	answers := []Answer{
+		{"Sub-agent #1 name?", "Summarizer"},
+		{"Agent type:", "LLM Agent"},
+		{"Instruction for Summarizer?", "Summarize the input."},
+		{"Output key?", "summary"},
+		{"Choose model:", "gemini-2.5-flash-lite"},
+	}
+
+	agent, err := NewInteractive(NewScripted(answers)).Agent()
+	if err != nil {
+		t.Fatalf("Agent() error = %v", err)
+	}
+	if agent.Name != "Summarizer" || agent.Type != model.AgentTypeLLM {
+		t.Errorf("agent = %v %v, want Summarizer llm", agent.Name, agent.Type)
+	}
+	if agent.Instruction != "Summarize the input." || agent.OutputKey != "summary" || agent.Model != "gemini-2.5-flash-lite" {
+		t.Errorf("agent = %q, %q, %q", agent.Instruction, agent.OutputKey, agent.Model)
+	}
+}
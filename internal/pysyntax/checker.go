@@ -0,0 +1,69 @@
+package pysyntax
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+type Checker interface {
+	Check(filename, src string) error
+}
+
+// NewChecker returns a checker backed by python3 when it is on PATH, and the
+// built-in tokenizer otherwise.
+func NewChecker() Checker {
+	if path, err := exec.LookPath("python3"); err == nil {
+		return &PythonChecker{Path: path}
+	}
+	return TokenChecker{}
+}
+
+type TokenChecker struct{}
+
+func (TokenChecker) Check(filename, src string) error {
+	_, err := Tokenize(src)
+	return err
+}
+
+const compileScript = `import sys
+try:
+    compile(sys.stdin.read(), sys.argv[1], "exec")
+except SyntaxError as e:
+    print(e.lineno or 0)
+    print(e.msg)
+    sys.exit(3)
+`
+
+type PythonChecker struct {
+	Path string
+}
+
+func (c *PythonChecker) Check(filename, src string) error {
+	cmd := exec.Command(c.Path, "-c", compileScript, filename)
+	cmd.Stdin = strings.NewReader(src)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+
+	err := cmd.Run()
+	if err == nil {
+		return nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 3 {
+		lines := strings.SplitN(strings.TrimSpace(stdout.String()), "\n", 2)
+		line, _ := strconv.Atoi(lines[0])
+		msg := "invalid syntax"
+		if len(lines) == 2 {
+			msg = lines[1]
+		}
+		return &Error{Line: line, Msg: msg}
+	}
+
+	// python3 exists but could not run the check; the tokenizer is still
+	// better than nothing.
+	return TokenChecker{}.Check(filename, src)
+}
@@ -0,0 +1,168 @@
+package pysyntax
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+)
+
+var checkTests = []struct {
+	name     string
+	src      string
+	wantErr  bool
+	wantLine int
+}{
+	{
+		name: "valid agent module",
+		src: `from google.adk.agents import LlmAgent
+
+agent = LlmAgent(
+    name="researcher",
+    model="gemini-2.5-flash",
+    instruction="Research the topic",
+)
+`,
+		wantErr: false,
+	},
+	{
+		name:    "triple-quoted string spanning lines",
+		src:     "x = \"\"\"first\nsecond (\n\"\"\"\n",
+		wantErr: false,
+	},
+	{
+		name:    "brackets inside strings and comments are ignored",
+		src:     "x = \"(\"  # )\ny = ['[']\n",
+		wantErr: false,
+	},
+	{
+		name:    "line continuation",
+		src:     "x = 1 + \\\n    2\n",
+		wantErr: false,
+	},
+	{
+		name:     "unclosed parenthesis",
+		src:      "agent = LlmAgent(\n    name=\"a\",\n\nroot_agent = agent\n",
+		wantErr:  true,
+		wantLine: 1,
+	},
+	{
+		name:     "unmatched closing bracket",
+		src:      "x = 1\ny = [1, 2]]\n",
+		wantErr:  true,
+		wantLine: 2,
+	},
+	{
+		name:     "unterminated string from embedded quote",
+		src:      "x = 1\ninstruction=\"Say \"hi\"\"\"\n",
+		wantErr:  true,
+		wantLine: 2,
+	},
+	{
+		name:     "newline inside single-quoted string",
+		src:      "instruction=\"first line\nsecond line\"\n",
+		wantErr:  true,
+		wantLine: 1,
+	},
+}
+
+func TestTokenChecker_Check(t *testing.T) {
+	for _, tt := range checkTests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertCheck(t, TokenChecker{}.Check("agent.py", tt.src), tt.wantErr, tt.wantLine)
+		})
+	}
+}
+
+func TestPythonChecker_Check(t *testing.T) {
+	path, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 not available")
+	}
+	checker := &PythonChecker{Path: path}
+
+	for _, tt := range checkTests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertCheck(t, checker.Check("agent.py", tt.src), tt.wantErr, tt.wantLine)
+		})
+	}
+}
+
+func assertCheck(t *testing.T, err error, wantErr bool, wantLine int) {
+	t.Helper()
+
+	if (err != nil) != wantErr {
+		t.Fatalf("Check() error = %v, wantErr %v", err, wantErr)
+	}
+	if !wantErr {
+		return
+	}
+
+	var syntaxErr *Error
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("Check() error type = %T, want *Error", err)
+	}
+	if syntaxErr.Line != wantLine {
+		t.Errorf("Check() error line = %d, want %d (%v)", syntaxErr.Line, wantLine, err)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tokens, err := Tokenize("agent = LlmAgent(name=\"a\", sub_agents=[b, c])\n")
+	if err != nil {
+		t.Fatalf("Tokenize() error = %v", err)
+	}
+
+	want := []struct {
+		kind TokenKind
+		text string
+	}{
+		{TokenName, "agent"}, {TokenOp, "="}, {TokenName, "LlmAgent"}, {TokenOp, "("},
+		{TokenName, "name"}, {TokenOp, "="}, {TokenString, `"a"`}, {TokenOp, ","},
+		{TokenName, "sub_agents"}, {TokenOp, "="}, {TokenOp, "["}, {TokenName, "b"},
+		{TokenOp, ","}, {TokenName, "c"}, {TokenOp, "]"}, {TokenOp, ")"},
+		{TokenNewline, "\n"}, {TokenEOF, ""},
+	}
+
+	if len(tokens) != len(want) {
+		t.Fatalf("Tokenize() returned %d tokens, want %d: %v", len(tokens), len(want), tokens)
+	}
+	for i, w := range want {
+		if tokens[i].Kind != w.kind || tokens[i].Text != w.text {
+			t.Errorf("token %d = %v %q, want %v %q", i, tokens[i].Kind, tokens[i].Text, w.kind, w.text)
+		}
+	}
+}
+
+func TestUnquote(t *testing.T) {
+	tests := []struct {
+		lit     string
+		want    string
+		wantErr bool
+	}{
+		{lit: `"plain"`, want: "plain"},
+		{lit: `'single'`, want: "single"},
+		{lit: `"say \"hi\"\n"`, want: "say \"hi\"\n"},
+		{lit: `"""first
+second"""`, want: "first\nsecond"},
+		{lit: `r"C:\path\n"`, want: `C:\path\n`},
+		{lit: `"caf\u00e9 \x41\101"`, want: "café AA"},
+		{lit: `"keeps \d unknown escapes"`, want: `keeps \d unknown escapes`},
+		{lit: `""`, want: ""},
+		{lit: `f"Hello {name}"`, wantErr: true},
+		{lit: `b"bytes"`, wantErr: true},
+		{lit: `"\u12"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lit, func(t *testing.T) {
+			got, err := Unquote(tt.lit)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unquote() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Unquote() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package pysyntax
+
+import (
+	"fmt"
+	"strings"
+)
+
+type TokenKind int
+
+const (
+	TokenName TokenKind = iota
+	TokenNumber
+	TokenString
+	TokenOp
+	TokenNewline
+	TokenEOF
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case TokenName:
+		return "name"
+	case TokenNumber:
+		return "number"
+	case TokenString:
+		return "string"
+	case TokenOp:
+		return "op"
+	case TokenNewline:
+		return "newline"
+	case TokenEOF:
+		return "EOF"
+	default:
+		return fmt.Sprintf("TokenKind(%d)", int(k))
+	}
+}
+
+type Token struct {
+	Kind TokenKind
+	Text string
+	Line int
+}
+
+type Error struct {
+	Line int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+var operators = []string{
+	"**=", "//=", ">>=", "<<=", "...",
+	"->", ":=", "==", "!=", "<=", ">=", "**", "//", "<<", ">>",
+	"+=", "-=", "*=", "/=", "%=", "@=", "&=", "|=", "^=",
+	"+", "-", "*", "/", "%", "@", "&", "|", "^", "~", "<", ">",
+	"(", ")", "[", "]", "{", "}", ",", ":", ";", ".", "=",
+}
+
+var closers = map[byte]byte{')': '(', ']': '[', '}': '{'}
+
+type bracket struct {
+	char byte
+	line int
+}
+
+type tokenizer struct {
+	src    string
+	pos    int
+	line   int
+	stack  []bracket
+	tokens []Token
+}
+
+// Tokenize splits Python source into tokens. It understands enough of the
+// lexical grammar (strings, comments, brackets, line continuations) to find
+// the errors a template is likely to introduce, but it is not a parser.
+func Tokenize(src string) ([]Token, error) {
+	t := &tokenizer{src: src, line: 1}
+	if err := t.run(); err != nil {
+		return nil, err
+	}
+	return t.tokens, nil
+}
+
+func (t *tokenizer) emit(kind TokenKind, text string, line int) {
+	t.tokens = append(t.tokens, Token{Kind: kind, Text: text, Line: line})
+}
+
+func (t *tokenizer) run() error {
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		switch {
+		case c == '\n':
+			if len(t.stack) == 0 && len(t.tokens) > 0 && t.tokens[len(t.tokens)-1].Kind != TokenNewline {
+				t.emit(TokenNewline, "\n", t.line)
+			}
+			t.line++
+			t.pos++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f':
+			t.pos++
+		case c == '#':
+			for t.pos < len(t.src) && t.src[t.pos] != '\n' {
+				t.pos++
+			}
+		case c == '\\':
+			if t.pos+1 < len(t.src) && t.src[t.pos+1] == '\n' {
+				t.pos += 2
+				t.line++
+				continue
+			}
+			if t.pos+2 < len(t.src) && t.src[t.pos+1] == '\r' && t.src[t.pos+2] == '\n' {
+				t.pos += 3
+				t.line++
+				continue
+			}
+			return &Error{Line: t.line, Msg: "unexpected character after line continuation character"}
+		case isStringStart(t.src[t.pos:]):
+			if err := t.readString(); err != nil {
+				return err
+			}
+		case isNameStart(c):
+			start := t.pos
+			for t.pos < len(t.src) && isNameChar(t.src[t.pos]) {
+				t.pos++
+			}
+			t.emit(TokenName, t.src[start:t.pos], t.line)
+		case isDigit(c) || (c == '.' && t.pos+1 < len(t.src) && isDigit(t.src[t.pos+1])):
+			start := t.pos
+			for t.pos < len(t.src) && (isNameChar(t.src[t.pos]) || t.src[t.pos] == '.' ||
+				((t.src[t.pos] == '+' || t.src[t.pos] == '-') && (t.src[t.pos-1] == 'e' || t.src[t.pos-1] == 'E'))) {
+				t.pos++
+			}
+			t.emit(TokenNumber, t.src[start:t.pos], t.line)
+		default:
+			if err := t.readOperator(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(t.stack) > 0 {
+		open := t.stack[len(t.stack)-1]
+		return &Error{Line: open.line, Msg: fmt.Sprintf("'%c' was never closed", open.char)}
+	}
+	if len(t.tokens) > 0 && t.tokens[len(t.tokens)-1].Kind != TokenNewline {
+		t.emit(TokenNewline, "", t.line)
+	}
+	t.emit(TokenEOF, "", t.line)
+	return nil
+}
+
+func (t *tokenizer) readOperator() error {
+	rest := t.src[t.pos:]
+	for _, op := range operators {
+		if !strings.HasPrefix(rest, op) {
+			continue
+		}
+		switch op[0] {
+		case '(', '[', '{':
+			t.stack = append(t.stack, bracket{char: op[0], line: t.line})
+		case ')', ']', '}':
+			if len(t.stack) == 0 {
+				return &Error{Line: t.line, Msg: fmt.Sprintf("unmatched '%s'", op)}
+			}
+			open := t.stack[len(t.stack)-1]
+			if open.char != closers[op[0]] {
+				return &Error{Line: t.line, Msg: fmt.Sprintf("closing parenthesis '%s' does not match opening parenthesis '%c' on line %d", op, open.char, open.line)}
+			}
+			t.stack = t.stack[:len(t.stack)-1]
+		}
+		t.emit(TokenOp, op, t.line)
+		t.pos += len(op)
+		return nil
+	}
+	return &Error{Line: t.line, Msg: fmt.Sprintf("invalid character '%c'", rest[0])}
+}
+
+func (t *tokenizer) readString() error {
+	start := t.pos
+	startLine := t.line
+	for t.src[t.pos] != '\'' && t.src[t.pos] != '"' {
+		t.pos++
+	}
+	quote := t.src[t.pos]
+	triple := strings.HasPrefix(t.src[t.pos:], strings.Repeat(string(quote), 3))
+	if triple {
+		t.pos += 3
+	} else {
+		t.pos++
+	}
+
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		switch {
+		case c == '\\':
+			if t.pos+1 < len(t.src) && t.src[t.pos+1] == '\n' {
+				t.line++
+			}
+			t.pos += 2
+			continue
+		case c == '\n':
+			if !triple {
+				return &Error{Line: startLine, Msg: "unterminated string literal"}
+			}
+			t.line++
+		case c == quote:
+			if !triple {
+				t.pos++
+				t.emit(TokenString, t.src[start:t.pos], startLine)
+				return nil
+			}
+			if strings.HasPrefix(t.src[t.pos:], strings.Repeat(string(quote), 3)) {
+				t.pos += 3
+				t.emit(TokenString, t.src[start:t.pos], startLine)
+				return nil
+			}
+		}
+		t.pos++
+	}
+
+	if triple {
+		return &Error{Line: startLine, Msg: "unterminated triple-quoted string literal"}
+	}
+	return &Error{Line: startLine, Msg: "unterminated string literal"}
+}
+
+func isStringStart(s string) bool {
+	for i := 0; i < len(s) && i < 3; i++ {
+		switch s[i] {
+		case '\'', '"':
+			return true
+		case 'r', 'R', 'b', 'B', 'u', 'U', 'f', 'F':
+			continue
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || isDigit(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
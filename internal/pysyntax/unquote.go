@@ -0,0 +1,93 @@
+package pysyntax
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Unquote returns the value of a Python string literal token. Raw strings are
+// supported; bytes and f-strings are not, since their value is not a plain
+// string known before the program runs.
+func Unquote(lit string) (string, error) {
+	i := strings.IndexAny(lit, `'"`)
+	if i < 0 {
+		return "", fmt.Errorf("%s is not a string literal", lit)
+	}
+	prefix := strings.ToLower(lit[:i])
+	if strings.ContainsAny(prefix, "bf") {
+		return "", fmt.Errorf("%s-string literals cannot be evaluated statically", prefix)
+	}
+	raw := strings.Contains(prefix, "r")
+
+	body := lit[i:]
+	quote := body[:1]
+	if strings.HasPrefix(body, strings.Repeat(quote, 3)) && len(body) >= 6 {
+		quote = strings.Repeat(quote, 3)
+	}
+	if len(body) < 2*len(quote) || !strings.HasSuffix(body, quote) {
+		return "", fmt.Errorf("unterminated string literal %s", lit)
+	}
+	body = body[len(quote) : len(body)-len(quote)]
+
+	if raw {
+		return body, nil
+	}
+	return unescape(body)
+}
+
+func unescape(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i+1 == len(s) {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch e := s[i]; e {
+		case '\n':
+		case '\\', '\'', '"':
+			b.WriteByte(e)
+		case 'a':
+			b.WriteByte('\a')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case 'x', 'u', 'U':
+			size := map[byte]int{'x': 2, 'u': 4, 'U': 8}[e]
+			if i+size >= len(s) {
+				return "", fmt.Errorf("truncated \\%c escape", e)
+			}
+			n, err := strconv.ParseUint(s[i+1:i+1+size], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(n)) {
+				return "", fmt.Errorf("invalid \\%c escape", e)
+			}
+			b.WriteRune(rune(n))
+			i += size
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			end := i + 1
+			for end < len(s) && end < i+3 && s[end] >= '0' && s[end] <= '7' {
+				end++
+			}
+			n, _ := strconv.ParseUint(s[i:end], 8, 32)
+			b.WriteRune(rune(n))
+			i = end - 1
+		default:
+			// Python keeps unknown escapes as written.
+			b.WriteByte('\\')
+			b.WriteByte(e)
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,153 @@
+package spec
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const apiVersionPrefix = "agent-builder/v"
+
+// FormatVersion is the spec format this agent-builder writes. Bump it, and
+// append a migration, whenever a field is added, renamed or restructured in a
+// way older specs need rewriting for.
+const FormatVersion = 2
+
+// APIVersion is the apiVersion value of specs in the current format.
+var APIVersion = FormatName(FormatVersion)
+
+// Step describes one migration applied to a spec.
+type Step struct {
+	From        int
+	To          int
+	Description string
+}
+
+func (s Step) String() string {
+	return fmt.Sprintf("%s → %s: %s", FormatName(s.From), FormatName(s.To), s.Description)
+}
+
+type migration struct {
+	description string
+	apply       func(root *yaml.Node) error
+}
+
+// migrations[i] upgrades a spec from format i to format i+1. Specs written
+// before apiVersion existed are format 0.
+var migrations = []migration{
+	{
+		description: "record the spec format in apiVersion and point editors at the JSON Schema",
+		apply: func(root *yaml.Node) error {
+			if mappingValue(root, "$schema") == nil {
+				insertKey(root, 0, "$schema", SchemaURL)
+			}
+			return nil
+		},
+	},
+	{
+		// Format 2 adds optional fields only, so a format 1 spec reads the
+		// same; the new version keeps a spec that uses them from being
+		// loaded by an agent-builder that would reject them.
+		description: "allow schemas, generation config, callbacks, descriptions, transfer controls, services and prompt files",
+		apply:       func(root *yaml.Node) error { return nil },
+	},
+}
+
+// Migrate upgrades a spec or manifest to the current format. It returns the
+// rewritten document and the steps applied; data is returned unchanged when
+// it is already current. Comments and key order are preserved.
+func Migrate(data []byte) ([]byte, []Step, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("spec must be a mapping")
+	}
+	root := doc.Content[0]
+
+	from, err := formatOf(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	if from == FormatVersion {
+		return data, nil, nil
+	}
+
+	var steps []Step
+	for version := from; version < FormatVersion; version++ {
+		m := migrations[version]
+		if err := m.apply(root); err != nil {
+			return nil, nil, fmt.Errorf("failed to migrate spec from %s: %w", FormatName(version), err)
+		}
+		setAPIVersion(root, FormatName(version+1))
+		steps = append(steps, Step{From: version, To: version + 1, Description: m.description})
+	}
+
+	migrated, err := encode(&doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return migrated, steps, nil
+}
+
+func formatOf(root *yaml.Node) (int, error) {
+	node := mappingValue(root, "apiVersion")
+	if node == nil {
+		return 0, nil
+	}
+
+	version, err := strconv.Atoi(strings.TrimPrefix(node.Value, apiVersionPrefix))
+	if err != nil || !strings.HasPrefix(node.Value, apiVersionPrefix) || version < 1 {
+		return 0, fmt.Errorf("unknown apiVersion %q (want %s)", node.Value, APIVersion)
+	}
+	if version > FormatVersion {
+		return 0, fmt.Errorf("apiVersion %s is newer than this agent-builder supports (%s); upgrade agent-builder", node.Value, APIVersion)
+	}
+	return version, nil
+}
+
+// FormatName returns the apiVersion value of a spec format.
+func FormatName(version int) string {
+	return apiVersionPrefix + strconv.Itoa(version)
+}
+
+// setAPIVersion sets apiVersion, adding it right after $schema when missing.
+func setAPIVersion(root *yaml.Node, value string) {
+	if node := mappingValue(root, "apiVersion"); node != nil {
+		node.Value = value
+		return
+	}
+	index := 0
+	if mappingValue(root, "$schema") != nil {
+		index = 1
+	}
+	insertKey(root, index, "apiVersion", value)
+}
+
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// insertKey adds a string entry as the index-th key of mapping. Comments
+// attached to the key it displaces stay at the top of the mapping.
+func insertKey(mapping *yaml.Node, index int, key, value string) {
+	keyNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}
+	valueNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+
+	at := index * 2
+	if at == 0 && len(mapping.Content) > 0 {
+		keyNode.HeadComment = mapping.Content[0].HeadComment
+		mapping.Content[0].HeadComment = ""
+	}
+	content := append([]*yaml.Node{}, mapping.Content[:at]...)
+	content = append(content, keyNode, valueNode)
+	mapping.Content = append(content, mapping.Content[at:]...)
+}
@@ -0,0 +1,267 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/doji-co/agent-builder/internal/adk"
+	"github.com/doji-co/agent-builder/internal/model"
+)
+
+// SchemaURL is where the published schema lives; specs and manifests point
+// editors at it through their $schema key.
+const SchemaURL = "https://raw.githubusercontent.com/doji-co/agent-builder/main/schema/agent-builder.schema.json"
+
+// enumValues lists the allowed values of the model's string types.
+func enumValues() map[reflect.Type][]string {
+	enums := make(map[reflect.Type][]string)
+	add := func(t reflect.Type, values interface{}) {
+		v := reflect.ValueOf(values)
+		for i := 0; i < v.Len(); i++ {
+			enums[t] = append(enums[t], v.Index(i).String())
+		}
+	}
+	add(reflect.TypeOf(model.OrchestrationPattern("")), model.OrchestrationPatterns)
+	add(reflect.TypeOf(model.AgentType("")), model.AgentTypes)
+	add(reflect.TypeOf(model.Packaging("")), model.Packagings)
+	add(reflect.TypeOf(model.Backend("")), model.Backends)
+	add(reflect.TypeOf(model.SessionService("")), model.SessionServices)
+	add(reflect.TypeOf(model.MemoryService("")), model.MemoryServices)
+	add(reflect.TypeOf(model.ArtifactService("")), model.ArtifactServices)
+	add(reflect.TypeOf(model.FieldType("")), model.FieldTypes)
+	add(reflect.TypeOf(model.HarmCategory("")), model.HarmCategories)
+	add(reflect.TypeOf(model.HarmThreshold("")), model.HarmThresholds)
+	add(reflect.TypeOf(model.Callback("")), append(append([]model.Callback{}, model.CallbackHooks...), model.CallbackRecipes...))
+	return enums
+}
+
+// keyEnums lists the allowed values of plain string fields, by key.
+func keyEnums() map[string][]string {
+	return map[string][]string{
+		"adkVersion": adk.Names(),
+		"apiVersion": {APIVersion},
+	}
+}
+
+// keySuggestions lists the usual values of plain string fields that accept
+// others too: any model ADK knows can be named. The schema offers them as an
+// enum next to a plain string, so editors complete them without rejecting
+// the rest.
+func keySuggestions() map[string][]string {
+	return map[string][]string{
+		"model": model.AvailableModels,
+	}
+}
+
+var descriptions = map[string]string{
+	"$schema":      "JSON Schema this file is validated against.",
+	"apiVersion":   "Spec format version. Older formats are migrated on load; run agent-builder migrate to rewrite the file.",
+	"files":        "Hash of every generated file, used by agent-builder doctor to report local edits.",
+	"name":         "Name of the project or agent. Agent names become Python package and variable names.",
+	"adkVersion":   "ADK release line the generated code targets.",
+	"backend":      "Where the agents call Gemini: Google AI Studio or Vertex AI.",
+	"packaging":    "How the generated project declares its dependencies.",
+	"addExample":   "Generate main.py to run the agents from the command line.",
+	"addReadme":    "Generate README.md.",
+	"addDocker":    "Add Docker support.",
+	"addEval":      "Generate an ADK evaluation set and pytest harness in eval/.",
+	"addTests":     "Generate pytest unit tests that run the agents against a fake model.",
+	"orchestrator": "Root agent that coordinates the sub-agents.",
+	"pattern":      "How the orchestrator runs its sub-agents.",
+	"description":  "What the agent does.",
+	"model":        "Gemini model the agent calls.",
+	"subAgents":    "Agents the orchestrator coordinates, in order.",
+	"type":         "Kind of agent.",
+	"instruction":  "Instruction given to the model. {key} is replaced with a session state value written earlier, {artifact.name} with an artifact's text; end a placeholder with ? when it may be missing.",
+	"outputKey":    "Session state key the agent's final response is stored under: an identifier, optionally prefixed with app:, user: or temp:.",
+	"examples":     "Example prompts and expected answers that seed the evaluation set.",
+	"prompt":       "A message a user might send.",
+	"expected":     "The response you would accept.",
+	"inputSchema":  "Structured input the agent accepts when another agent calls it as a tool.",
+	"outputSchema": "Structured output the agent must reply with. Agents with an output schema cannot use tools.",
+	"tools":        "Python functions the agent can call; stubs are generated in the agent's tools.py.",
+	"jsonSchema":   "JSON Schema object describing the data, for nested structures. Fields follow the order of required, then alphabetical.",
+	"list":         "The field holds a list of values of its type.",
+	"optional":     "The field may be omitted.",
+	"enum":         "Allowed values of a string field.",
+
+	"generationConfig": "Model generation settings. On the orchestrator they apply to its own model calls and are the defaults sub-agents inherit; a sub-agent's settings override them field by field.",
+	"temperature":      "Sampling temperature, from 0 to 2.",
+	"topP":             "Nucleus sampling probability mass, from 0 to 1.",
+	"topK":             "Number of most likely tokens to sample from.",
+	"maxOutputTokens":  "Maximum number of tokens in a reply.",
+	"stopSequences":    "Up to 5 strings that end the reply when generated.",
+	"safetySettings":   "Blocking thresholds per harm category. Sub-agents override inherited settings category by category.",
+	"thinkingBudget":   "Tokens the model may spend thinking (Gemini 2.5): -1 for dynamic, 0 to turn thinking off.",
+	"category":         "Harm category the setting applies to.",
+	"threshold":        "Probability of harm at which content is blocked.",
+
+	"disallowTransferToParent": "Under an llm-coordinated orchestrator, stop the agent from handing the conversation back to the coordinator.",
+	"disallowTransferToPeers":  "Under an llm-coordinated orchestrator, stop the agent from handing the conversation to its sibling agents.",
+
+	"memory": "Give the agent ADK's load_memory tool to search past sessions. Needs a memory service.",
+
+	"services":    "Where the generated main.py keeps sessions, memory and artifacts.",
+	"session":     "Session service: in-memory, database (SQLite by default) or vertex-ai (needs the vertex-ai backend and an Agent Engine).",
+	"databaseUrl": "SQLAlchemy URL of the database session service. Defaults to sqlite:///./sessions.db; DATABASE_URL overrides it.",
+	"ragCorpus":   "Resource name of the RAG corpus the vertex-ai-rag memory service uses. RAG_CORPUS overrides it.",
+	"artifact":    "Artifact service: in-memory, local (files on disk) or gcs.",
+	"artifactDir": "Directory of the local artifact service, relative to the project. Defaults to artifacts; ARTIFACT_DIR overrides it.",
+	"bucket":      "Bucket of the gcs artifact service. ARTIFACT_BUCKET overrides it.",
+
+	"promptFile":        "Write the instruction to prompts/<agent>.md, which the generated agent reads at import time, instead of inlining it in agent.py.",
+	"staticInstruction": "Instruction sent verbatim, without placeholders, ahead of the instruction so that it can be cached. Needs ADK 1.15.",
+	"globalInstruction": "Instruction every agent in the tree receives. Only an llm-coordinated orchestrator can set one; it is read before any sub-agent writes to state, so its placeholders should end in ?.",
+
+	"callbacks": "ADK callbacks generated in the agent's callbacks.py: hooks (before-agent, after-model, ...) become stubs to fill in, recipes (request-logging, input-blocklist, output-length-limit, state-snapshot) are generated ready to use.",
+
+	// Keys whose meaning depends on the type they appear in.
+	"Schema.name":       "Pydantic class name. Defaults to the agent name followed by Input or Output.",
+	"Schema.fields":     "Fields of the data, in order.",
+	"Field.name":        "Python attribute name of the field.",
+	"Field.type":        "Type of the field's values.",
+	"Field.description": "What the field holds; passed to the model.",
+	"Services.memory":   "Memory service: none, in-memory or vertex-ai-rag (needs the vertex-ai backend).",
+	"Agent.description": "What requests the agent handles. An llm-coordinated orchestrator routes requests by it.",
+}
+
+var required = map[reflect.Type][]string{
+	reflect.TypeOf(Manifest{}):            {"name", "orchestrator"},
+	reflect.TypeOf(model.Orchestrator{}):  {"name", "pattern", "subAgents"},
+	reflect.TypeOf(model.Agent{}):         {"name", "type"},
+	reflect.TypeOf(model.Example{}):       {"prompt", "expected"},
+	reflect.TypeOf(model.Field{}):         {"name", "type"},
+	reflect.TypeOf(model.SafetySetting{}): {"category", "threshold"},
+}
+
+// Schema returns the JSON Schema of the spec and manifest format, derived from
+// the yaml tags of the model types.
+func Schema() ([]byte, error) {
+	b := &schemaBuilder{enums: enumValues(), keyEnums: keyEnums(), keySuggestions: keySuggestions()}
+
+	root := b.object(reflect.TypeOf(Manifest{}))
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = SchemaURL
+	root["title"] = "agent-builder project spec"
+
+	addDefaults(root, reflect.ValueOf(*model.NewProject("", nil)))
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(root); err != nil {
+		return nil, fmt.Errorf("failed to encode schema: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// addDefaults records the values of defaults as the defaults of the object
+// schema's properties. Nested structs get defaults on their own properties,
+// and empty values are left out.
+func addDefaults(object map[string]interface{}, defaults reflect.Value) {
+	properties := object["properties"].(map[string]interface{})
+	for i := 0; i < defaults.NumField(); i++ {
+		key := yamlKey(defaults.Type().Field(i))
+		field := defaults.Field(i)
+		prop, ok := properties[key].(map[string]interface{})
+		if !ok || key == "name" || field.Kind() == reflect.Ptr {
+			continue
+		}
+		if field.Kind() == reflect.Struct {
+			addDefaults(prop, field)
+		} else if field.Kind() == reflect.Bool || !field.IsZero() {
+			prop["default"] = field.Interface()
+		}
+	}
+}
+
+type schemaBuilder struct {
+	enums          map[reflect.Type][]string
+	keyEnums       map[string][]string
+	keySuggestions map[string][]string
+}
+
+func (b *schemaBuilder) object(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	b.addFields(t, properties)
+
+	schema := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if req := required[t]; len(req) > 0 {
+		schema["required"] = req
+	}
+	return schema
+}
+
+func (b *schemaBuilder) addFields(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if strings.Contains(field.Tag.Get("yaml"), ",inline") {
+			b.addFields(field.Type, properties)
+			continue
+		}
+		key := yamlKey(field)
+		if key == "-" || key == "" {
+			continue
+		}
+
+		prop := b.typeSchema(field.Type, key)
+		if description, ok := descriptions[t.Name()+"."+key]; ok {
+			prop["description"] = description
+		} else if description, ok := descriptions[key]; ok {
+			prop["description"] = description
+		}
+		properties[key] = prop
+	}
+}
+
+func (b *schemaBuilder) typeSchema(t reflect.Type, key string) map[string]interface{} {
+	if values, ok := b.enums[t]; ok {
+		return map[string]interface{}{"type": "string", "enum": values}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return b.typeSchema(t.Elem(), key)
+	case reflect.String:
+		if values, ok := b.keyEnums[key]; ok {
+			return map[string]interface{}{"type": "string", "enum": values}
+		}
+		if values, ok := b.keySuggestions[key]; ok {
+			return map[string]interface{}{"anyOf": []interface{}{
+				map[string]interface{}{"type": "string", "enum": values},
+				map[string]interface{}{"type": "string"},
+			}}
+		}
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": b.typeSchema(t.Elem(), key)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.typeSchema(t.Elem(), key)}
+	case reflect.Struct:
+		return b.object(t)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func yamlKey(field reflect.StructField) string {
+	tag := field.Tag.Get("yaml")
+	if tag == "" {
+		return field.Name
+	}
+	return strings.Split(tag, ",")[0]
+}
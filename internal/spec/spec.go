@@ -0,0 +1,166 @@
+package spec
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/doji-co/agent-builder/internal/generator"
+	"github.com/doji-co/agent-builder/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestName is the file a generated project's manifest is written to.
+const ManifestName = "agent-builder.yaml"
+
+const manifestHeader = `# Generated by agent-builder. The files section records a hash of every
+# generated file so that "agent-builder doctor" can report local edits.
+`
+
+// Manifest is a project spec plus the hash of every file generated from it.
+type Manifest struct {
+	Schema        string `yaml:"$schema,omitempty"`
+	APIVersion    string `yaml:"apiVersion"`
+	model.Project `yaml:",inline"`
+	Files         map[string]string `yaml:"files,omitempty"`
+
+	// Migrated lists the migrations applied while loading an older format.
+	Migrated []Step `yaml:"-"`
+}
+
+func Marshal(project *model.Project) ([]byte, error) {
+	return encode(&Manifest{Schema: SchemaURL, APIVersion: APIVersion, Project: *project})
+}
+
+// Unmarshal parses a spec, migrating it from an older format if needed.
+// Fields the spec leaves out keep the defaults of model.NewProject, models
+// left out are model.DefaultModel, and unknown fields are rejected.
+func Unmarshal(data []byte) (*model.Project, error) {
+	manifest, err := unmarshalManifest(data)
+	if err != nil {
+		return nil, err
+	}
+	return &manifest.Project, nil
+}
+
+// Load reads the spec at path. Specs in an older format are migrated in
+// memory; the steps applied are returned so the caller can suggest running
+// "agent-builder migrate".
+func Load(path string) (*model.Project, []Step, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	manifest, err := unmarshalManifest(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &manifest.Project, manifest.Migrated, nil
+}
+
+func Save(path string, project *model.Project) error {
+	data, err := Marshal(project)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write spec: %w", err)
+	}
+	return nil
+}
+
+func NewManifest(project *model.Project, files []generator.File) *Manifest {
+	manifest := &Manifest{Schema: SchemaURL, APIVersion: APIVersion, Project: *project, Files: make(map[string]string, len(files))}
+	for _, file := range files {
+		manifest.Files[filepath.ToSlash(file.Path)] = Hash([]byte(file.Content))
+	}
+	return manifest
+}
+
+// ManifestFile renders the manifest for files so it can be written alongside
+// them.
+func ManifestFile(project *model.Project, files []generator.File) (generator.File, error) {
+	data, err := encode(NewManifest(project, files))
+	if err != nil {
+		return generator.File{}, err
+	}
+	return generator.File{Path: ManifestName, Content: manifestHeader + string(data)}, nil
+}
+
+// LoadManifest reads the manifest of the project in dir. It returns an error
+// satisfying os.IsNotExist when the project has none.
+func LoadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := unmarshalManifest(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ManifestName, err)
+	}
+	return manifest, nil
+}
+
+// SaveManifest writes manifest back to the project in dir in the current
+// format. The file hashes are kept, so they still describe the files on disk
+// until the project is regenerated.
+func SaveManifest(dir string, manifest *Manifest) error {
+	manifest.Schema, manifest.APIVersion = SchemaURL, APIVersion
+	data, err := encode(manifest)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ManifestName), []byte(manifestHeader+string(data)), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ManifestName, err)
+	}
+	return nil
+}
+
+func Hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func unmarshalManifest(data []byte) (*Manifest, error) {
+	data, steps, err := Migrate(data)
+	if err != nil {
+		return nil, err
+	}
+	manifest := &Manifest{Project: *model.NewProject("", nil), Migrated: steps}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(manifest); err != nil {
+		return nil, err
+	}
+	manifest.OutputDir = fmt.Sprintf("./%s", manifest.Name)
+
+	// A model left out is the default the wizard offers.
+	if o := manifest.Orchestrator; o != nil {
+		if o.Model == "" {
+			o.Model = model.DefaultModel
+		}
+		for _, agent := range o.SubAgents {
+			if agent != nil && agent.Model == "" {
+				agent.Model = model.DefaultModel
+			}
+		}
+	}
+	return manifest, nil
+}
+
+func encode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to encode spec: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode spec: %w", err)
+	}
+	return buf.Bytes(), nil
+}
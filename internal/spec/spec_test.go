@@ -0,0 +1,541 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/doji-co/agent-builder/internal/generator"
+	"github.com/doji-co/agent-builder/internal/model"
+	"gopkg.in/yaml.v3"
+)
+
+func testProject() *model.Project {
+	orch := model.NewOrchestrator("ResearchCoordinator", model.PatternSequential, "Coordinates research", "gemini-2.5-flash")
+	researcher := model.NewAgent("Researcher", model.AgentTypeLLM, "Research the topic", "research_data", "gemini-2.5-flash")
+	researcher.Examples = []model.Example{{Prompt: "Solar power", Expected: "A summary"}}
+	orch.AddSubAgent(researcher)
+	orch.AddSubAgent(model.NewAgent("Writer", model.AgentTypeLLM, "Write based on {research_data}", "draft", "gemini-2.5-flash"))
+
+	temperature, maxTokens := 0.3, 1024
+	orch.GenerationConfig = &model.GenerationConfig{Temperature: &temperature}
+	orch.SubAgents[1].GenerationConfig = &model.GenerationConfig{
+		MaxOutputTokens: &maxTokens,
+		SafetySettings:  []model.SafetySetting{{Category: model.HarmCategoryHarassment, Threshold: model.HarmThresholdOnlyHigh}},
+	}
+
+	project := model.NewProject("research-assistant", orch)
+	project.Packaging = model.PackagingUV
+	project.AddTests = false
+	return project
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	project := testProject()
+
+	data, err := Marshal(project)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	got, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, project) {
+		t.Errorf("Unmarshal(Marshal()) = %+v, want %+v\n%s", got, project, data)
+	}
+}
+
+func TestMarshal_Format(t *testing.T) {
+	data, err := Marshal(testProject())
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	content := string(data)
+
+	expectedStrings := []string{
+		"name: research-assistant\n",
+		"adkVersion: \"1.0\"\n",
+		"packaging: uv\n",
+		"addTests: false\n",
+		"orchestrator:\n  name: ResearchCoordinator\n  pattern: sequential\n",
+		"  subAgents:\n    - name: Researcher\n      type: llm\n",
+		"      outputKey: research_data\n",
+		"      examples:\n        - prompt: Solar power\n",
+	}
+
+	for _, expected := range expectedStrings {
+		if !strings.Contains(content, expected) {
+			t.Errorf("Marshal() missing expected string: %q\n%s", expected, content)
+		}
+	}
+
+	if strings.Contains(content, "outputDir") {
+		t.Error("Marshal() should not record the output directory")
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr bool
+		check   func(t *testing.T, project *model.Project)
+	}{
+		{
+			name: "omitted fields keep defaults",
+			spec: `name: minimal
+orchestrator:
+  name: Coordinator
+  pattern: parallel
+  subAgents:
+    - name: Worker
+      type: llm
+      instruction: Work
+`,
+			check: func(t *testing.T, project *model.Project) {
+				if !project.AddTests || !project.AddEval {
+					t.Error("AddTests and AddEval should default to true")
+				}
+				if project.Packaging != model.PackagingRequirements {
+					t.Errorf("Packaging = %v, want %v", project.Packaging, model.PackagingRequirements)
+				}
+				if project.OutputDir != "./minimal" {
+					t.Errorf("OutputDir = %v, want ./minimal", project.OutputDir)
+				}
+				if project.Orchestrator.Pattern != model.PatternParallel {
+					t.Errorf("Pattern = %v, want %v", project.Orchestrator.Pattern, model.PatternParallel)
+				}
+			},
+		},
+		{
+			name: "omitted model is the default",
+			spec: `name: minimal
+orchestrator:
+  name: Coordinator
+  pattern: sequential
+  subAgents:
+    - name: Worker
+      type: llm
+      instruction: Work
+    - name: Reviewer
+      type: llm
+      instruction: Review
+      model: gemini-2.5-pro
+`,
+			check: func(t *testing.T, project *model.Project) {
+				orch := project.Orchestrator
+				if orch.Model != model.DefaultModel || orch.SubAgents[0].Model != model.DefaultModel {
+					t.Errorf("models = %v, %v, want %v", orch.Model, orch.SubAgents[0].Model, model.DefaultModel)
+				}
+				if orch.SubAgents[1].Model != "gemini-2.5-pro" {
+					t.Errorf("Reviewer model = %v, want gemini-2.5-pro", orch.SubAgents[1].Model)
+				}
+				if err := project.Validate(); err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+			},
+		},
+		{
+			name:    "unknown field",
+			spec:    "name: typo\naddTest: true\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid yaml",
+			spec:    "name: [unclosed\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project, err := Unmarshal([]byte(tt.spec))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, project)
+			}
+		})
+	}
+}
+
+func TestManifestFile(t *testing.T) {
+	project := testProject()
+	files := []generator.File{
+		{Path: filepath.Join("researcher", "agent.py"), Content: "agent = None\n"},
+		{Path: "main.py", Content: "print('hi')\n"},
+	}
+
+	file, err := ManifestFile(project, files)
+	if err != nil {
+		t.Fatalf("ManifestFile() error = %v", err)
+	}
+
+	if file.Path != ManifestName {
+		t.Errorf("Path = %v, want %v", file.Path, ManifestName)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ManifestName), []byte(file.Content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(&manifest.Project, project) {
+		t.Errorf("LoadManifest() project = %+v, want %+v", manifest.Project, *project)
+	}
+
+	wantFiles := map[string]string{
+		"researcher/agent.py": Hash([]byte("agent = None\n")),
+		"main.py":             Hash([]byte("print('hi')\n")),
+	}
+	if !reflect.DeepEqual(manifest.Files, wantFiles) {
+		t.Errorf("LoadManifest() files = %v, want %v", manifest.Files, wantFiles)
+	}
+}
+
+func TestSaveManifest(t *testing.T) {
+	project := testProject()
+	files := []generator.File{{Path: "main.py", Content: "print('hi')\n"}}
+	file, err := ManifestFile(project, files)
+	if err != nil {
+		t.Fatalf("ManifestFile() error = %v", err)
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ManifestName), []byte(file.Content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	manifest.Orchestrator.SubAgents[0].Name = "Investigator"
+	if err := SaveManifest(dir, manifest); err != nil {
+		t.Fatalf("SaveManifest() error = %v", err)
+	}
+
+	saved, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	if saved.Orchestrator.SubAgents[0].Name != "Investigator" {
+		t.Errorf("sub-agent name = %v, want Investigator", saved.Orchestrator.SubAgents[0].Name)
+	}
+	if saved.Files["main.py"] != Hash([]byte("print('hi')\n")) {
+		t.Errorf("files = %v, want the hashes kept", saved.Files)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, ManifestName))
+	if !strings.HasPrefix(string(data), manifestHeader) {
+		t.Errorf("saved manifest does not start with the header:\n%s", data)
+	}
+}
+
+func TestLoadManifest_Missing(t *testing.T) {
+	_, err := LoadManifest(t.TempDir())
+	if !os.IsNotExist(err) {
+		t.Errorf("LoadManifest() error = %v, want not-exist error", err)
+	}
+}
+
+func TestSpec_RegeneratesIdenticalFiles(t *testing.T) {
+	project := testProject()
+	project.Orchestrator.SubAgents[1].Instruction = "Write a 'draft' based on {research_data}: ünïcode"
+	gen := generator.NewGenerator()
+
+	want, err := gen.RenderProject(project)
+	if err != nil {
+		t.Fatalf("RenderProject() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := Save(path, project); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, _, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	got, err := gen.RenderProject(loaded)
+	if err != nil {
+		t.Fatalf("RenderProject() from spec error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Error("RenderProject() from a saved spec differs from the original output")
+	}
+}
+
+func TestSchema_MatchesPublishedFile(t *testing.T) {
+	got, err := Schema()
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+
+	want, err := os.ReadFile(filepath.Join("..", "..", "schema", "agent-builder.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != string(want) {
+		t.Error("schema/agent-builder.schema.json is out of date; run: go run . schema > schema/agent-builder.schema.json")
+	}
+}
+
+func TestSchema_CoversSpec(t *testing.T) {
+	data, err := Schema()
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatalf("Schema() is not valid JSON: %v", err)
+	}
+
+	// Models outside the wizard's list are valid too.
+	project := testProject()
+	project.Orchestrator.SubAgents[1].Model = "gemini-2.0-flash"
+
+	manifest, err := ManifestFile(project, []generator.File{{Path: "main.py", Content: "\n"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal([]byte(manifest.Content), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if doc["$schema"] != SchemaURL {
+		t.Errorf("manifest $schema = %v, want %v", doc["$schema"], SchemaURL)
+	}
+	assertCovered(t, "", schema, doc)
+}
+
+// assertCovered checks that every key and enum value in doc is allowed by
+// schema.
+func assertCovered(t *testing.T, path string, schema map[string]interface{}, doc interface{}) {
+	t.Helper()
+
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		for key, child := range v {
+			prop, ok := properties[key].(map[string]interface{})
+			if !ok {
+				if extra, isMap := schema["additionalProperties"].(map[string]interface{}); isMap {
+					assertCovered(t, path+"."+key, extra, child)
+					continue
+				}
+				t.Errorf("schema has no property %s%s", path, "."+key)
+				continue
+			}
+			assertCovered(t, path+"."+key, prop, child)
+		}
+	case []interface{}:
+		items, _ := schema["items"].(map[string]interface{})
+		for i, child := range v {
+			assertCovered(t, fmt.Sprintf("%s[%d]", path, i), items, child)
+		}
+	default:
+		if !allows(schema, v) {
+			t.Errorf("%s = %v is not allowed by the schema %v", path, v, schema)
+		}
+	}
+}
+
+// allows reports whether a scalar matches schema's enum, or one of its anyOf
+// branches.
+func allows(schema map[string]interface{}, v interface{}) bool {
+	if branches, ok := schema["anyOf"].([]interface{}); ok {
+		for _, branch := range branches {
+			if branch, ok := branch.(map[string]interface{}); ok && allows(branch, v) {
+				return true
+			}
+		}
+		return false
+	}
+	enum, ok := schema["enum"].([]interface{})
+	if !ok {
+		return true
+	}
+	for _, allowed := range enum {
+		if allowed == v {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMigrate(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      string
+		wantSteps int
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "unversioned spec",
+			spec:      "# my spec\nname: demo\norchestrator:\n  name: Coordinator # root\n",
+			wantSteps: 2,
+			want:      "# my spec\n$schema: " + SchemaURL + "\napiVersion: agent-builder/v2\nname: demo\norchestrator:\n  name: Coordinator # root\n",
+		},
+		{
+			name:      "unversioned spec with $schema",
+			spec:      "$schema: ./local.json\nname: demo\n",
+			wantSteps: 2,
+			want:      "$schema: ./local.json\napiVersion: agent-builder/v2\nname: demo\n",
+		},
+		{
+			name:      "v1 spec",
+			spec:      "# my spec\napiVersion: agent-builder/v1\nname: demo\n",
+			wantSteps: 1,
+			want:      "# my spec\napiVersion: agent-builder/v2\nname: demo\n",
+		},
+		{
+			name: "current spec",
+			spec: "apiVersion: agent-builder/v2\nname:   demo\n",
+			want: "apiVersion: agent-builder/v2\nname:   demo\n",
+		},
+		{
+			name:    "newer spec",
+			spec:    "apiVersion: agent-builder/v9\nname: demo\n",
+			wantErr: "apiVersion agent-builder/v9 is newer than this agent-builder supports (agent-builder/v2); upgrade agent-builder",
+		},
+		{
+			name:    "unknown apiVersion",
+			spec:    "apiVersion: v1\nname: demo\n",
+			wantErr: `unknown apiVersion "v1" (want agent-builder/v2)`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, steps, err := Migrate([]byte(tt.spec))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Migrate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Migrate() error = %v", err)
+			}
+
+			if len(steps) != tt.wantSteps {
+				t.Errorf("Migrate() steps = %v, want %d", steps, tt.wantSteps)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Migrate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrations_ReachCurrentFormat(t *testing.T) {
+	if len(migrations) != FormatVersion {
+		t.Errorf("len(migrations) = %d, want one per format up to %d", len(migrations), FormatVersion)
+	}
+
+	data, err := Marshal(testProject())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "apiVersion: "+APIVersion+"\n") {
+		t.Errorf("Marshal() should write apiVersion %s\n%s", APIVersion, data)
+	}
+}
+
+func TestLoad_MigratesOlderSpec(t *testing.T) {
+	project := testProject()
+	data, err := Marshal(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := strings.Replace(string(data), "apiVersion: "+APIVersion+"\n", "", 1)
+
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(path, []byte(old), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, steps, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(steps) != FormatVersion || steps[0].From != 0 || steps[len(steps)-1].To != FormatVersion {
+		t.Errorf("Load() steps = %v, want v0 → v%d", steps, FormatVersion)
+	}
+	if !reflect.DeepEqual(loaded, project) {
+		t.Errorf("Load() = %+v, want %+v", loaded, project)
+	}
+}
+
+func TestLoad_V1Spec(t *testing.T) {
+	// A spec as agent-builder wrote it before format 2.
+	v1 := `$schema: ` + SchemaURL + `
+apiVersion: agent-builder/v1
+name: research-assistant
+adkVersion: "1.0"
+packaging: uv
+addExample: true
+addReadme: true
+addDocker: false
+addEval: true
+addTests: false
+orchestrator:
+  name: ResearchCoordinator
+  pattern: sequential
+  description: Coordinates research
+  model: gemini-2.5-flash
+  subAgents:
+    - name: Researcher
+      type: llm
+      instruction: Research the topic
+      outputKey: research_data
+      model: gemini-2.5-flash
+    - name: Writer
+      type: llm
+      instruction: Write based on {research_data}
+      model: gemini-2.5-flash
+`
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(path, []byte(v1), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, steps, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(steps) != 1 || steps[0].From != 1 || steps[0].To != 2 {
+		t.Errorf("Load() steps = %v, want v1 → v2", steps)
+	}
+
+	orch := model.NewOrchestrator("ResearchCoordinator", model.PatternSequential, "Coordinates research", "gemini-2.5-flash")
+	orch.AddSubAgent(model.NewAgent("Researcher", model.AgentTypeLLM, "Research the topic", "research_data", "gemini-2.5-flash"))
+	orch.AddSubAgent(model.NewAgent("Writer", model.AgentTypeLLM, "Write based on {research_data}", "", "gemini-2.5-flash"))
+	want := model.NewProject("research-assistant", orch)
+	want.Packaging = model.PackagingUV
+	want.AddTests = false
+	if !reflect.DeepEqual(loaded, want) {
+		t.Errorf("Load() = %+v, want %+v", loaded, want)
+	}
+	if err := loaded.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,309 @@
+package studio
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/doji-co/agent-builder/internal/generator"
+	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/naming"
+	"github.com/doji-co/agent-builder/internal/pysyntax"
+	"github.com/doji-co/agent-builder/internal/spec"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed static/*
+var staticFS embed.FS
+
+// maxBody caps the size of a spec posted to the API.
+const maxBody = 1 << 20
+
+// Server serves the studio's single-page app and the API it edits a project
+// through. Projects travel as JSON objects with the keys of the YAML spec, so
+// the spec package parses, migrates and validates them exactly as it does a
+// spec file.
+type Server struct {
+	project *model.Project
+	gen     *generator.Generator
+
+	// OutputDir overrides the directory a project is generated into; by
+	// default it is ./<project name>.
+	OutputDir string
+	// Generate writes the project to project.OutputDir.
+	Generate func(project *model.Project) error
+}
+
+func New(project *model.Project, generate func(*model.Project) error) *Server {
+	return &Server{
+		project: project,
+		// The preview renders on every edit; the tokenizer keeps it fast,
+		// and Generate still checks with python3.
+		gen:      generator.NewGeneratorWithChecker(pysyntax.TokenChecker{}),
+		Generate: generate,
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	static, err := fs.Sub(staticFS, "static")
+	if err != nil {
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServer(http.FS(static)))
+	mux.HandleFunc("GET /api/project", s.handleProject)
+	mux.HandleFunc("GET /api/schema", s.handleSchema)
+	mux.HandleFunc("POST /api/preview", s.handlePreview)
+	mux.HandleFunc("POST /api/import", s.handleImport)
+	mux.HandleFunc("POST /api/export", s.handleExport)
+	mux.HandleFunc("POST /api/generate", s.handleGenerate)
+	return localOnly(mux)
+}
+
+// localOnly rejects requests that did not come from the studio page itself:
+// a Host other than localhost (DNS rebinding) or, for POSTs, another origin
+// or a body that is not JSON, which a page elsewhere could send without a
+// CORS preflight.
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if host != "localhost" && net.ParseIP(host) == nil {
+			http.Error(w, "studio only answers on localhost", http.StatusForbidden)
+			return
+		}
+		if r.Method == http.MethodPost {
+			if origin := r.Header.Get("Origin"); origin != "" && origin != "http://"+r.Host {
+				http.Error(w, "cross-origin request refused", http.StatusForbidden)
+				return
+			}
+			if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+				http.Error(w, "expected application/json", http.StatusUnsupportedMediaType)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type previewFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+type previewResponse struct {
+	// Error is the reason the project cannot be generated, empty when it
+	// can.
+	Error string        `json:"error,omitempty"`
+	Spec  string        `json:"spec"`
+	Files []previewFile `json:"files"`
+	// AgentFiles is the agent.py of the orchestrator followed by those of
+	// the sub-agents, in order.
+	AgentFiles []string `json:"agentFiles"`
+	// AgentErrors holds each sub-agent's own validation error, empty for
+	// the valid ones, so the page can point at the agent to fix.
+	AgentErrors []string `json:"agentErrors"`
+	// Warnings are placeholders no agent writes before they are read, which
+	// do not stop generation.
+	Warnings []string `json:"warnings"`
+}
+
+type generateResponse struct {
+	Dir   string   `json:"dir"`
+	Files []string `json:"files"`
+}
+
+func (s *Server) handleProject(w http.ResponseWriter, r *http.Request) {
+	doc, err := toJSON(s.project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *Server) handleSchema(w http.ResponseWriter, r *http.Request) {
+	schema, err := spec.Schema()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(schema)
+}
+
+// handlePreview renders a project without writing it. A project that does
+// not validate is not an HTTP error: the response carries the reason and the
+// spec so the page can keep showing both while the user edits.
+func (s *Server) handlePreview(w http.ResponseWriter, r *http.Request) {
+	response := previewResponse{Files: []previewFile{}, AgentFiles: []string{}, AgentErrors: []string{}, Warnings: []string{}}
+	project, err := readProject(r)
+	if err != nil {
+		response.Error = err.Error()
+		writeJSON(w, http.StatusOK, response)
+		return
+	}
+
+	if project.Orchestrator != nil {
+		response.AgentFiles = agentFiles(project)
+		for _, agent := range project.Orchestrator.SubAgents {
+			msg := ""
+			if err := agent.Validate(); err != nil {
+				msg = err.Error()
+			}
+			response.AgentErrors = append(response.AgentErrors, msg)
+		}
+		response.Warnings = append(response.Warnings, project.Orchestrator.PlaceholderWarnings()...)
+	}
+	if data, err := spec.Marshal(project); err == nil {
+		response.Spec = string(data)
+	}
+	files, err := s.gen.RenderProject(project)
+	if err != nil {
+		response.Error = err.Error()
+		writeJSON(w, http.StatusOK, response)
+		return
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	for _, file := range files {
+		response.Files = append(response.Files, previewFile{Path: file.Path, Content: file.Content})
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// handleImport turns a spec file opened in the page into the JSON the page
+// edits. The spec is posted as a JSON string.
+func (s *Server) handleImport(w http.ResponseWriter, r *http.Request) {
+	var text string
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBody)).Decode(&text); err != nil {
+		http.Error(w, fmt.Sprintf("failed to read spec: %v", err), http.StatusBadRequest)
+		return
+	}
+	project, err := spec.Unmarshal([]byte(text))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to parse spec: %v", err), http.StatusBadRequest)
+		return
+	}
+	doc, err := toJSON(project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *Server) handleExport(w http.ResponseWriter, r *http.Request) {
+	project, err := readProject(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	data, err := spec.Marshal(project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", spec.ManifestName))
+	w.Write(data)
+}
+
+// handleGenerate writes the project to disk. It refuses a directory that
+// already has files in it unless the request says ?overwrite=true.
+func (s *Server) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	project, err := readProject(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if s.OutputDir != "" {
+		project.OutputDir = s.OutputDir
+	}
+	if err := project.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("project validation failed: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+	if err := generator.ValidateIdentifiers(project); err != nil {
+		http.Error(w, fmt.Sprintf("project validation failed: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+
+	entries, err := os.ReadDir(project.OutputDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(entries) > 0 && r.URL.Query().Get("overwrite") != "true" {
+		http.Error(w, fmt.Sprintf("%s already exists and is not empty", project.OutputDir), http.StatusConflict)
+		return
+	}
+
+	if err := s.Generate(project); err != nil {
+		http.Error(w, fmt.Sprintf("failed to generate project: %v", err), http.StatusInternalServerError)
+		return
+	}
+	files, err := s.gen.RenderProject(project)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response := generateResponse{Dir: project.OutputDir, Files: []string{spec.ManifestName}}
+	for _, file := range files {
+		response.Files = append(response.Files, file.Path)
+	}
+	sort.Strings(response.Files)
+	writeJSON(w, http.StatusOK, response)
+}
+
+func agentFiles(project *model.Project) []string {
+	orchestrator := project.Orchestrator
+	paths := []string{naming.SnakeCase(orchestrator.Name) + "/agent.py"}
+	for _, agent := range orchestrator.SubAgents {
+		paths = append(paths, naming.SnakeCase(agent.Name)+"/agent.py")
+	}
+	return paths
+}
+
+// readProject parses the posted project. JSON is YAML, so the spec package
+// reads it as it would a spec file, rejecting unknown keys.
+func readProject(r *http.Request) (*model.Project, error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project: %w", err)
+	}
+	project, err := spec.Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse project: %w", err)
+	}
+	return project, nil
+}
+
+// toJSON converts a project to the JSON form of its spec.
+func toJSON(project *model.Project) (interface{}, error) {
+	data, err := spec.Marshal(project)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to convert spec: %w", err)
+	}
+	return doc, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,288 @@
+package studio
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/doji-co/agent-builder/internal/model"
+)
+
+func testProject() *model.Project {
+	orch := model.NewOrchestrator("ResearchCoordinator", model.PatternSequential, "Coordinates research", "gemini-2.5-flash")
+	orch.AddSubAgent(model.NewAgent("Researcher", model.AgentTypeLLM, "Research the topic", "research_data", "gemini-2.5-flash"))
+	orch.AddSubAgent(model.NewAgent("Writer", model.AgentTypeLLM, "Write based on {research_data}", "draft", "gemini-2.5-flash"))
+	return model.NewProject("research-assistant", orch)
+}
+
+// request sends a request the way the studio page does: from localhost, with
+// a JSON body.
+func request(t *testing.T, handler http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, "http://localhost:8484"+path, strings.NewReader(body))
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Origin", "http://localhost:8484")
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func projectJSON(t *testing.T, handler http.Handler) map[string]interface{} {
+	t.Helper()
+	rec := request(t, handler, http.MethodGet, "/api/project", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /api/project = %d: %s", rec.Code, rec.Body)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func marshal(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestStaticAssets(t *testing.T) {
+	handler := New(testProject(), nil).Handler()
+
+	rec := request(t, handler, http.MethodGet, "/", "")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `<script src="app.js">`) {
+		t.Fatalf("GET / = %d, want the page", rec.Code)
+	}
+
+	// Everything the page loads must come with agent-builder.
+	external := regexp.MustCompile(`(src|href)=["']?(https?:)?//|url\(["']?(https?:)?//|import\s.*["']https?:|fetch\(["']https?:`)
+	err := fs.WalkDir(staticFS, "static", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := staticFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if match := external.Find(data); match != nil {
+			t.Errorf("%s loads an external resource: %s", path, match)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProject_RoundTrip(t *testing.T) {
+	handler := New(testProject(), nil).Handler()
+	doc := projectJSON(t, handler)
+
+	orchestrator := doc["orchestrator"].(map[string]interface{})
+	if doc["name"] != "research-assistant" || orchestrator["pattern"] != "sequential" {
+		t.Errorf("project = %v, want the spec's keys", doc)
+	}
+
+	rec := request(t, handler, http.MethodPost, "/api/export", marshal(t, doc))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/export = %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="agent-builder.yaml"` {
+		t.Errorf("Content-Disposition = %v", got)
+	}
+	for _, want := range []string{"apiVersion: agent-builder/v2", "name: research-assistant", "outputKey: research_data"} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("exported spec is missing %q:\n%s", want, rec.Body)
+		}
+	}
+
+	rec = request(t, handler, http.MethodPost, "/api/import", marshal(t, rec.Body.String()))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST /api/import = %d: %s", rec.Code, rec.Body)
+	}
+	var imported map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &imported); err != nil {
+		t.Fatal(err)
+	}
+	if marshal(t, imported) != marshal(t, doc) {
+		t.Errorf("import(export(project)) = %v, want %v", imported, doc)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	handler := New(testProject(), nil).Handler()
+	doc := projectJSON(t, handler)
+
+	tests := []struct {
+		name        string
+		edit        func(doc map[string]interface{})
+		wantError   string
+		wantFile    string
+		agentErrors []string
+		warnings    []string
+	}{
+		{
+			name:        "valid project",
+			edit:        func(map[string]interface{}) {},
+			wantFile:    "writer/agent.py",
+			agentErrors: []string{"", ""},
+		},
+		{
+			name: "agent without an instruction",
+			edit: func(doc map[string]interface{}) {
+				agents := doc["orchestrator"].(map[string]interface{})["subAgents"].([]interface{})
+				delete(agents[1].(map[string]interface{}), "instruction")
+			},
+			wantError:   "instruction is required for LLM agents",
+			agentErrors: []string{"", "instruction is required for LLM agents"},
+		},
+		{
+			name: "placeholder no agent writes",
+			edit: func(doc map[string]interface{}) {
+				agents := doc["orchestrator"].(map[string]interface{})["subAgents"].([]interface{})
+				agents[1].(map[string]interface{})["instruction"] = "Write based on {notes}"
+			},
+			agentErrors: []string{"", ""},
+			warnings:    []string{"sub-agent Writer: placeholder {notes} is not the outputKey of an agent that runs earlier; unless a tool or callback writes it first, make it one, or write {notes?} if it may be missing"},
+		},
+		{
+			name:      "unknown key",
+			edit:      func(doc map[string]interface{}) { doc["colour"] = "blue" },
+			wantError: "field colour not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var edited map[string]interface{}
+			json.Unmarshal([]byte(marshal(t, doc)), &edited)
+			tt.edit(edited)
+
+			rec := request(t, handler, http.MethodPost, "/api/preview", marshal(t, edited))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("POST /api/preview = %d: %s", rec.Code, rec.Body)
+			}
+			var got previewResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.wantError == "" && got.Error != "" {
+				t.Errorf("Error = %v, want none", got.Error)
+			}
+			if tt.wantError != "" && !strings.Contains(got.Error, tt.wantError) {
+				t.Errorf("Error = %v, want %q", got.Error, tt.wantError)
+			}
+			if marshal(t, got.AgentErrors) != marshal(t, append([]string{}, tt.agentErrors...)) {
+				t.Errorf("AgentErrors = %q, want %q", got.AgentErrors, tt.agentErrors)
+			}
+			if marshal(t, got.Warnings) != marshal(t, append([]string{}, tt.warnings...)) {
+				t.Errorf("Warnings = %q, want %q", got.Warnings, tt.warnings)
+			}
+			if tt.wantFile == "" {
+				return
+			}
+			found := false
+			for _, file := range got.Files {
+				found = found || file.Path == tt.wantFile && strings.Contains(file.Content, "research_data")
+			}
+			if !found {
+				t.Errorf("Files has no %s rendering the project", tt.wantFile)
+			}
+			if want := []string{"research_coordinator/agent.py", "researcher/agent.py", "writer/agent.py"}; marshal(t, got.AgentFiles) != marshal(t, want) {
+				t.Errorf("AgentFiles = %v, want %v", got.AgentFiles, want)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	var generated []*model.Project
+	server := New(testProject(), func(project *model.Project) error {
+		generated = append(generated, project)
+		return os.WriteFile(filepath.Join(project.OutputDir, "agent-builder.yaml"), []byte("name: x\n"), 0644)
+	})
+	server.OutputDir = t.TempDir()
+	handler := server.Handler()
+	body := marshal(t, projectJSON(t, handler))
+
+	rec := request(t, handler, http.MethodPost, "/api/generate", body)
+	if rec.Code != http.StatusOK || len(generated) != 1 {
+		t.Fatalf("POST /api/generate = %d (%s), generated %d times", rec.Code, rec.Body, len(generated))
+	}
+	if generated[0].OutputDir != server.OutputDir {
+		t.Errorf("OutputDir = %v, want %v", generated[0].OutputDir, server.OutputDir)
+	}
+	var got generateResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(strings.Join(got.Files, " "), "researcher/agent.py") {
+		t.Errorf("Files = %v, want the generated files", got.Files)
+	}
+
+	rec = request(t, handler, http.MethodPost, "/api/generate", body)
+	if rec.Code != http.StatusConflict || len(generated) != 1 {
+		t.Errorf("generating into a non-empty directory = %d, want %d without overwriting", rec.Code, http.StatusConflict)
+	}
+	rec = request(t, handler, http.MethodPost, "/api/generate?overwrite=true", body)
+	if rec.Code != http.StatusOK || len(generated) != 2 {
+		t.Errorf("generating with overwrite = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	for _, invalid := range []string{
+		strings.Replace(body, `"Research the topic"`, `""`, 1),
+		strings.Replace(body, `"research-assistant"`, `"../escaped"`, 1),
+	} {
+		rec = request(t, handler, http.MethodPost, "/api/generate?overwrite=true", invalid)
+		if rec.Code != http.StatusUnprocessableEntity || len(generated) != 2 {
+			t.Errorf("generating an invalid project = %d (%s), want %d", rec.Code, strings.TrimSpace(rec.Body.String()), http.StatusUnprocessableEntity)
+		}
+	}
+}
+
+func TestLocalOnly(t *testing.T) {
+	handler := New(testProject(), func(*model.Project) error {
+		t.Error("a refused request generated the project")
+		return nil
+	}).Handler()
+
+	tests := []struct {
+		name    string
+		host    string
+		origin  string
+		content string
+		want    int
+	}{
+		{"rebound host name", "attacker.example:8484", "", "application/json", http.StatusForbidden},
+		{"another origin", "localhost:8484", "http://attacker.example", "application/json", http.StatusForbidden},
+		{"form post", "localhost:8484", "", "text/plain", http.StatusUnsupportedMediaType},
+		{"loopback address", "127.0.0.1:8484", "http://127.0.0.1:8484", "application/json; charset=utf-8", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "http://"+tt.host+"/api/generate", strings.NewReader("{"))
+			req.Header.Set("Content-Type", tt.content)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d (%s), want %d", rec.Code, strings.TrimSpace(rec.Body.String()), tt.want)
+			}
+		})
+	}
+}
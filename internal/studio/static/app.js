@@ -0,0 +1,582 @@
+// agent-builder studio: edits a project spec in the browser and asks the
+// local agent-builder server to validate, preview, export and generate it.
+// The project is kept as the JSON form of the YAML spec, with the same keys.
+"use strict";
+
+const SPEC = "agent-builder.yaml";
+const DEFAULT_MODEL = "gemini-2.5-flash";
+
+const state = {
+  project: null,
+  schema: null,
+  // selected is {kind: "project"}, {kind: "orchestrator"} or
+  // {kind: "agent", index}.
+  selected: { kind: "project" },
+  preview: null,
+  // lastFiles are the files of the last project that rendered, shown while
+  // the current one has a validation error.
+  lastFiles: [],
+  // file is the previewed path; it follows the selection until a file is
+  // picked from the list.
+  file: SPEC,
+  followSelection: true,
+  dirty: false,
+  previewSeq: 0,
+  previewTimer: null,
+};
+
+const isLLM = (agent) => agent.type === "llm";
+const coordinated = () => state.project.orchestrator.pattern === "llm-coordinated";
+
+// Form fields per node. Choices, defaults and help text come from the spec's
+// JSON schema; kind is only given where the schema cannot tell.
+const projectFields = [
+  { key: "name", label: "Name" },
+  { key: "adkVersion", label: "ADK version" },
+  { key: "backend", label: "Backend" },
+  { key: "packaging", label: "Packaging" },
+  { key: "services.session", label: "Session service" },
+  { key: "services.databaseUrl", label: "Database URL", when: (p) => p.services.session === "database" },
+  { key: "services.memory", label: "Memory service" },
+  { key: "services.ragCorpus", label: "RAG corpus", when: (p) => p.services.memory === "vertex-ai-rag" },
+  { key: "services.artifact", label: "Artifact service" },
+  { key: "services.artifactDir", label: "Artifact directory", when: (p) => p.services.artifact === "local" },
+  { key: "services.bucket", label: "Bucket", when: (p) => p.services.artifact === "gcs" },
+  { key: "addExample", label: "Example (main.py)" },
+  { key: "addReadme", label: "README" },
+  { key: "addDocker", label: "Docker" },
+  { key: "addEval", label: "Evaluation set" },
+  { key: "addTests", label: "Unit tests" },
+];
+
+const orchestratorFields = [
+  { key: "name", label: "Name" },
+  { key: "pattern", label: "Pattern" },
+  { key: "description", label: "Description" },
+  { key: "model", label: "Model" },
+  { key: "globalInstruction", label: "Global instruction", kind: "long", when: coordinated },
+];
+
+const agentFields = [
+  { key: "name", label: "Name" },
+  { key: "type", label: "Type" },
+  { key: "description", label: "Description" },
+  { key: "instruction", label: "Instruction", kind: "long", when: isLLM },
+  { key: "staticInstruction", label: "Static instruction", kind: "long", when: isLLM },
+  { key: "promptFile", label: "Prompt file", when: isLLM },
+  { key: "outputKey", label: "Output key" },
+  { key: "model", label: "Model" },
+  { key: "memory", label: "Memory", when: isLLM },
+  { key: "disallowTransferToParent", label: "No transfer to parent", when: (a) => isLLM(a) && coordinated() },
+  { key: "disallowTransferToPeers", label: "No transfer to peers", when: (a) => isLLM(a) && coordinated() },
+];
+
+const patternNotes = {
+  sequential: "Sub-agents run one after another, left to right.",
+  parallel: "Sub-agents run at the same time.",
+  loop: "Sub-agents run in order, repeatedly, until one ends the loop.",
+  "llm-coordinated": "The orchestrator decides which sub-agent handles each request.",
+};
+
+// ---- helpers ----
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [name, value] of Object.entries(attrs || {})) {
+    if (name === "class") node.className = value;
+    else if (name.startsWith("on")) node.addEventListener(name.slice(2), value);
+    else if (value !== false && value != null) node.setAttribute(name, value === true ? "" : value);
+  }
+  for (const child of children) {
+    if (child != null) node.append(child);
+  }
+  return node;
+}
+
+function getPath(obj, key) {
+  return key.split(".").reduce((o, k) => (o == null ? undefined : o[k]), obj);
+}
+
+function setPath(obj, key, value) {
+  const keys = key.split(".");
+  const last = keys.pop();
+  const parent = keys.reduce((o, k) => (o[k] = o[k] || {}), obj);
+  // Empty text falls back to the spec's default; false is kept, as some
+  // flags default to true.
+  if (value === "") {
+    delete parent[last];
+  } else {
+    parent[last] = value;
+  }
+}
+
+// schemaFor returns the schema of key in the node kind's object.
+function schemaFor(kind, key) {
+  let node = state.schema;
+  const path = { project: [], orchestrator: ["orchestrator"], agent: ["orchestrator", "subAgents", "*"] }[kind];
+  for (const part of [...path, ...key.split(".")]) {
+    if (!node) return {};
+    node = part === "*" ? node.items : (node.properties || {})[part];
+  }
+  return node || {};
+}
+
+function agents() {
+  return state.project.orchestrator.subAgents || [];
+}
+
+function selectedObject() {
+  switch (state.selected.kind) {
+    case "project":
+      return state.project;
+    case "orchestrator":
+      return state.project.orchestrator;
+    default:
+      return agents()[state.selected.index];
+  }
+}
+
+function showMessage(text, isError) {
+  const box = document.getElementById("message");
+  box.textContent = text;
+  box.className = isError ? "error" : "";
+  box.hidden = !text;
+}
+
+async function api(path, body) {
+  const options = body === undefined ? {} : {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(body),
+  };
+  const response = await fetch(path, options);
+  if (!response.ok) {
+    const error = new Error((await response.text()).trim() || response.statusText);
+    error.status = response.status;
+    throw error;
+  }
+  return response;
+}
+
+// ---- editing ----
+
+function changed() {
+  state.dirty = true;
+  render();
+  schedulePreview();
+}
+
+function select(selection) {
+  state.selected = selection;
+  state.followSelection = true;
+  render();
+  renderPreview();
+}
+
+function uniqueAgentName() {
+  const taken = new Set([state.project.orchestrator.name, ...agents().map((a) => a.name)]);
+  for (let n = agents().length + 1; ; n++) {
+    if (!taken.has("Agent" + n)) return "Agent" + n;
+  }
+}
+
+function addAgent(type, at) {
+  const agent = { name: uniqueAgentName(), type };
+  if (type === "llm") {
+    agent.model = DEFAULT_MODEL;
+  }
+  const list = agents();
+  list.splice(at, 0, agent);
+  state.project.orchestrator.subAgents = list;
+  state.selected = { kind: "agent", index: at };
+  state.followSelection = true;
+  changed();
+}
+
+function moveAgent(from, to) {
+  const list = agents();
+  if (to < 0 || to >= list.length || from === to) return;
+  const [agent] = list.splice(from, 1);
+  list.splice(to, 0, agent);
+  state.selected = { kind: "agent", index: to };
+  changed();
+}
+
+function deleteAgent(index) {
+  const agent = agents()[index];
+  if (!confirm(`Delete ${agent.name}?`)) return;
+  agents().splice(index, 1);
+  if (state.selected.kind === "agent") {
+    state.selected = agents().length ? { kind: "agent", index: Math.min(index, agents().length - 1) } : { kind: "orchestrator" };
+  }
+  changed();
+}
+
+// ---- drag and drop ----
+
+// Drags carry "new:<type>" from the palette or "move:<index>" from a card.
+function dropSlot(index) {
+  const slot = el("div", { class: "slot", "aria-hidden": "true" });
+  slot.addEventListener("dragover", (event) => {
+    event.preventDefault();
+    slot.classList.add("over");
+  });
+  slot.addEventListener("dragleave", () => slot.classList.remove("over"));
+  slot.addEventListener("drop", (event) => {
+    event.preventDefault();
+    slot.classList.remove("over");
+    drop(event.dataTransfer.getData("text/plain"), index);
+  });
+  return slot;
+}
+
+function drop(data, index) {
+  const [action, value] = data.split(":");
+  if (action === "new") {
+    addAgent(value, index);
+  } else if (action === "move") {
+    const from = Number(value);
+    moveAgent(from, from < index ? index - 1 : index);
+  }
+}
+
+function setupPalette() {
+  for (const chip of document.querySelectorAll(".chip")) {
+    chip.addEventListener("dragstart", (event) => event.dataTransfer.setData("text/plain", "new:" + chip.dataset.type));
+    chip.addEventListener("click", () => {
+      const at = state.selected.kind === "agent" ? state.selected.index + 1 : agents().length;
+      addAgent(chip.dataset.type, at);
+    });
+  }
+
+  const trash = document.getElementById("trash");
+  trash.addEventListener("dragover", (event) => {
+    event.preventDefault();
+    trash.classList.add("over");
+  });
+  trash.addEventListener("dragleave", () => trash.classList.remove("over"));
+  trash.addEventListener("drop", (event) => {
+    event.preventDefault();
+    trash.classList.remove("over");
+    const [action, value] = event.dataTransfer.getData("text/plain").split(":");
+    if (action === "move") deleteAgent(Number(value));
+  });
+}
+
+// ---- rendering ----
+
+function render() {
+  renderCanvas();
+  renderInspector();
+}
+
+function card(label, kind, selection, extra) {
+  const selected = state.selected.kind === selection.kind && state.selected.index === selection.index;
+  const node = el("div", {
+    class: "card" + (selected ? " selected" : "") + (extra && extra.invalid ? " invalid" : ""),
+    role: "button",
+    tabindex: "0",
+    title: extra && extra.invalid ? extra.invalid : null,
+    onclick: () => select(selection),
+    onkeydown: (event) => {
+      if (event.key === "Enter" || event.key === " ") {
+        event.preventDefault();
+        select(selection);
+      }
+    },
+  }, el("div", { class: "name" }, label), el("div", { class: "kind" }, kind));
+  return node;
+}
+
+function renderCanvas() {
+  const canvas = document.getElementById("canvas");
+  const project = state.project;
+  const orchestrator = project.orchestrator;
+  const agentErrors = (state.preview && state.preview.agentErrors) || [];
+
+  const orchestratorCard = card(orchestrator.name || "(unnamed)", "orchestrator, " + orchestrator.pattern, { kind: "orchestrator" });
+  orchestratorCard.classList.add("orchestrator");
+  orchestratorCard.addEventListener("dragover", (event) => event.preventDefault());
+  orchestratorCard.addEventListener("drop", (event) => {
+    event.preventDefault();
+    drop(event.dataTransfer.getData("text/plain"), agents().length);
+  });
+
+  const vertical = orchestrator.pattern === "parallel" || orchestrator.pattern === "llm-coordinated";
+  const lane = el("div", { class: "lane" + (vertical ? " vertical" : "") });
+  const list = agents();
+  if (list.length === 0) {
+    const empty = dropSlot(0);
+    empty.className = "empty";
+    empty.textContent = "Drop agents here";
+    lane.append(empty);
+  }
+  list.forEach((agent, i) => {
+    lane.append(dropSlot(i));
+    const node = card(agent.name || "(unnamed)", agent.type + (agent.outputKey ? " → " + agent.outputKey : ""),
+      { kind: "agent", index: i }, { invalid: agentErrors[i] });
+    node.draggable = true;
+    node.addEventListener("dragstart", (event) => {
+      event.dataTransfer.setData("text/plain", "move:" + i);
+      node.classList.add("dragging");
+    });
+    node.addEventListener("dragend", () => node.classList.remove("dragging"));
+    lane.append(node);
+    if (!vertical && i < list.length - 1) {
+      lane.append(el("span", { class: "arrow", "aria-hidden": "true" }, "→"));
+    }
+  });
+  if (list.length > 0) lane.append(dropSlot(list.length));
+
+  const projectCard = card("Project " + (project.name || "(unnamed)"), project.packaging + ", ADK " + project.adkVersion, { kind: "project" });
+  projectCard.classList.add("project-card");
+
+  canvas.replaceChildren(
+    el("h2", {}, "Canvas"),
+    projectCard,
+    orchestratorCard,
+    el("div", { class: "pattern-note" }, patternNotes[orchestrator.pattern] || ""),
+    lane,
+  );
+}
+
+function fieldsFor(kind) {
+  return { project: projectFields, orchestrator: orchestratorFields, agent: agentFields }[kind];
+}
+
+function input(kind, obj, field) {
+  const schema = schemaFor(kind, field.key);
+  const id = "field-" + field.key.replace(/\./g, "-");
+  const value = getPath(obj, field.key);
+  const help = schema.description ? el("div", { class: "help" }, schema.description) : null;
+
+  if (schema.type === "boolean") {
+    const box = el("input", { type: "checkbox", id, checked: value === true,
+      onchange: (event) => { setPath(obj, field.key, event.target.checked); changed(); } });
+    return el("div", { class: "field check" }, el("label", { for: id }, box, field.label), help);
+  }
+
+  let control;
+  let suggestions = null;
+  if (schema.enum) {
+    const options = schema.enum.includes(value) || value === undefined ? schema.enum : [value, ...schema.enum];
+    control = el("select", { id, onchange: (event) => { setPath(obj, field.key, event.target.value); changed(); } },
+      ...options.map((option) => el("option", { value: option, selected: option === value }, option)));
+    if (value === undefined) {
+      control.prepend(el("option", { value: "", selected: true }, "(default)"));
+    }
+  } else {
+    // Text is applied as it is typed; only the preview waits for a pause.
+    const apply = (event) => {
+      setPath(obj, field.key, event.target.value);
+      state.dirty = true;
+      if (field.key === "name" || field.key === "outputKey") renderCanvas();
+      schedulePreview();
+    };
+    control = field.kind === "long"
+      ? el("textarea", { id, oninput: apply })
+      : el("input", { type: "text", id, oninput: apply });
+    control.value = value == null ? "" : String(value);
+    // An enum offered next to a plain string, such as the known models, is
+    // a list of suggestions; any value is kept.
+    const suggested = (schema.anyOf || []).find((branch) => branch.enum);
+    if (suggested && field.kind !== "long") {
+      control.setAttribute("list", id + "-suggestions");
+      suggestions = el("datalist", { id: id + "-suggestions" }, ...suggested.enum.map((value) => el("option", { value })));
+    }
+  }
+  return el("div", { class: "field" }, el("label", { for: id }, field.label), control, suggestions, help);
+}
+
+function renderInspector() {
+  const inspector = document.getElementById("inspector");
+  const { kind, index } = state.selected;
+  const obj = selectedObject();
+  const title = { project: "Project", orchestrator: "Orchestrator", agent: "Sub-agent" }[kind];
+  const children = [el("h2", {}, title)];
+
+  for (const field of fieldsFor(kind)) {
+    if (field.when && !field.when(obj)) continue;
+    children.push(input(kind, obj, field));
+  }
+
+  if (kind === "agent") {
+    children.push(el("div", { id: "agent-error", role: "alert" }));
+    children.push(el("div", { class: "buttons" },
+      el("button", { type: "button", disabled: index === 0, onclick: () => moveAgent(index, index - 1) }, "Move earlier"),
+      el("button", { type: "button", disabled: index === agents().length - 1, onclick: () => moveAgent(index, index + 1) }, "Move later"),
+      el("button", { type: "button", onclick: () => deleteAgent(index) }, "Delete"),
+    ));
+    children.push(el("p", { class: "kept" }, "Schemas, tools, callbacks, examples and generation settings are kept as they are; edit them in the spec file."));
+  }
+  inspector.replaceChildren(...children);
+  renderAgentError();
+}
+
+// renderAgentError shows the selected agent's own validation error. It is
+// updated in place so that a preview arriving while a field is being typed in
+// does not rebuild the form.
+function renderAgentError() {
+  const box = document.getElementById("agent-error");
+  if (!box) return;
+  const errors = (state.preview && state.preview.agentErrors) || [];
+  box.textContent = errors[state.selected.index] || "";
+}
+
+// ---- preview ----
+
+function schedulePreview() {
+  clearTimeout(state.previewTimer);
+  document.getElementById("status").textContent = "Rendering...";
+  state.previewTimer = setTimeout(refreshPreview, 250);
+}
+
+async function refreshPreview() {
+  const seq = ++state.previewSeq;
+  let preview;
+  try {
+    preview = await (await api("/api/preview", state.project)).json();
+  } catch (error) {
+    document.getElementById("status").textContent = "";
+    showMessage("Preview failed: " + error.message, true);
+    return;
+  }
+  // A slower, older answer must not replace a newer one.
+  if (seq !== state.previewSeq) return;
+  state.preview = preview;
+  if (!preview.error) state.lastFiles = preview.files;
+  renderCanvas();
+  renderAgentError();
+  renderPreview();
+}
+
+// selectionFile is the agent.py of the selected node, as the server names
+// it; the spec when the project does not parse or another node is selected.
+function selectionFile() {
+  const files = state.preview.agentFiles;
+  switch (state.selected.kind) {
+    case "orchestrator":
+      return files[0] || SPEC;
+    case "agent":
+      return files[state.selected.index + 1] || SPEC;
+    default:
+      return SPEC;
+  }
+}
+
+function renderPreview() {
+  const preview = state.preview;
+  if (!preview) return;
+  if (state.followSelection) state.file = selectionFile();
+
+  const error = document.getElementById("error");
+  error.textContent = preview.error ? "Invalid: " + preview.error : "";
+  error.hidden = !preview.error;
+
+  const warnings = document.getElementById("warnings");
+  warnings.textContent = (preview.warnings || []).map((w) => "Warning: " + w).join("\n");
+  warnings.hidden = !warnings.textContent;
+
+  const files = preview.error ? state.lastFiles : preview.files;
+  const select = document.getElementById("file");
+  select.replaceChildren(
+    el("option", { value: SPEC, selected: state.file === SPEC }, SPEC + " (spec)"),
+    ...files.map((file) => el("option", { value: file.path, selected: file.path === state.file }, file.path)),
+  );
+
+  let content = preview.spec;
+  let status = preview.error ? "" : "Up to date";
+  if (state.file !== SPEC) {
+    const file = files.find((f) => f.path === state.file);
+    content = file ? file.content : `${state.file} is not generated yet; fix the error above.`;
+    if (file && preview.error) status = "Showing the last valid render";
+  }
+  document.getElementById("code").textContent = content;
+  document.getElementById("status").textContent = status;
+}
+
+// ---- toolbar ----
+
+function download(name, blob) {
+  const link = el("a", { href: URL.createObjectURL(blob), download: name });
+  document.body.append(link);
+  link.click();
+  link.remove();
+  URL.revokeObjectURL(link.href);
+}
+
+async function exportSpec() {
+  try {
+    const response = await api("/api/export", state.project);
+    download(SPEC, await response.blob());
+    state.dirty = false;
+    showMessage(`Exported ${SPEC}. Generate from it with: agent-builder create --spec ${SPEC}`);
+  } catch (error) {
+    showMessage("Export failed: " + error.message, true);
+  }
+}
+
+async function generate(overwrite) {
+  const button = document.getElementById("generate");
+  button.disabled = true;
+  try {
+    const response = await api("/api/generate" + (overwrite ? "?overwrite=true" : ""), state.project);
+    const result = await response.json();
+    state.dirty = false;
+    showMessage(`Generated ${result.files.length} files in ${result.dir}:\n${result.files.join("  ")}`);
+  } catch (error) {
+    if (error.status === 409 && confirm(error.message + ". Overwrite the files in it?")) {
+      return generate(true);
+    }
+    showMessage("Not generated: " + error.message, true);
+  } finally {
+    button.disabled = false;
+  }
+}
+
+async function openSpec(file) {
+  try {
+    const text = await file.text();
+    state.project = await (await api("/api/import", text)).json();
+    state.selected = { kind: "project" };
+    state.followSelection = true;
+    state.dirty = false;
+    showMessage(`Opened ${file.name}.`);
+    render();
+    refreshPreview();
+  } catch (error) {
+    showMessage(`Could not open ${file.name}: ${error.message}`, true);
+  }
+}
+
+async function start() {
+  try {
+    const [project, schema] = await Promise.all([api("/api/project"), api("/api/schema")]);
+    state.project = await project.json();
+    state.schema = await schema.json();
+  } catch (error) {
+    showMessage("Could not reach agent-builder studio: " + error.message, true);
+    return;
+  }
+
+  setupPalette();
+  document.getElementById("export").addEventListener("click", exportSpec);
+  document.getElementById("generate").addEventListener("click", () => generate(false));
+  document.getElementById("open").addEventListener("change", (event) => {
+    if (event.target.files.length) openSpec(event.target.files[0]);
+    event.target.value = "";
+  });
+  document.getElementById("file").addEventListener("change", (event) => {
+    state.file = event.target.value;
+    state.followSelection = false;
+    renderPreview();
+  });
+  window.addEventListener("beforeunload", (event) => {
+    if (state.dirty) event.preventDefault();
+  });
+
+  render();
+  refreshPreview();
+}
+
+start();
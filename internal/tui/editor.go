@@ -0,0 +1,410 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/doji-co/agent-builder/internal/generator"
+	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/naming"
+	"github.com/doji-co/agent-builder/internal/prompt"
+	"github.com/doji-co/agent-builder/internal/pysyntax"
+	"github.com/doji-co/agent-builder/internal/spec"
+)
+
+type focus int
+
+const (
+	focusTree focus = iota
+	focusForm
+	focusEdit
+)
+
+// The tree lists the project, the orchestrator and then the sub-agents, so
+// sub-agent i is node i+firstAgent.
+const (
+	projectNode      = 0
+	orchestratorNode = 1
+	firstAgent       = 2
+)
+
+// Editor is the bubbletea model of the project editor: the agent tree on the
+// left, a form for the selected node on the right and, below the form, a
+// live preview of the file the node generates. It edits project in place and
+// hands it to save on ctrl+s.
+type Editor struct {
+	project *model.Project
+	save    func(*model.Project) error
+	gen     *generator.Generator
+
+	focus    focus
+	selected int
+	field    int
+
+	input textinput.Model
+	area  textarea.Model
+	// fieldErr is the error of the last edit of field fieldErrAt of node
+	// fieldErrNode, shown next to it until the field is edited again.
+	fieldErr     error
+	fieldErrNode int
+	fieldErrAt   int
+
+	// invalid is the project's validation error, nil when it can be saved.
+	invalid error
+	preview viewport.Model
+	title   string
+
+	// confirm is a yes/no question waiting for an answer, and onConfirm runs
+	// when the answer is yes.
+	confirm   string
+	onConfirm func() tea.Cmd
+
+	status string
+	dirty  bool
+	saved  bool
+
+	width, height int
+}
+
+func New(project *model.Project, save func(*model.Project) error) *Editor {
+	e := &Editor{
+		project: project,
+		save:    save,
+		// The preview renders on every keystroke that changes the project;
+		// the tokenizer keeps it fast, and create still checks with python3.
+		gen:     generator.NewGeneratorWithChecker(pysyntax.TokenChecker{}),
+		input:   textinput.New(),
+		area:    textarea.New(),
+		preview: viewport.New(80, 10),
+	}
+	e.area.ShowLineNumbers = false
+	e.input.Prompt = ""
+	e.changed()
+	e.dirty = false
+	return e
+}
+
+// Saved reports whether the project was saved at least once.
+func (e *Editor) Saved() bool {
+	return e.saved
+}
+
+func (e *Editor) Init() tea.Cmd {
+	return nil
+}
+
+func (e *Editor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		e.width, e.height = msg.Width, msg.Height
+		e.layout()
+		return e, nil
+	case tea.KeyMsg:
+		return e, e.key(msg)
+	}
+	return e, nil
+}
+
+func (e *Editor) key(msg tea.KeyMsg) tea.Cmd {
+	if e.focus == focusEdit {
+		return e.editKey(msg)
+	}
+
+	if e.confirm != "" {
+		run := e.onConfirm
+		e.confirm, e.onConfirm = "", nil
+		if msg.String() == "y" {
+			return run()
+		}
+		e.status = "Cancelled."
+		return nil
+	}
+
+	e.status = ""
+	switch msg.String() {
+	case "ctrl+c":
+		return e.quit()
+	case "ctrl+s":
+		e.write()
+		return nil
+	case "pgdown":
+		e.preview.ViewDown()
+		return nil
+	case "pgup":
+		e.preview.ViewUp()
+		return nil
+	}
+
+	if e.focus == focusForm {
+		e.formKey(msg)
+		return nil
+	}
+	return e.treeKey(msg)
+}
+
+func (e *Editor) treeKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "q":
+		return e.quit()
+	case "up", "k":
+		e.selectNode(e.selected - 1)
+	case "down", "j":
+		e.selectNode(e.selected + 1)
+	case "enter", "tab", "right", "l":
+		e.focus, e.field = focusForm, 0
+	case "a":
+		e.addAgent()
+	case "shift+up", "K":
+		e.moveAgent(-1)
+	case "shift+down", "J":
+		e.moveAgent(1)
+	case "d", "delete":
+		e.deleteAgent()
+	}
+	return nil
+}
+
+func (e *Editor) formKey(msg tea.KeyMsg) {
+	fields := e.fields()
+	f := fields[e.field]
+	switch msg.String() {
+	case "esc", "tab", "shift+tab":
+		e.focus = focusTree
+	case "up", "k":
+		e.field = max(e.field-1, 0)
+	case "down", "j":
+		e.field = min(e.field+1, len(fields)-1)
+	case "left", "h":
+		if f.kind == choiceField {
+			e.cycle(f, -1)
+		}
+	case "right", "l":
+		if f.kind == choiceField {
+			e.cycle(f, 1)
+		}
+	case "enter", " ":
+		switch f.kind {
+		case boolField:
+			e.apply(f, fmt.Sprint(f.get() != "true"))
+		case choiceField:
+			e.cycle(f, 1)
+		case textField:
+			e.input.SetValue(f.get())
+			e.input.CursorEnd()
+			e.input.Focus()
+			e.focus = focusEdit
+		case longTextField:
+			e.area.SetValue(f.get())
+			e.area.Focus()
+			e.focus = focusEdit
+		}
+	}
+}
+
+// editKey handles keys while a text field is being edited: enter applies a
+// line and esc cancels it; in a text area enter starts a new line and esc
+// applies the text.
+func (e *Editor) editKey(msg tea.KeyMsg) tea.Cmd {
+	f := e.fields()[e.field]
+	var cmd tea.Cmd
+	if f.kind == longTextField {
+		if msg.String() == "esc" {
+			e.area.Blur()
+			e.focus = focusForm
+			e.apply(f, e.area.Value())
+			return nil
+		}
+		e.area, cmd = e.area.Update(msg)
+		return cmd
+	}
+
+	switch msg.String() {
+	case "enter":
+		if e.apply(f, e.input.Value()) {
+			e.input.Blur()
+			e.focus = focusForm
+		}
+		return nil
+	case "esc":
+		e.input.Blur()
+		e.focus = focusForm
+		e.fieldErr = nil
+		return nil
+	}
+	e.input, cmd = e.input.Update(msg)
+	return cmd
+}
+
+// apply sets the value of field f of the selected node and reports whether
+// the field accepted it.
+func (e *Editor) apply(f field, value string) bool {
+	if value == f.get() {
+		e.fieldErr = nil
+		return true
+	}
+	if err := f.set(value); err != nil {
+		e.fieldErr, e.fieldErrNode, e.fieldErrAt = err, e.selected, e.field
+		return false
+	}
+	e.fieldErr = nil
+	e.changed()
+	// Changing a pattern or an agent type adds or removes fields.
+	e.field = min(e.field, len(e.fields())-1)
+	return true
+}
+
+func (e *Editor) cycle(f field, step int) {
+	current := 0
+	for i, option := range f.options {
+		if option == f.get() {
+			current = i
+		}
+	}
+	e.apply(f, f.options[(current+step+len(f.options))%len(f.options)])
+}
+
+func (e *Editor) fields() []field {
+	switch e.selected {
+	case projectNode:
+		return projectFields(e.project)
+	case orchestratorNode:
+		return orchestratorFields(e.project.Orchestrator)
+	default:
+		return agentFields(e.project.Orchestrator, e.selected-firstAgent)
+	}
+}
+
+func (e *Editor) nodes() int {
+	return firstAgent + len(e.project.Orchestrator.SubAgents)
+}
+
+func (e *Editor) selectNode(node int) {
+	if node < 0 || node >= e.nodes() || node == e.selected {
+		return
+	}
+	e.selected, e.field = node, 0
+	e.refreshPreview()
+	e.preview.GotoTop()
+}
+
+// addAgent inserts an LLM sub-agent after the selected one, or at the end
+// when no sub-agent is selected.
+func (e *Editor) addAgent() {
+	orchestrator := e.project.Orchestrator
+	name := ""
+	for n := len(orchestrator.SubAgents) + 1; ; n++ {
+		name = fmt.Sprintf("Agent%d", n)
+		if prompt.ValidateAgentName(name, agentNames(orchestrator, -1)...) == nil && name != orchestrator.Name {
+			break
+		}
+	}
+	agent := model.NewAgent(name, model.AgentTypeLLM, "", "", model.DefaultModel)
+
+	at := len(orchestrator.SubAgents)
+	if e.selected >= firstAgent {
+		at = e.selected - firstAgent + 1
+	}
+	orchestrator.SubAgents = append(orchestrator.SubAgents[:at], append([]*model.Agent{agent}, orchestrator.SubAgents[at:]...)...)
+	e.selected, e.field = at+firstAgent, 0
+	e.changed()
+	e.status = fmt.Sprintf("Added %s; press enter to edit it.", name)
+}
+
+func (e *Editor) moveAgent(step int) {
+	agents := e.project.Orchestrator.SubAgents
+	from := e.selected - firstAgent
+	to := from + step
+	if from < 0 || to < 0 || to >= len(agents) {
+		return
+	}
+	agents[from], agents[to] = agents[to], agents[from]
+	e.selected = to + firstAgent
+	e.changed()
+}
+
+func (e *Editor) deleteAgent() {
+	if e.selected < firstAgent {
+		return
+	}
+	i := e.selected - firstAgent
+	e.confirm = fmt.Sprintf("Delete %s? (y/n)", e.project.Orchestrator.SubAgents[i].Name)
+	e.onConfirm = func() tea.Cmd {
+		orchestrator := e.project.Orchestrator
+		name := orchestrator.SubAgents[i].Name
+		orchestrator.SubAgents = append(orchestrator.SubAgents[:i], orchestrator.SubAgents[i+1:]...)
+		e.selected = min(e.selected, e.nodes()-1)
+		e.changed()
+		e.status = fmt.Sprintf("Deleted %s.", name)
+		return nil
+	}
+}
+
+func (e *Editor) quit() tea.Cmd {
+	if !e.dirty {
+		return tea.Quit
+	}
+	e.confirm = "Quit without saving? (y/n)"
+	e.onConfirm = func() tea.Cmd { return tea.Quit }
+	return nil
+}
+
+func (e *Editor) write() {
+	if e.invalid != nil {
+		e.status = "Not saved: fix the validation error first."
+		return
+	}
+	if err := e.save(e.project); err != nil {
+		e.status = fmt.Sprintf("Not saved: %v", err)
+		return
+	}
+	e.dirty, e.saved = false, true
+	e.status = fmt.Sprintf("Saved %s.", spec.ManifestName)
+}
+
+// changed revalidates the project and regenerates the preview after an edit.
+func (e *Editor) changed() {
+	e.dirty = true
+	e.invalid = e.project.Validate()
+	if e.invalid == nil {
+		e.invalid = generator.ValidateIdentifiers(e.project)
+	}
+	e.refreshPreview()
+}
+
+// refreshPreview shows the file the selected node generates: the spec for
+// the project, agent.py for the orchestrator and the sub-agents.
+func (e *Editor) refreshPreview() {
+	if e.selected == projectNode {
+		e.title = spec.ManifestName
+		data, err := spec.Marshal(e.project)
+		if err != nil {
+			e.preview.SetContent(err.Error())
+			return
+		}
+		e.preview.SetContent(string(data))
+		return
+	}
+
+	name := e.project.Orchestrator.Name
+	if e.selected >= firstAgent {
+		name = e.project.Orchestrator.SubAgents[e.selected-firstAgent].Name
+	}
+	e.title = filepath.Join(naming.SnakeCase(name), "agent.py")
+
+	files, err := e.gen.RenderProject(e.project)
+	if err != nil {
+		e.preview.SetContent(fmt.Sprintf("Cannot render %s:\n%v", e.title, err))
+		return
+	}
+	for _, file := range files {
+		if file.Path == e.title {
+			e.preview.SetContent(file.Content)
+			return
+		}
+	}
+	e.preview.SetContent(fmt.Sprintf("%s is not generated.", e.title))
+}
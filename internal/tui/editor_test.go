@@ -0,0 +1,215 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/doji-co/agent-builder/internal/model"
+)
+
+func testProject() *model.Project {
+	orch := model.NewOrchestrator("ResearchCoordinator", model.PatternSequential, "Coordinates research", "gemini-2.5-flash")
+	orch.AddSubAgent(model.NewAgent("Researcher", model.AgentTypeLLM, "Research the topic", "research_data", "gemini-2.5-flash"))
+	orch.AddSubAgent(model.NewAgent("Writer", model.AgentTypeLLM, "Write based on {research_data}", "draft", "gemini-2.5-flash"))
+	return model.NewProject("research-assistant", orch)
+}
+
+// press sends keys to the editor: names such as "down" and "ctrl+s", or
+// text typed as runes.
+func press(e *Editor, keys ...string) tea.Cmd {
+	names := map[string]tea.KeyType{
+		"up": tea.KeyUp, "down": tea.KeyDown, "left": tea.KeyLeft, "right": tea.KeyRight,
+		"enter": tea.KeyEnter, "esc": tea.KeyEsc, "tab": tea.KeyTab, "backspace": tea.KeyBackspace,
+		"ctrl+s": tea.KeyCtrlS, "ctrl+c": tea.KeyCtrlC, "ctrl+u": tea.KeyCtrlU,
+	}
+	var cmd tea.Cmd
+	for _, k := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		if t, ok := names[k]; ok {
+			msg = tea.KeyMsg{Type: t}
+		}
+		_, cmd = e.Update(msg)
+	}
+	return cmd
+}
+
+func newEditor(t *testing.T) (*Editor, *[]*model.Project) {
+	t.Helper()
+	var saves []*model.Project
+	e := New(testProject(), func(p *model.Project) error {
+		saves = append(saves, p)
+		return nil
+	})
+	e.Update(tea.WindowSizeMsg{Width: 120, Height: 50})
+	return e, &saves
+}
+
+func subAgentNames(e *Editor) string {
+	var names []string
+	for _, agent := range e.project.Orchestrator.SubAgents {
+		names = append(names, agent.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestEditor_AddMoveDelete(t *testing.T) {
+	e, _ := newEditor(t)
+
+	press(e, "down", "down", "a")
+	if got := subAgentNames(e); got != "Researcher,Agent3,Writer" {
+		t.Fatalf("after add = %v, want Researcher,Agent3,Writer", got)
+	}
+	if e.selected != firstAgent+1 {
+		t.Errorf("selected = %d, want the new agent", e.selected)
+	}
+	if e.invalid == nil || !strings.Contains(e.invalid.Error(), "instruction is required") {
+		t.Errorf("invalid = %v, want the new agent's missing instruction", e.invalid)
+	}
+
+	press(e, "J")
+	if got := subAgentNames(e); got != "Researcher,Writer,Agent3" {
+		t.Errorf("after move down = %v", got)
+	}
+	press(e, "K", "K")
+	if got := subAgentNames(e); got != "Agent3,Researcher,Writer" {
+		t.Errorf("after move up = %v", got)
+	}
+	press(e, "K")
+	if got := subAgentNames(e); got != "Agent3,Researcher,Writer" {
+		t.Errorf("moving the first agent up = %v, want no change", got)
+	}
+
+	press(e, "d", "n")
+	if got := subAgentNames(e); got != "Agent3,Researcher,Writer" {
+		t.Errorf("after cancelled delete = %v", got)
+	}
+	press(e, "d")
+	if !strings.Contains(e.View(), "Delete Agent3? (y/n)") {
+		t.Error("View() does not ask to confirm the delete")
+	}
+	press(e, "y")
+	if got := subAgentNames(e); got != "Researcher,Writer" {
+		t.Errorf("after delete = %v, want Researcher,Writer", got)
+	}
+	if e.invalid != nil {
+		t.Errorf("invalid = %v, want a valid project again", e.invalid)
+	}
+}
+
+func TestEditor_EditFields(t *testing.T) {
+	e, _ := newEditor(t)
+	press(e, "down", "down", "enter")
+
+	// Rename Researcher to a name Writer already has.
+	press(e, "enter", "ctrl+u", "Writer", "enter")
+	if e.focus != focusEdit {
+		t.Fatal("a rejected name should keep the field open")
+	}
+	if e.fieldErr == nil || !strings.Contains(e.View(), e.fieldErr.Error()) {
+		t.Errorf("View() does not show the field error %v", e.fieldErr)
+	}
+	if e.project.Orchestrator.SubAgents[0].Name != "Researcher" {
+		t.Errorf("Name = %v, want the rejected name not applied", e.project.Orchestrator.SubAgents[0].Name)
+	}
+
+	press(e, "ctrl+u", "Scout", "enter")
+	if got := e.project.Orchestrator.SubAgents[0].Name; got != "Scout" {
+		t.Errorf("Name = %v, want Scout", got)
+	}
+	if e.fieldErr != nil || e.focus != focusForm {
+		t.Errorf("fieldErr = %v, focus = %v after a valid name", e.fieldErr, e.focus)
+	}
+	if e.title != "scout/agent.py" || !strings.Contains(e.View(), "Preview: scout/agent.py") {
+		t.Errorf("preview title = %v, want scout/agent.py", e.title)
+	}
+
+	// Type: llm -> custom removes the instruction fields.
+	press(e, "down", "right")
+	if got := e.project.Orchestrator.SubAgents[0].Type; got != model.AgentTypeCustom {
+		t.Errorf("Type = %v, want custom", got)
+	}
+	for _, f := range e.fields() {
+		if f.label == "Instruction" {
+			t.Error("custom agents should have no instruction field")
+		}
+	}
+	press(e, "left")
+
+	// Instruction: a multi-line text area applied with esc.
+	press(e, "down", "down", "enter", "enter", "Cite sources.", "esc")
+	if got := e.project.Orchestrator.SubAgents[0].Instruction; got != "Research the topic\nCite sources." {
+		t.Errorf("Instruction = %q", got)
+	}
+	if !strings.Contains(e.preview.View(), "Cite sources.") {
+		t.Error("preview does not show the new instruction")
+	}
+
+	// Memory toggles with enter.
+	for e.fields()[e.field].label != "Memory" {
+		press(e, "down")
+	}
+	press(e, "enter")
+	if !e.project.Orchestrator.SubAgents[0].Memory {
+		t.Error("Memory should be toggled on")
+	}
+}
+
+func TestEditor_ProjectForm(t *testing.T) {
+	e, _ := newEditor(t)
+	if !strings.Contains(e.preview.View(), "name: research-assistant") {
+		t.Errorf("project preview = %q, want the spec", e.preview.View())
+	}
+
+	press(e, "enter", "down", "right")
+	if e.project.ADKVersion == "1.0" {
+		t.Error("ADK version should cycle to the next version")
+	}
+	press(e, "up", "enter", "ctrl+u", "bad name", "enter")
+	if e.fieldErr == nil {
+		t.Error("a project name with a space should be rejected")
+	}
+	press(e, "esc")
+	if e.fieldErr != nil || e.project.Name != "research-assistant" {
+		t.Errorf("esc should cancel the edit: fieldErr = %v, name = %v", e.fieldErr, e.project.Name)
+	}
+}
+
+func TestEditor_Save(t *testing.T) {
+	e, saves := newEditor(t)
+
+	press(e, "down", "down", "a", "ctrl+s")
+	if len(*saves) != 0 {
+		t.Error("an invalid project should not be saved")
+	}
+	if !strings.Contains(e.View(), "Not saved") {
+		t.Error("View() does not say why the project was not saved")
+	}
+
+	press(e, "d", "y", "ctrl+s")
+	if len(*saves) != 1 || !e.Saved() || e.dirty {
+		t.Errorf("saves = %d, Saved() = %v, dirty = %v", len(*saves), e.Saved(), e.dirty)
+	}
+
+	e.save = func(*model.Project) error { return errors.New("disk full") }
+	press(e, "a", "d", "y", "ctrl+s")
+	if !strings.Contains(e.View(), "Not saved: disk full") {
+		t.Error("View() does not show the save error")
+	}
+}
+
+func TestEditor_Quit(t *testing.T) {
+	e, _ := newEditor(t)
+	if cmd := press(e, "q"); cmd == nil {
+		t.Error("q should quit an unchanged project")
+	}
+
+	press(e, "down", "down", "a")
+	if cmd := press(e, "q"); cmd != nil {
+		t.Error("q should ask before discarding changes")
+	}
+	if cmd := press(e, "y"); cmd == nil {
+		t.Error("y should quit")
+	}
+}
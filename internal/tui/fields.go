@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/doji-co/agent-builder/internal/adk"
+	"github.com/doji-co/agent-builder/internal/model"
+	"github.com/doji-co/agent-builder/internal/prompt"
+)
+
+type fieldKind int
+
+const (
+	textField fieldKind = iota
+	// longTextField is edited in a multi-line text area.
+	longTextField
+	choiceField
+	boolField
+)
+
+// field is one row of the form of a tree node. set applies a new value, or
+// returns the error to show next to the field and leaves the project as it
+// was.
+type field struct {
+	label   string
+	kind    fieldKind
+	options []string
+	get     func() string
+	set     func(string) error
+}
+
+func text(label string, value *string, validate func(string) error) field {
+	return field{label: label, kind: textField, get: func() string { return *value }, set: func(s string) error {
+		if validate != nil {
+			if err := validate(s); err != nil {
+				return err
+			}
+		}
+		*value = s
+		return nil
+	}}
+}
+
+func longText(label string, value *string) field {
+	f := text(label, value, nil)
+	f.kind = longTextField
+	return f
+}
+
+func flag(label string, value *bool) field {
+	return field{label: label, kind: boolField, get: func() string { return strconv.FormatBool(*value) }, set: func(s string) error {
+		*value = s == "true"
+		return nil
+	}}
+}
+
+// choice is a field whose value is one of options. T is one of the model's
+// string types.
+func choice[T ~string](label string, value *T, options []T) field {
+	names := make([]string, len(options))
+	for i, option := range options {
+		names[i] = string(option)
+	}
+	// Keep a value the options do not list, such as a model released after
+	// this version of agent-builder, selectable.
+	if !slices.Contains(names, string(*value)) {
+		names = append([]string{string(*value)}, names...)
+	}
+	return field{label: label, kind: choiceField, options: names, get: func() string { return string(*value) }, set: func(s string) error {
+		*value = T(s)
+		return nil
+	}}
+}
+
+func projectFields(project *model.Project) []field {
+	return []field{
+		text("Name", &project.Name, prompt.ValidateProjectName),
+		choice("ADK version", &project.ADKVersion, adk.Names()),
+		choice("Backend", &project.Backend, model.Backends),
+		choice("Packaging", &project.Packaging, model.Packagings),
+		choice("Session service", &project.Services.Session, model.SessionServices),
+		choice("Memory service", &project.Services.Memory, model.MemoryServices),
+		choice("Artifact service", &project.Services.Artifact, model.ArtifactServices),
+		flag("Example (main.py)", &project.AddExample),
+		flag("README", &project.AddReadme),
+		flag("Docker", &project.AddDocker),
+		flag("Evaluation set", &project.AddEval),
+		flag("Unit tests", &project.AddTests),
+	}
+}
+
+func orchestratorFields(orchestrator *model.Orchestrator) []field {
+	fields := []field{
+		text("Name", &orchestrator.Name, func(name string) error {
+			return prompt.ValidateAgentName(name, agentNames(orchestrator, -1)...)
+		}),
+		choice("Pattern", &orchestrator.Pattern, model.OrchestrationPatterns),
+		text("Description", &orchestrator.Description, nil),
+		choice("Model", &orchestrator.Model, model.AvailableModels),
+	}
+	if orchestrator.Pattern == model.PatternLLMCoordinated {
+		fields = append(fields, longText("Global instruction", &orchestrator.GlobalInstruction))
+	}
+	return fields
+}
+
+func agentFields(orchestrator *model.Orchestrator, i int) []field {
+	agent := orchestrator.SubAgents[i]
+	fields := []field{
+		text("Name", &agent.Name, func(name string) error {
+			return prompt.ValidateAgentName(name, agentNames(orchestrator, i)...)
+		}),
+		choice("Type", &agent.Type, model.AgentTypes),
+		text("Description", &agent.Description, nil),
+	}
+	if agent.Type == model.AgentTypeLLM {
+		fields = append(fields,
+			longText("Instruction", &agent.Instruction),
+			longText("Static instruction", &agent.StaticInstruction),
+			flag("Prompt file", &agent.PromptFile),
+		)
+	}
+	fields = append(fields,
+		text("Output key", &agent.OutputKey, nil),
+		choice("Model", &agent.Model, model.AvailableModels),
+	)
+	if agent.Type == model.AgentTypeLLM {
+		fields = append(fields, flag("Memory", &agent.Memory))
+		if orchestrator.Pattern == model.PatternLLMCoordinated {
+			fields = append(fields,
+				flag("No transfer to parent", &agent.DisallowTransferToParent),
+				flag("No transfer to peers", &agent.DisallowTransferToPeers),
+			)
+		}
+	}
+	return fields
+}
+
+// agentNames lists the names of the orchestrator and its sub-agents except
+// sub-agent skip, which a new name must not clash with.
+func agentNames(orchestrator *model.Orchestrator, skip int) []string {
+	var names []string
+	if skip >= 0 {
+		names = append(names, orchestrator.Name)
+	}
+	for i, agent := range orchestrator.SubAgents {
+		if i != skip {
+			names = append(names, agent.Name)
+		}
+	}
+	return names
+}
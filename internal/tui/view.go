@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	treeWidth  = 32
+	labelWidth = 22
+)
+
+var (
+	paneStyle     = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	activeStyle   = paneStyle.BorderForeground(lipgloss.Color("12"))
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	dimStyle      = lipgloss.NewStyle().Faint(true)
+)
+
+// layout sizes the panes to the terminal: the tree takes a fixed width, the
+// form as many lines as the longest form needs and the preview the rest.
+func (e *Editor) layout() {
+	right := max(e.width-treeWidth-4, 20)
+	e.input.Width = right - labelWidth - 4
+	e.area.SetWidth(right - 4)
+	e.area.SetHeight(8)
+	e.preview.Width = right - 4
+	e.preview.Height = max(e.height-e.formHeight()-8, 3)
+}
+
+func (e *Editor) formHeight() int {
+	// The project form has the most fields; a sub-agent's form with a text
+	// area open, its notes and errors can take a few lines more.
+	return len(projectFields(e.project)) + 16
+}
+
+func (e *Editor) View() string {
+	if e.width == 0 {
+		return "Loading..."
+	}
+
+	tree := paneStyle
+	form := paneStyle
+	if e.focus == focusTree {
+		tree = activeStyle
+	} else {
+		form = activeStyle
+	}
+	height := e.height - 4
+	right := e.width - treeWidth - 4
+
+	left := tree.Width(treeWidth).Height(height).Render(e.treeView())
+	formPane := form.Width(right).Height(e.formHeight()).Render(e.formView())
+	previewPane := paneStyle.Width(right).Render(titleStyle.Render("Preview: "+e.title) + "\n" + e.preview.View())
+
+	body := lipgloss.JoinHorizontal(lipgloss.Top, left, lipgloss.JoinVertical(lipgloss.Left, formPane, previewPane))
+	return body + "\n" + e.footer()
+}
+
+func (e *Editor) treeView() string {
+	orchestrator := e.project.Orchestrator
+	lines := []string{
+		fmt.Sprintf("Project %s", e.project.Name),
+		fmt.Sprintf("%s (%s)", orchestrator.Name, orchestrator.Pattern),
+	}
+	for i, agent := range orchestrator.SubAgents {
+		branch := "├─"
+		if i == len(orchestrator.SubAgents)-1 {
+			branch = "└─"
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s (%s)", branch, agent.Name, agent.Type))
+	}
+
+	for i, line := range lines {
+		if i == e.selected {
+			lines[i] = selectedStyle.Render(line)
+		}
+	}
+	return titleStyle.Render("Agents") + "\n" + strings.Join(lines, "\n")
+}
+
+func (e *Editor) formView() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(e.nodeName()) + "\n")
+
+	for i, f := range e.fields() {
+		label := fmt.Sprintf("%-*s", labelWidth, f.label)
+		value := displayValue(f)
+		if e.focus == focusEdit && i == e.field {
+			if f.kind == longTextField {
+				b.WriteString(label + "\n" + e.area.View() + "\n")
+				continue
+			}
+			value = e.input.View()
+		}
+		if e.focus != focusTree && i == e.field {
+			label = selectedStyle.Render(label)
+		}
+		b.WriteString(label + " " + value + "\n")
+		if e.fieldErr != nil && e.fieldErrNode == e.selected && e.fieldErrAt == i {
+			b.WriteString(errorStyle.Render(strings.Repeat(" ", labelWidth+1)+e.fieldErr.Error()) + "\n")
+		}
+	}
+
+	if e.selected > orchestratorNode {
+		b.WriteString(dimStyle.Render("\nSchemas, tools, callbacks, examples and generation settings\nare kept as they are; edit them in the spec file.") + "\n")
+	}
+	if e.invalid != nil {
+		b.WriteString("\n" + errorStyle.Render("Invalid: "+e.invalid.Error()))
+	}
+	return b.String()
+}
+
+func (e *Editor) nodeName() string {
+	switch e.selected {
+	case projectNode:
+		return "Project"
+	case orchestratorNode:
+		return "Orchestrator"
+	default:
+		return "Sub-agent " + e.project.Orchestrator.SubAgents[e.selected-firstAgent].Name
+	}
+}
+
+func displayValue(f field) string {
+	value := f.get()
+	switch f.kind {
+	case boolField:
+		if value == "true" {
+			return "[x]"
+		}
+		return "[ ]"
+	case choiceField:
+		if value == "" {
+			value = "(none)"
+		}
+		return "< " + value + " >"
+	case longTextField:
+		first, rest, multiline := strings.Cut(value, "\n")
+		if multiline && strings.TrimSpace(rest) != "" {
+			first += " ..."
+		}
+		if value == "" {
+			return dimStyle.Render("(empty)")
+		}
+		return first
+	}
+	return value
+}
+
+func (e *Editor) footer() string {
+	if e.confirm != "" {
+		return e.confirm
+	}
+	help := "up/down: select  a: add  K/J: move  d: delete  enter: edit  ctrl+s: save  q: quit"
+	switch e.focus {
+	case focusForm:
+		help = "up/down: field  enter: edit or toggle  left/right: change  esc: back to tree  ctrl+s: save"
+	case focusEdit:
+		help = "enter: apply  esc: cancel"
+		if e.fields()[e.field].kind == longTextField {
+			help = "esc: apply"
+		}
+	}
+	if e.dirty {
+		help = "[modified]  " + help
+	}
+	if e.status != "" {
+		return e.status + "\n" + dimStyle.Render(help)
+	}
+	return dimStyle.Render(help)
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2/core"
+	"golang.org/x/term"
+)
+
+var plain bool
+
+// SetPlain turns plain output on or off. Plain output has no colors and no
+// emoji, for CI logs, dumb terminals and screen readers.
+func SetPlain(on bool) {
+	plain = on
+	core.DisableColor = on
+}
+
+// Plain reports whether plain output is on.
+func Plain() bool {
+	return plain
+}
+
+// PlainRequested reports whether plain output should be on by default: when
+// NO_COLOR is set to anything (see no-color.org) or stdout is not a terminal.
+func PlainRequested() bool {
+	return os.Getenv("NO_COLOR") != "" || !IsTerminal(os.Stdout)
+}
+
+// IsTerminal reports whether f is connected to a terminal.
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// Text returns s, without emoji when plain output is on.
+func Text(s string) string {
+	if plain {
+		return StripEmoji(s)
+	}
+	return s
+}
+
+// Println is fmt.Println through Text.
+func Println(a ...interface{}) {
+	fmt.Print(Text(fmt.Sprintln(a...)))
+}
+
+// Printf is fmt.Printf through Text.
+func Printf(format string, a ...interface{}) {
+	fmt.Print(Text(fmt.Sprintf(format, a...)))
+}
+
+// StripEmoji removes emoji and pictographic symbols from s, with the space
+// that follows each one, so that "✓ Created" becomes "Created". Bullets,
+// rulers and box-drawing characters are kept.
+func StripEmoji(s string) string {
+	var b strings.Builder
+	skipSpace := false
+	for _, r := range s {
+		if isEmoji(r) {
+			skipSpace = true
+			continue
+		}
+		if skipSpace && r == ' ' {
+			skipSpace = false
+			continue
+		}
+		skipSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // pictographs, emoticons, transport, ...
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // miscellaneous symbols and dingbats
+		return true
+	case r == 0xFE0F || r == 0x200D: // emoji presentation selector, joiner
+		return true
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package ui
+
+import "testing"
+
+func TestStripEmoji(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"🤖 Welcome to Agent Builder!", "Welcome to Agent Builder!"},
+		{"\n💡 What is a model?", "\nWhat is a model?"},
+		{"   📝 Best practices:", "   Best practices:"},
+		{"✓ Created my-project", "Created my-project"},
+		{"✨ Done ✨", "Done "},
+		{"⚠️ careful", "careful"},
+		{"   • Sequential: one after another", "   • Sequential: one after another"},
+		{"├── agent.py", "├── agent.py"},
+		{"━━━━━━", "━━━━━━"},
+		{"Café 研究者", "Café 研究者"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := StripEmoji(tt.input); got != tt.want {
+				t.Errorf("StripEmoji(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	defer SetPlain(false)
+
+	SetPlain(false)
+	if got := Text("🚀 Next steps:"); got != "🚀 Next steps:" {
+		t.Errorf("Text() = %q, want emoji kept", got)
+	}
+
+	SetPlain(true)
+	if got := Text("🚀 Next steps:"); got != "Next steps:" {
+		t.Errorf("Text() = %q, want Next steps:", got)
+	}
+}